@@ -47,6 +47,36 @@ var (
 		"Notification worker queue capacity",
 	).Default("1000").Int()
 
+	latencyWindowSize = kingpin.Flag(
+		"stats.latency-window-size",
+		"The number of the recent sends of each notifier type used to calculate the latency",
+	).Default("1000").Int()
+
+	tlsSessionCacheSize = kingpin.Flag(
+		"tls.session-cache-size",
+		"The number of the TLS sessions cached for resumption, shared by the notifiers",
+	).Default("64").Int()
+
+	emailRetryInterval = kingpin.Flag(
+		"email.retry-interval",
+		"The interval to retry the emails failed transiently, 0 means the failed emails are not retried",
+	).Default("0s").Duration()
+
+	emailRetryQueuePath = kingpin.Flag(
+		"email.retry-queue-path",
+		"The file to persist the emails waiting to be retried, so they are resumed after restarting. They are kept in memory if it is empty",
+	).Default("").String()
+
+	emailRetryQueueSize = kingpin.Flag(
+		"email.retry-queue-size",
+		"The maximum number of the emails waiting to be retried, the oldest one is discarded if exceeded",
+	).Default("1000").Int()
+
+	emailRetryMaxAge = kingpin.Flag(
+		"email.retry-max-age",
+		"The emails failed for longer than this time are discarded, including the ones resumed after restarting",
+	).Default("1h").Duration()
+
 	nmns = kingpin.Flag(
 		"notification-manager-namespaces",
 		"notification manager namespaces",
@@ -117,10 +147,16 @@ func Main() int {
 		logger,
 		cfg,
 		&wh.Options{
-			ListenAddress:  *listenAddress,
-			WebhookTimeout: *webhookTimeout,
-			WorkerTimeout:  *wkrTimeout,
-			WorkerQueue:    *wkrQueue,
+			ListenAddress:       *listenAddress,
+			WebhookTimeout:      *webhookTimeout,
+			WorkerTimeout:       *wkrTimeout,
+			WorkerQueue:         *wkrQueue,
+			LatencyWindowSize:   *latencyWindowSize,
+			TLSSessionCacheSize: *tlsSessionCacheSize,
+			EmailRetryInterval:  *emailRetryInterval,
+			EmailRetryQueuePath: *emailRetryQueuePath,
+			EmailRetryQueueSize: *emailRetryQueueSize,
+			EmailRetryMaxAge:    *emailRetryMaxAge,
 		})
 
 	srvCh := make(chan error, 1)
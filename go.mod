@@ -7,12 +7,17 @@ require (
 	github.com/go-chi/chi v4.0.3+incompatible
 	github.com/go-kit/kit v0.9.0
 	github.com/go-logr/logr v0.1.0
+	github.com/golang/protobuf v1.3.2
 	github.com/json-iterator/go v1.1.8
+	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/mwitkow/go-conntrack v0.0.0-20161129095857-cc309e4a2223
 	github.com/onsi/ginkgo v1.11.0
 	github.com/onsi/gomega v1.8.1
 	github.com/prometheus/alertmanager v0.20.0
 	github.com/prometheus/common v0.7.0
+	github.com/yuin/goldmark v1.4.0
+	golang.org/x/net v0.0.0-20191004110552-13f9640d40b9
+	google.golang.org/grpc v1.23.1
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 	k8s.io/api v0.17.2
 	k8s.io/apimachinery v0.17.2
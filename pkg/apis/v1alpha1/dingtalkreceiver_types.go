@@ -18,12 +18,37 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
 )
 
 // DingTalkReceiverSpec defines the desired state of DingTalkReceiver
 type DingTalkReceiverSpec struct {
 	// WebhookConfig to be selected for this receiver
 	DingTalkConfigSelector *metav1.LabelSelector `json:"dingTalkConfigSelector,omitempty"`
+	// The maximum length of the annotation values in the notifications of this receiver, the longer values will be truncated.
+	// It applies in addition to the global `annotationMaxLength`. 0 means no limit.
+	AnnotationMaxLength int `json:"annotationMaxLength,omitempty"`
+	// Whether to add the link to the source of the alerts to the messages, it overrides the global `sourceLink.enabled`.
+	SourceLink *bool `json:"sourceLink,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The sub-templates which may not be defined in the templates of this receiver, in the form of glob patterns
+	// such as `email.custom.*`. A missing optional sub-template renders empty with a warning instead of failing
+	// the whole message, the sub-templates not matched are still required.
+	OptionalTemplates []string `json:"optionalTemplates,omitempty"`
+	// The annotations shown in the messages in order, with the display names.
+	// The annotations not listed are hidden, all the annotations are shown if it is not set.
+	AnnotationSection []AnnotationField `json:"annotationSection,omitempty"`
+	// The accent of each alert in the messages, derived from the fingerprint of the alert,
+	// so the same alert has the same accent in every notification.
+	AlertAccent *AlertAccent `json:"alertAccent,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
 }
 
 // DingTalkReceiverStatus defines the observed state of DingTalkReceiver
@@ -0,0 +1,64 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchConfigSpec defines the desired state of ElasticsearchConfig
+type ElasticsearchConfigSpec struct {
+	// The addresses of the Elasticsearch or OpenSearch nodes, in standard URL form, e.g. `https://es.example.com:9200`.
+	// The nodes will be tried in order until the bulk request is accepted by one of them.
+	Hosts []string `json:"hosts"`
+	// The HTTP client config used to connect to the nodes, including the authentication and the TLS config.
+	HTTPConfig *HTTPClientConfig `json:"httpConfig,omitempty"`
+	// The prefix of the index name, default is `alerts`.
+	Index string `json:"index,omitempty"`
+	// The date suffix of the index name in the form of golang time layout, the date is the time the alerts are indexed.
+	// For example, `2006.01` will generate the index name like `alerts-2024.01`. Default is `2006.01`.
+	// Set it to `-` to disable the date suffix.
+	IndexDateLayout string `json:"indexDateLayout,omitempty"`
+}
+
+// ElasticsearchConfigStatus defines the observed state of ElasticsearchConfig
+type ElasticsearchConfigStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchConfig is the Schema for the elasticsearchconfigs API
+type ElasticsearchConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchConfigSpec   `json:"spec,omitempty"`
+	Status ElasticsearchConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchConfigList contains a list of ElasticsearchConfig
+type ElasticsearchConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchConfig{}, &ElasticsearchConfigList{})
+}
@@ -0,0 +1,65 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// ElasticsearchReceiverSpec defines the desired state of ElasticsearchReceiver
+type ElasticsearchReceiverSpec struct {
+	// ElasticsearchConfig to be selected for this receiver
+	ElasticsearchConfigSelector *metav1.LabelSelector `json:"elasticsearchConfigSelector,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
+}
+
+// ElasticsearchReceiverStatus defines the observed state of ElasticsearchReceiver
+type ElasticsearchReceiverStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchReceiver is the Schema for the elasticsearchreceivers API
+type ElasticsearchReceiver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticsearchReceiverSpec   `json:"spec,omitempty"`
+	Status ElasticsearchReceiverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticsearchReceiverList contains a list of ElasticsearchReceiver
+type ElasticsearchReceiverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticsearchReceiver `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticsearchReceiver{}, &ElasticsearchReceiverList{})
+}
@@ -27,7 +27,8 @@ type EmailConfigSpec struct {
 	From string `json:"from"`
 	// The address of the SMTP server.
 	SmartHost HostPort `json:"smartHost"`
-	// The hostname to use when identifying to the SMTP server.
+	// The hostname to use when identifying to the SMTP server, it is the local name sent in EHLO/HELO.
+	// Default is `localhost`, some relays reject it and need the real hostname of the client.
 	Hello *string `json:"hello,omitempty"`
 	// The username for CRAM-MD5, LOGIN and PLAIN authentications.
 	AuthUsername *string `json:"authUsername,omitempty"`
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
 )
 
 // EmailReceiverSpec defines the desired state of EmailReceiver
@@ -26,6 +27,77 @@ type EmailReceiverSpec struct {
 	To []string `json:"to"`
 	// EmailConfig to be selected for this receiver
 	EmailConfigSelector *metav1.LabelSelector `json:"emailConfigSelector,omitempty"`
+	// The maximum length of the annotation values in the notifications of this receiver, the longer values will be truncated.
+	// It applies in addition to the global `annotationMaxLength`. 0 means no limit.
+	AnnotationMaxLength int `json:"annotationMaxLength,omitempty"`
+	// The labels to build the identity of the email subject, in order, such as `cluster` and `service`.
+	// The subject will be like `[FIRING:1, RESOLVED:0] cluster=xxx service=xxx`, the body is not affected.
+	// If it is not set, the subject template will be used.
+	SubjectLabels []string `json:"subjectLabels,omitempty"`
+	// Whether to add the link to the source of the alerts to the messages, it overrides the global `sourceLink.enabled`.
+	SourceLink *bool `json:"sourceLink,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The sub-templates which may not be defined in the templates of this receiver, in the form of glob patterns
+	// such as `email.custom.*`. A missing optional sub-template renders empty with a warning instead of failing
+	// the whole message, the sub-templates not matched are still required.
+	OptionalTemplates []string `json:"optionalTemplates,omitempty"`
+	// The annotations shown in the messages in order, with the display names.
+	// The annotations not listed are hidden, all the annotations are shown if it is not set.
+	AnnotationSection []AnnotationField `json:"annotationSection,omitempty"`
+	// The icons prepended to the subject to show the status of the alerts, such as `🔥` for firing.
+	// The subject has no icon if it is not set.
+	SubjectIcons *SubjectIcons `json:"subjectIcons,omitempty"`
+	// Whether to render each alert as a collapsible section in the default html template, with the alert name and
+	// the severity as the summary. The clients not supporting `<details>` show the sections expanded.
+	CollapsibleAlerts bool `json:"collapsibleAlerts,omitempty"`
+	// The annotations whose values are Markdown, such as `description`, they are converted to sanitized html
+	// in the default html template. The scripts and the event handlers are stripped. They are shown as is if it is not set.
+	MarkdownAnnotations []string `json:"markdownAnnotations,omitempty"`
+	// The Content-Transfer-Encoding of the body, `auto`, `7bit`, `8bit`, `quoted-printable` or `base64`, default is `auto`,
+	// which sends the body as `7bit` if it is ASCII, `8bit` if the server supports 8BITMIME, otherwise `quoted-printable`.
+	// The body is sent as `quoted-printable` if it can not be sent in the encoding, such as `8bit` to a server
+	// not supporting 8BITMIME, or `7bit` with non-ASCII characters.
+	ContentTransferEncoding string `json:"contentTransferEncoding,omitempty"`
+	// The envelope-from of the emails, the address sent in the SMTP MAIL FROM command, which becomes the Return-Path
+	// the bounces are sent to. The From header is not changed. The `from` of the EmailConfig is used if it is not set.
+	EnvelopeFrom string `json:"envelopeFrom,omitempty"`
+	// The custom headers of the emails, such as `X-Team` from the annotation `team`, for the mail-processing rules.
+	Headers []EmailHeader `json:"headers,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
+}
+
+// EmailHeader is a custom header of the emails, the value is from a label or an annotation of the alerts,
+// or a static value. The CR and LF in the value are replaced with spaces.
+type EmailHeader struct {
+	// The name of the header, such as `X-Team`. `From`, `To`, `Cc`, `Bcc` and `Subject` can not be set.
+	Name string `json:"name"`
+	// The label whose value is the value of the header.
+	Label string `json:"label,omitempty"`
+	// The annotation whose value is the value of the header, it is used if the label is not set.
+	Annotation string `json:"annotation,omitempty"`
+	// The static value of the header, it is used if neither the label nor the annotation is set.
+	Value string `json:"value,omitempty"`
+	// What to do if the alerts have different values, `skip` does not set the header, `first` uses the value
+	// of the first alert, `join` joins the values with `, `. Default is `skip`.
+	OnConflict string `json:"onConflict,omitempty"`
+}
+
+// SubjectIcons are the icons of the statuses of the alerts, the subject of a group with both firing and resolved
+// alerts has both icons.
+type SubjectIcons struct {
+	// Whether to prepend the icons to the subject.
+	Enabled bool `json:"enabled,omitempty"`
+	// The icon of the firing alerts, default is `🔥`.
+	Firing string `json:"firing,omitempty"`
+	// The icon of the resolved alerts, default is `✅`.
+	Resolved string `json:"resolved,omitempty"`
 }
 
 // EmailReceiverStatus defines the observed state of EmailReceiver
@@ -0,0 +1,63 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GrpcConfigSpec defines the desired state of GrpcConfig
+type GrpcConfigSpec struct {
+	// The address of the gRPC server, e.g. `notification.example.com:9090`.
+	Target string `json:"target"`
+	// The full name of the method to call, default is `/notification.NotificationService/Notify`.
+	// The method must accept the `NotifyRequest` defined in `notification.proto`.
+	Method string `json:"method,omitempty"`
+	// The token sent as a bearer token in the `authorization` metadata.
+	Token *v1.SecretKeySelector `json:"token,omitempty"`
+	// TLSConfig to use to connect to the gRPC server, the connection is insecure if it is not set.
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// GrpcConfigStatus defines the observed state of GrpcConfig
+type GrpcConfigStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// GrpcConfig is the Schema for the grpcconfigs API
+type GrpcConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrpcConfigSpec   `json:"spec,omitempty"`
+	Status GrpcConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GrpcConfigList contains a list of GrpcConfig
+type GrpcConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GrpcConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GrpcConfig{}, &GrpcConfigList{})
+}
@@ -87,6 +87,279 @@ type GlobalOptions struct {
 	// The name of the template to generate message.
 	// If the receiver dose not setup template, it will use this.
 	Template string `json:"template,omitempty"`
+	// The maximum number of the templates rendered concurrently, the renders over it wait in a queue.
+	// Default is the number of CPUs.
+	RenderConcurrency int `json:"renderConcurrency,omitempty"`
+	// The maximum length of an annotation value, the value longer than it will be truncated.
+	// 0 means no limit.
+	AnnotationMaxLength int `json:"annotationMaxLength,omitempty"`
+	// The maximum size of the alerts in one notification, the alerts exceeding it will be dropped,
+	// and the number of the dropped alerts will be set to the common annotation `truncatedAlerts`.
+	// 0 means no limit.
+	AlertsMaxSize int `json:"alertsMaxSize,omitempty"`
+	// Whether to compute the reason of the notification, `initial-firing`, `update-firing`, `resolved`, `repeat`
+	// or `stale` for the alerts assumed to be resolved as they went quiet, it will be set to the common annotation `notificationReason` and the email header `X-Notification-Reason`.
+	NotificationReason bool `json:"notificationReason,omitempty"`
+	// If a firing group is not updated for longer than this time without being resolved,
+	// a synthetic resolved notification will be sent, with the common annotation `stale` set to `true`.
+	// 0 means do not track the stale groups.
+	StaleTimeout time.Duration `json:"staleTimeout,omitempty"`
+	// The name of the template to generate the message of the stale notification,
+	// the message will be set to the common annotation `staleMessage`.
+	StaleTemplate string `json:"staleTemplate,omitempty"`
+	// The label or annotation to enable or disable the notification of an alert, default is `notifications`.
+	// The alerts whose value is one of `disabled`, `false`, `off`, `no` and `0` will not be notified.
+	NotificationLabel string `json:"notificationLabel,omitempty"`
+	// The link to the source of the alerts, it is generated from the GeneratorURL of the alerts.
+	SourceLink *SourceLink `json:"sourceLink,omitempty"`
+	// Merge the groups with the same value of a label received within a window into one notification.
+	Coalesce *Coalesce `json:"coalesce,omitempty"`
+	// Whether to warm up the notifiers when the receivers are added or changed, such as fetching the access tokens
+	// of wechat and dingtalk, so that the first notification can be sent immediately.
+	// The failed warm-up will be retried in the background.
+	WarmUp bool `json:"warmUp,omitempty"`
+	// Suppress the notification of a group which is the same as the last one except the volatile labels and annotations.
+	Dedup *Dedup `json:"dedup,omitempty"`
+	// Whether the changes of the receivers and their configs take effect only after applied by `POST /receivers/apply`,
+	// the pending changes can be reviewed by `GET /receivers/diff`. The changes of the options take effect immediately.
+	ManualApply bool `json:"manualApply,omitempty"`
+	// Route the alerts to the receivers named in a label of the alerts, besides or instead of the receivers of the namespace.
+	ReceiversLabel *ReceiversLabel `json:"receiversLabel,omitempty"`
+	// Select the template of the messages by a label of the alerts, such as `template=certificate-expiry`.
+	TemplateSelector *TemplateSelector `json:"templateSelector,omitempty"`
+	// Add the metadata of the Kubernetes objects of the alerts to the annotations of the alerts, such as the owner and the team.
+	Enrichment *Enrichment `json:"enrichment,omitempty"`
+	// Mark the repeat notifications of the groups acknowledged by `POST /alerts/ack`, instead of notifying them as new.
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+	// Which of the labels and the annotations wins in `.CommonContext` and `.Context` of the templates when they have
+	// the same name, `annotations` or `labels`. Default is `annotations`.
+	ContextPrecedence string `json:"contextPrecedence,omitempty"`
+	// The flow control of each target, such as an email address or a slack channel. It is shared by all the receivers
+	// sending to the target, so a target in many receivers is not flooded. It is disabled if the threshold is 0.
+	TargetThrottle *Throttle `json:"targetThrottle,omitempty"`
+	// Suppress the symptom alerts while their cause alert is firing, such as the alerts caused by `APIServerDown`.
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+	// Collapse the labels and the annotations with too many distinct values in the messages of the chat notifiers,
+	// DingTalk, Slack and WeChat.
+	CardinalityGuard *CardinalityGuard `json:"cardinalityGuard,omitempty"`
+	// Notify the administrators when a receiver is ignored because of its config, such as a receiver without a config.
+	ConfigErrorNotification *ConfigErrorNotification `json:"configErrorNotification,omitempty"`
+	// Notify the administrators when a receiver degraded by the failed warm-up recovers, with how long it was degraded.
+	RecoveryNotification *RecoveryNotification `json:"recoveryNotification,omitempty"`
+	// Record the routing decisions of the recent alerts, returned by `GET /debug/routing`,
+	// to find out why an alert is not notified.
+	RoutingTrace *RoutingTrace `json:"routingTrace,omitempty"`
+	// Stop sending to a target after its consecutive failures, such as an email address rejected by the server,
+	// so a bad target does not consume the retries. It is disabled if the threshold is 0.
+	TargetQuarantine *TargetQuarantine `json:"targetQuarantine,omitempty"`
+	// Normalize the labels of the alerts when they are received, before they are routed, filtered and rendered,
+	// such as `Severity=Critical` to `severity=critical`, so the selectors and the templates see the canonical labels.
+	LabelNormalization *LabelNormalization `json:"labelNormalization,omitempty"`
+}
+
+// LabelNormalization is the config of normalizing the labels of the alerts, the group labels and the common labels.
+// When two labels are the same after normalized, the one already in the canonical form wins.
+type LabelNormalization struct {
+	// Whether to lowercase the names of the labels.
+	LowercaseNames bool `json:"lowercaseNames,omitempty"`
+	// The labels whose values are lowercased, such as `severity`. The names are matched after normalized.
+	LowercaseValues []string `json:"lowercaseValues,omitempty"`
+	// Whether to trim the leading and trailing whitespace of the names and the values of the labels.
+	TrimSpace bool `json:"trimSpace,omitempty"`
+}
+
+// TargetQuarantine is the config of quarantining the targets failing repeatedly, only the email addresses rejected
+// by the server are tracked now. The target is sent to again after the cooldown, or when its receiver is changed.
+type TargetQuarantine struct {
+	// The number of the consecutive failures to quarantine a target.
+	Threshold int `json:"threshold,omitempty"`
+	// How long the target is quarantined, default is 1h.
+	Cooldown time.Duration `json:"cooldown,omitempty"`
+	// The name of the receivers which the notifications about the quarantined targets are sent to,
+	// such as the receivers of the administrators. No notification is sent if it is not set.
+	Receiver string `json:"receiver,omitempty"`
+}
+
+// RoutingTrace is the config of recording the routing decision of each alert, the receivers it matches,
+// the reason it is dropped, such as a suppression or the notification label, and the notifiers it is dispatched to.
+type RoutingTrace struct {
+	// Whether to record the routing decisions.
+	Enabled bool `json:"enabled,omitempty"`
+	// The number of the recent decisions kept, default is 1000.
+	Size int `json:"size,omitempty"`
+}
+
+// ConfigErrorNotification is the config of the notifications about the config errors of the receivers.
+// The secrets in the errors are masked.
+type ConfigErrorNotification struct {
+	// The name of the receivers which the notifications are sent to, such as the receivers of the administrators.
+	Receiver string `json:"receiver"`
+	// The minimum interval between the notifications about the same error of a receiver, default is 1h.
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// AlertAccent is the config of the accent of each alert in the chat messages, the emoji before the alert
+// in the text, such as `{{ .Accent.Emoji }}` in the templates, and the color of the alert in the slack Block Kit layout.
+// The fingerprint of the alert is hashed to an accent of the palette, so responders recognize the recurring alerts.
+type AlertAccent struct {
+	// Whether to add the accents.
+	Enabled bool `json:"enabled,omitempty"`
+	// The accents the fingerprints are hashed to, default is 8 colors with the square emojis of the colors.
+	Palette []Accent `json:"palette,omitempty"`
+	// The accents of the alerts of the severities, such as `critical`, they override the accents of the fingerprints.
+	Severities map[string]Accent `json:"severities,omitempty"`
+}
+
+// Accent is a color and an emoji.
+type Accent struct {
+	// The color in hex, such as `#E01E5A`.
+	Color string `json:"color,omitempty"`
+	// The emoji, such as `🟥`.
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// RecoveryNotification is the config of the notifications about the degraded receivers which recover.
+type RecoveryNotification struct {
+	// The name of the receivers which the notifications are sent to, such as the receivers of the administrators.
+	Receiver string `json:"receiver"`
+	// The minimum interval between the notifications about the same receiver, so a flapping receiver
+	// does not flood the administrators, default is 1h.
+	Interval time.Duration `json:"interval,omitempty"`
+}
+
+// CardinalityGuard is the config of collapsing a label or an annotation which has more distinct values
+// in the alerts of a notification than `maxValues`, such as a unique `pod` of each alert.
+// The alerts which differ only in such keys are merged into one, and the values are shown as
+// `N distinct values (showing first K): v1, v2, ...`.
+type CardinalityGuard struct {
+	// The maximum number of the distinct values of a key rendered as is, 0 means do not collapse.
+	MaxValues int `json:"maxValues,omitempty"`
+	// The number of the values shown when collapsed, default is 5.
+	ShowValues int `json:"showValues,omitempty"`
+}
+
+// Acknowledgement is the config of the acknowledged groups. A group is acknowledged until it is resolved,
+// its repeat notifications have the common annotations `acknowledgedBy` and `acknowledgedAt`.
+// The group is notified as usual if an alert starts firing after the acknowledgement.
+type Acknowledgement struct {
+	// The prefix of the email subject of an acknowledged group, default is `[ACKED]`.
+	SubjectPrefix string `json:"subjectPrefix,omitempty"`
+	// The types of the receivers the notifications of an acknowledged group are sent to, such as `email`,
+	// the other receivers are skipped, so the pagers are not paged again. All the receivers get them if it is not set.
+	DowngradeTo []string `json:"downgradeTo,omitempty"`
+}
+
+// Enrichment is the config of adding the metadata of the Kubernetes objects of an alert to the annotations of the alert.
+// The existing annotations of the alert are not overwritten, and the alert is sent as is if the object can not be found.
+type Enrichment struct {
+	// How long the metadata of the objects is cached, default is 1m.
+	CacheTTL time.Duration `json:"cacheTTL,omitempty"`
+	// Add the metadata of the workload of the alert, which is found by the `namespace` label and the first workload
+	// label of the alert, `pod`, `deployment`, `statefulset` or `daemonset`.
+	Workload *Enricher `json:"workload,omitempty"`
+	// Add the metadata of the namespace of the alert, which is found by the `namespace` label.
+	// It is applied after the workload, so the metadata of the workload wins if they have the same key.
+	Namespace *Enricher `json:"namespace,omitempty"`
+}
+
+// Enricher is the config of adding the metadata of a kind of objects to the annotations of the alerts.
+type Enricher struct {
+	// Whether to add the owner references of the object, as the annotation `owner` in form of `Kind/name`,
+	// separated by comma.
+	OwnerReferences bool `json:"ownerReferences,omitempty"`
+	// The labels of the object to add, such as `app.kubernetes.io/version`, the annotations added have the same keys.
+	Labels []string `json:"labels,omitempty"`
+	// The annotations of the object to add, such as `team`.
+	Annotations []string `json:"annotations,omitempty"`
+	// What to do if the object can not be looked up, `open` sends the alerts without the metadata of this enricher,
+	// `closed` holds the notification and reports the error. Default is `open`.
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// TemplateSelector is the config of selecting the template by a label of the alerts, the label value `name` selects
+// the template `<name>.html` for email and `<name>.text` for wechat, slack and dingtalk, defined in the template files.
+// The alerts selecting no template or an undefined one use the template of the receiver type, then the global template.
+type TemplateSelector struct {
+	// The label whose value is the name of the template, default is `template`.
+	Label string `json:"label,omitempty"`
+	// How to send a group whose alerts select different templates, `split` sends the alerts selecting each template
+	// in a separate notification, `first` uses the template of the first alert, `majority` uses the template
+	// selected by the most alerts. Default is `split`.
+	Mode string `json:"mode,omitempty"`
+}
+
+// ReceiversLabel is the config of routing the alerts by a label, such as `receivers=oncall-email,team-slack`.
+// The receivers are named by the names of the receiver resources, the unknown names are skipped.
+// An alert can only be routed to the global receivers and the receivers of the tenants of its namespace.
+type ReceiversLabel struct {
+	// The label whose value is the comma-separated names of the receivers, default is `receivers`.
+	Label string `json:"label,omitempty"`
+	// `merge` sends the alerts to the named receivers besides the receivers of the namespace,
+	// `override` sends them only to the named receivers. Default is `merge`.
+	Mode string `json:"mode,omitempty"`
+}
+
+// Dedup is the config of suppressing the duplicate notifications, such as the group re-sent by alertmanager
+// because the `value` annotation of an alert changed at every evaluation.
+type Dedup struct {
+	// Whether to suppress the duplicate notifications.
+	Enabled bool `json:"enabled,omitempty"`
+	// The labels and annotations ignored when comparing the notifications, such as `value`.
+	IgnoredKeys []string `json:"ignoredKeys,omitempty"`
+	// The duplicate notification is still sent if the last one of the group was sent longer than this time ago,
+	// so the reminders are kept. 0 means the duplicate notifications are always suppressed.
+	RepeatInterval time.Duration `json:"repeatInterval,omitempty"`
+}
+
+// Suppression suppresses the symptom alerts while a cause alert is firing and within the grace period after it is resolved,
+// so the storm of alerts caused by a single failure and its recovery is not notified.
+// The cause alerts are remembered when they are notified, the symptom alerts may be in other groups.
+type Suppression struct {
+	// The labels of the cause alerts, such as `alertname: APIServerDown`.
+	Cause *metav1.LabelSelector `json:"cause"`
+	// The labels of the symptom alerts suppressed when a cause alert is firing, the cause alerts are not suppressed.
+	Symptom *metav1.LabelSelector `json:"symptom"`
+	// The symptom alerts are still suppressed within this time after the cause alert is resolved.
+	GracePeriod time.Duration `json:"gracePeriod,omitempty"`
+	// The cause alert is treated as resolved if it is not notified as firing within this time, in case its resolved
+	// notification is lost. It should be longer than the repeat interval of alertmanager, default is 4h.
+	CauseTTL time.Duration `json:"causeTTL,omitempty"`
+}
+
+// Coalesce is the config of merging the groups across namespaces, such as the same alert fired in many namespaces
+// because of a single root cause. The global receivers receive one merged notification,
+// the tenant receivers receive the groups of their namespaces.
+type Coalesce struct {
+	// The time to wait for the groups to merge, 0 means do not merge.
+	Window time.Duration `json:"window,omitempty"`
+	// The label to merge the groups by, default is `alertname`.
+	Label string `json:"label,omitempty"`
+	// The groups are sent before the window is over when the alerts of them reach this number, so a burst of alerts
+	// is sent promptly. The next groups wait for a new window. 0 means the groups are only sent when the window is over.
+	MaxAlerts int `json:"maxAlerts,omitempty"`
+}
+
+// SourceLink is the config of the link to the source of the alerts, such as the Prometheus expression.
+// The link is added to the messages of email, wechat, slack and dingtalk, the alerts without GeneratorURL have no link.
+type SourceLink struct {
+	// Whether to add the link to the messages, the receivers can override it by `sourceLink`.
+	Enabled bool `json:"enabled,omitempty"`
+	// The text of the link, default is `View in Prometheus`.
+	Text string `json:"text,omitempty"`
+	// The regular expression to match the GeneratorURL, the matched part will be replaced with `replacement`.
+	// It is used to replace an internal host with an externally reachable one, such as `^http://prometheus-k8s.kubesphere-monitoring-system.svc:9090`.
+	// The GeneratorURL referenced by the templates is replaced too.
+	Regex string `json:"regex,omitempty"`
+	// The replacement of the matched part, `$1` can be used to reference the capture group.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// AnnotationField is an annotation shown in the annotation section of the messages.
+type AnnotationField struct {
+	// The name of the annotation, such as `runbook_url`.
+	Name string `json:"name"`
+	// The name shown in the messages, such as `Runbook`. Default is the name of the annotation.
+	DisplayName string `json:"displayName,omitempty"`
 }
 
 type EmailOptions struct {
@@ -101,6 +374,14 @@ type EmailOptions struct {
 	Template string `json:"template,omitempty"`
 	// The name of the template to generate email subject
 	SubjectTemplate string `json:"subjectTemplate,omitempty"`
+	// The minimum interval between two sends to the same smart host, the emails will be paced to avoid greylisting.
+	// 0 means no spacing.
+	SendInterval time.Duration `json:"sendInterval,omitempty"`
+	// The maximum number of emails can be sent through the same smart host and sender in a day,
+	// the emails exceeding it will not be sent. 0 means no limit.
+	DailyQuota int `json:"dailyQuota,omitempty"`
+	// The time zone whose midnight the daily quota is reset at, such as `Asia/Shanghai`. Default is the local time zone.
+	QuotaTimeZone string `json:"quotaTimeZone,omitempty"`
 }
 
 type WechatOptions struct {
@@ -112,6 +393,10 @@ type WechatOptions struct {
 	MessageMaxSize int `json:"messageMaxSize,omitempty"`
 	// The time of token expired.
 	TokenExpires time.Duration `json:"tokenExpires,omitempty"`
+	// The mode to generate message when the status templates `<template>.firing` and `<template>.resolved` are defined,
+	// `section` renders the firing and resolved alerts in separate sections of one message,
+	// `split` renders them as separate messages. Default is `section`.
+	StatusTemplateMode string `json:"statusTemplateMode,omitempty"`
 }
 
 type SlackOptions struct {
@@ -120,6 +405,10 @@ type SlackOptions struct {
 	// The name of the template to generate slack message.
 	// If the global template is not set, it will use default.
 	Template string `json:"template,omitempty"`
+	// The mode to generate message when the status templates `<template>.firing` and `<template>.resolved` are defined,
+	// `section` renders the firing and resolved alerts in separate sections of one message,
+	// `split` renders them as separate messages. Default is `section`.
+	StatusTemplateMode string `json:"statusTemplateMode,omitempty"`
 }
 
 type WebhookOptions struct {
@@ -130,6 +419,32 @@ type WebhookOptions struct {
 	Template string `json:"template,omitempty"`
 }
 
+type ElasticsearchOptions struct {
+	// Notification Sending Timeout
+	NotificationTimeout *int32 `json:"notificationTimeout,omitempty"`
+	// The mode to index the alerts, `alert` indexes each alert as a document,
+	// `group` indexes the alerts in one notification as a document. Default is `alert`.
+	DocumentMode string `json:"documentMode,omitempty"`
+}
+
+type SplunkOptions struct {
+	// Notification Sending Timeout
+	NotificationTimeout *int32 `json:"notificationTimeout,omitempty"`
+	// The mode to send the alerts, `alert` sends each alert as an event,
+	// `group` sends the alerts in one notification as an event. Default is `alert`.
+	EventMode string `json:"eventMode,omitempty"`
+}
+
+type GrpcOptions struct {
+	// Notification Sending Timeout
+	NotificationTimeout *int32 `json:"notificationTimeout,omitempty"`
+}
+
+type PushgatewayOptions struct {
+	// Notification Sending Timeout
+	NotificationTimeout *int32 `json:"notificationTimeout,omitempty"`
+}
+
 // The config of flow control.
 type Throttle struct {
 	// The maximum calls in `Unit`.
@@ -157,15 +472,23 @@ type DingTalkOptions struct {
 	ChatBotThrottle *Throttle `json:"chatBotThrottle,omitempty"`
 	// The flow control fo conversation.
 	ConversationThrottle *Throttle `json:"conversationThrottle,omitempty"`
+	// The mode to generate message when the status templates `<template>.firing` and `<template>.resolved` are defined,
+	// `section` renders the firing and resolved alerts in separate sections of one message,
+	// `split` renders them as separate messages. Default is `section`.
+	StatusTemplateMode string `json:"statusTemplateMode,omitempty"`
 }
 
 type Options struct {
-	Global   *GlobalOptions   `json:"global,omitempty"`
-	Email    *EmailOptions    `json:"email,omitempty"`
-	Wechat   *WechatOptions   `json:"wechat,omitempty"`
-	Slack    *SlackOptions    `json:"slack,omitempty"`
-	Webhook  *WebhookOptions  `json:"webhook,omitempty"`
-	DingTalk *DingTalkOptions `json:"dingtalk,omitempty"`
+	Global        *GlobalOptions        `json:"global,omitempty"`
+	Email         *EmailOptions         `json:"email,omitempty"`
+	Wechat        *WechatOptions        `json:"wechat,omitempty"`
+	Slack         *SlackOptions         `json:"slack,omitempty"`
+	Webhook       *WebhookOptions       `json:"webhook,omitempty"`
+	DingTalk      *DingTalkOptions      `json:"dingtalk,omitempty"`
+	Elasticsearch *ElasticsearchOptions `json:"elasticsearch,omitempty"`
+	Splunk        *SplunkOptions        `json:"splunk,omitempty"`
+	Grpc          *GrpcOptions          `json:"grpc,omitempty"`
+	Pushgateway   *PushgatewayOptions   `json:"pushgateway,omitempty"`
 }
 
 // NotificationManagerStatus defines the observed state of NotificationManager
@@ -0,0 +1,59 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PushgatewayConfigSpec defines the desired state of PushgatewayConfig
+type PushgatewayConfigSpec struct {
+	// The URL of the Pushgateway, e.g. `http://pushgateway.monitoring:9091`.
+	URL string `json:"url"`
+	// The job of the metrics pushed, default is `notification-manager`.
+	Job string `json:"job,omitempty"`
+	// The HTTP client configuration to connect to the Pushgateway.
+	HTTPConfig *HTTPClientConfig `json:"httpConfig,omitempty"`
+}
+
+// PushgatewayConfigStatus defines the observed state of PushgatewayConfig
+type PushgatewayConfigStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// PushgatewayConfig is the Schema for the pushgatewayconfigs API
+type PushgatewayConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PushgatewayConfigSpec   `json:"spec,omitempty"`
+	Status PushgatewayConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PushgatewayConfigList contains a list of PushgatewayConfig
+type PushgatewayConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PushgatewayConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PushgatewayConfig{}, &PushgatewayConfigList{})
+}
@@ -18,14 +18,67 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
 )
 
 // SlackReceiverSpec defines the desired state of SlackReceiver
 type SlackReceiverSpec struct {
 	// SlackConfig to be selected for this receiver
 	SlackConfigSelector *metav1.LabelSelector `json:"slackConfigSelector,omitempty"`
+	// The maximum length of the annotation values in the notifications of this receiver, the longer values will be truncated.
+	// It applies in addition to the global `annotationMaxLength`. 0 means no limit.
+	AnnotationMaxLength int `json:"annotationMaxLength,omitempty"`
 	// The channel or user to send notifications to.
 	Channel string `json:"channel"`
+	// Whether to add the link to the source of the alerts to the messages, it overrides the global `sourceLink.enabled`.
+	SourceLink *bool `json:"sourceLink,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The sub-templates which may not be defined in the templates of this receiver, in the form of glob patterns
+	// such as `email.custom.*`. A missing optional sub-template renders empty with a warning instead of failing
+	// the whole message, the sub-templates not matched are still required.
+	OptionalTemplates []string `json:"optionalTemplates,omitempty"`
+	// The annotations shown in the messages in order, with the display names.
+	// The annotations not listed are hidden, all the annotations are shown if it is not set.
+	AnnotationSection []AnnotationField `json:"annotationSection,omitempty"`
+	// The accent of each alert in the messages, derived from the fingerprint of the alert,
+	// so the same alert has the same accent in every notification.
+	AlertAccent *AlertAccent `json:"alertAccent,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
+	// Send the messages in the Block Kit layout instead of the plain text.
+	BlockKit *SlackBlockKit `json:"blockKit,omitempty"`
+}
+
+// SlackBlockKit is the config of the Block Kit layout. The default layout has a header of the group,
+// and a section with the annotations, a context with the labels and the buttons of each alert.
+// A message has at most 50 blocks, the alerts exceeding it are sent in more messages.
+type SlackBlockKit struct {
+	// Whether to send the messages in the Block Kit layout.
+	Enabled bool `json:"enabled,omitempty"`
+	// The template rendering the blocks of a message as a json array, such as `slack.blocks` defined in the template files,
+	// it replaces the default layout.
+	Template string `json:"template,omitempty"`
+	// The buttons of each alert. Default are `Runbook` linking to the annotation `runbook_url`, `Source` linking to
+	// the source of the alert if the source link is enabled, and `Silence` linking to alertmanager.
+	Buttons []SlackButton `json:"buttons,omitempty"`
+}
+
+// SlackButton is a button linking to a page of an alert, such as the runbook, or a page to acknowledge the alert.
+type SlackButton struct {
+	// The text of the button.
+	Text string `json:"text"`
+	// The template of the url, executed with each alert, such as `{{ .Annotations.runbook_url }}`.
+	// `.ExternalURL` is the url of alertmanager and `.SilenceURL` is the url to silence the alert.
+	// The button is omitted if the url is empty.
+	URL string `json:"url"`
+	// The style of the button, `primary` or `danger`, default is the plain button.
+	Style string `json:"style,omitempty"`
 }
 
 // SlackReceiverStatus defines the observed state of SlackReceiver
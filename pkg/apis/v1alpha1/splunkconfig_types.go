@@ -0,0 +1,67 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SplunkConfigSpec defines the desired state of SplunkConfig
+type SplunkConfigSpec struct {
+	// The URL of the HTTP Event Collector, e.g. `https://splunk.example.com:8088`.
+	// `/services/collector/event` will be used as the path if the path is empty.
+	URL string `json:"url"`
+	// The HEC token.
+	Token *v1.SecretKeySelector `json:"token"`
+	// The index the events are written to, the default index of the token is used if not set.
+	Index string `json:"index,omitempty"`
+	// The source type of the events, default is `notification-manager`.
+	SourceType string `json:"sourceType,omitempty"`
+	// The source of the events.
+	Source string `json:"source,omitempty"`
+	// TLSConfig to use to connect to the HTTP Event Collector.
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// SplunkConfigStatus defines the observed state of SplunkConfig
+type SplunkConfigStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// SplunkConfig is the Schema for the splunkconfigs API
+type SplunkConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SplunkConfigSpec   `json:"spec,omitempty"`
+	Status SplunkConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SplunkConfigList contains a list of SplunkConfig
+type SplunkConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SplunkConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SplunkConfig{}, &SplunkConfigList{})
+}
@@ -0,0 +1,65 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// SplunkReceiverSpec defines the desired state of SplunkReceiver
+type SplunkReceiverSpec struct {
+	// SplunkConfig to be selected for this receiver
+	SplunkConfigSelector *metav1.LabelSelector `json:"splunkConfigSelector,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
+}
+
+// SplunkReceiverStatus defines the observed state of SplunkReceiver
+type SplunkReceiverStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// SplunkReceiver is the Schema for the splunkreceivers API
+type SplunkReceiver struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SplunkReceiverSpec   `json:"spec,omitempty"`
+	Status SplunkReceiverStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SplunkReceiverList contains a list of SplunkReceiver
+type SplunkReceiverList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SplunkReceiver `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SplunkReceiver{}, &SplunkReceiverList{})
+}
@@ -59,6 +59,28 @@ type HTTPClientConfig struct {
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
 }
 
+// WebhookSuccessCriteria defines how to decide whether a webhook call succeeded,
+// it is used for the endpoints which return 200 on logical failure.
+// The status code is checked first, a call with a status code other than 200 is a failure whatever the body is.
+// Then the response body is checked with `jsonPath` if it is set, and the regular expressions are ignored.
+// Otherwise, the body matching `failureBodyRegex` or not matching `successBodyRegex` is a failure.
+type WebhookSuccessCriteria struct {
+	// The path of a field of the JSON response body, in the form of the keys and the indexes separated by dots,
+	// such as `accepted` or `results.0.accepted`. The call succeeds if the value of the field is `jsonValue`,
+	// the call whose body is not JSON or has no such field is a failure.
+	JSONPath string `json:"jsonPath,omitempty"`
+	// The value of the field at `jsonPath` of the successful calls, in the form of string, `true` by default.
+	JSONValue string `json:"jsonValue,omitempty"`
+	// The path of the field of the JSON response body which is the reason of the failure, such as `reason`.
+	ReasonJSONPath string `json:"reasonJsonPath,omitempty"`
+	// A regular expression applied to the response body, the call is treated as a failure if the body does not match it.
+	SuccessBodyRegex string `json:"successBodyRegex,omitempty"`
+	// A regular expression applied to the response body, the call is treated as a failure if the body matches it.
+	// The first capture group, if any, will be used as the reason of the failure.
+	// It is checked before `successBodyRegex`.
+	FailureBodyRegex string `json:"failureBodyRegex,omitempty"`
+}
+
 // ServiceReference holds a reference to Service.legacy.k8s.io
 type ServiceReference struct {
 	// `namespace` is the namespace of the service.
@@ -123,6 +145,9 @@ type WebhookConfigSpec struct {
 	Service *ServiceReference `json:"service,omitempty"`
 
 	HTTPConfig *HTTPClientConfig `json:"httpConfig,omitempty"`
+
+	// The criteria to decide whether the call succeeded, only the status code is checked if not set.
+	SuccessCriteria *WebhookSuccessCriteria `json:"successCriteria,omitempty"`
 }
 
 // WebhookConfigStatus defines the observed state of WebhookConfig
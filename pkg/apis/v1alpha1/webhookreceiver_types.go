@@ -18,12 +18,48 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
 )
 
 // WebhookReceiverSpec defines the desired state of WebhookReceiver
 type WebhookReceiverSpec struct {
 	// WebhookConfig to be selected for this receiver
 	WebhookConfigSelector *metav1.LabelSelector `json:"webhookConfigSelector,omitempty"`
+	// The maximum length of the annotation values in the notifications of this receiver, the longer values will be truncated.
+	// It applies in addition to the global `annotationMaxLength`. 0 means no limit.
+	AnnotationMaxLength int `json:"annotationMaxLength,omitempty"`
+	// The sub-templates which may not be defined in the templates of this receiver, in the form of glob patterns
+	// such as `email.custom.*`. A missing optional sub-template renders empty with a warning instead of failing
+	// the whole message, the sub-templates not matched are still required.
+	OptionalTemplates []string `json:"optionalTemplates,omitempty"`
+	// The maximum number of the notifications sent to this receiver simultaneously, the excess ones will wait.
+	// It protects the backend with low capacity, 0 means no limit.
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// The status of the alerts sent to this receiver, `firing` or `resolved`, the alerts of the other status
+	// are not sent to it. Both are sent if it is not set.
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// The time to wait before the first notification of a new alert group, the alerts of the group arriving within it
+	// are sent in one notification. The later notifications of the group are sent without waiting. It is at most 5m.
+	FirstNotificationDelay time.Duration `json:"firstNotificationDelay,omitempty"`
+	// How to handle the redirects of the webhook.
+	Redirect *WebhookRedirect `json:"redirect,omitempty"`
+	// The schema version of the payload, `v1` or `v2`, the payload is an envelope with the `schemaVersion`,
+	// so the consumers are not broken by the later versions. `v1` is the notification data with the `schemaVersion`,
+	// `v2` has the `status`, the `group` with the receiver and the labels, the `counts` of the firing and the resolved alerts,
+	// the `alerts` and the `externalURL`. Both have the `message` rendered by the custom template if it is set.
+	// The payload is the notification data, or the message if the template is set, without an envelope if it is not set.
+	PayloadVersion string `json:"payloadVersion,omitempty"`
+}
+
+// WebhookRedirect is the config of following the redirects of the webhook, such as a 307 to a regional host.
+type WebhookRedirect struct {
+	// Whether to follow the redirects, the redirect response is treated as a failure if not. Default is true.
+	Follow *bool `json:"follow,omitempty"`
+	// The maximum number of the redirects to follow, default is 10.
+	MaxRedirects int `json:"maxRedirects,omitempty"`
+	// Whether to keep the Authorization header when redirected to another host.
+	// It is removed by default, so the credentials are not leaked to the other host.
+	PreserveAuthorization bool `json:"preserveAuthorization,omitempty"`
 }
 
 // WebhookReceiverStatus defines the observed state of WebhookReceiver
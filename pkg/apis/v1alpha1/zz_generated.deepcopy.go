@@ -26,6 +26,83 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Accent) DeepCopyInto(out *Accent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Accent.
+func (in *Accent) DeepCopy() *Accent {
+	if in == nil {
+		return nil
+	}
+	out := new(Accent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Acknowledgement) DeepCopyInto(out *Acknowledgement) {
+	*out = *in
+	if in.DowngradeTo != nil {
+		in, out := &in.DowngradeTo, &out.DowngradeTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Acknowledgement.
+func (in *Acknowledgement) DeepCopy() *Acknowledgement {
+	if in == nil {
+		return nil
+	}
+	out := new(Acknowledgement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertAccent) DeepCopyInto(out *AlertAccent) {
+	*out = *in
+	if in.Palette != nil {
+		in, out := &in.Palette, &out.Palette
+		*out = make([]Accent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Severities != nil {
+		in, out := &in.Severities, &out.Severities
+		*out = make(map[string]Accent, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertAccent.
+func (in *AlertAccent) DeepCopy() *AlertAccent {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertAccent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnnotationField) DeepCopyInto(out *AnnotationField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnnotationField.
+func (in *AnnotationField) DeepCopy() *AnnotationField {
+	if in == nil {
+		return nil
+	}
+	out := new(AnnotationField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -46,6 +123,21 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CardinalityGuard) DeepCopyInto(out *CardinalityGuard) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CardinalityGuard.
+func (in *CardinalityGuard) DeepCopy() *CardinalityGuard {
+	if in == nil {
+		return nil
+	}
+	out := new(CardinalityGuard)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientCertificate) DeepCopyInto(out *ClientCertificate) {
 	*out = *in
@@ -71,6 +163,56 @@ func (in *ClientCertificate) DeepCopy() *ClientCertificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Coalesce) DeepCopyInto(out *Coalesce) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Coalesce.
+func (in *Coalesce) DeepCopy() *Coalesce {
+	if in == nil {
+		return nil
+	}
+	out := new(Coalesce)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigErrorNotification) DeepCopyInto(out *ConfigErrorNotification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigErrorNotification.
+func (in *ConfigErrorNotification) DeepCopy() *ConfigErrorNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigErrorNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dedup) DeepCopyInto(out *Dedup) {
+	*out = *in
+	if in.IgnoredKeys != nil {
+		in, out := &in.IgnoredKeys, &out.IgnoredKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dedup.
+func (in *Dedup) DeepCopy() *Dedup {
+	if in == nil {
+		return nil
+	}
+	out := new(Dedup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DingTalkChatBot) DeepCopyInto(out *DingTalkChatBot) {
 	*out = *in
@@ -322,6 +464,26 @@ func (in *DingTalkReceiverSpec) DeepCopyInto(out *DingTalkReceiverSpec) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SourceLink != nil {
+		in, out := &in.SourceLink, &out.SourceLink
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OptionalTemplates != nil {
+		in, out := &in.OptionalTemplates, &out.OptionalTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationSection != nil {
+		in, out := &in.AnnotationSection, &out.AnnotationSection
+		*out = make([]AnnotationField, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertAccent != nil {
+		in, out := &in.AlertAccent, &out.AlertAccent
+		*out = new(AlertAccent)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DingTalkReceiverSpec.
@@ -350,7 +512,7 @@ func (in *DingTalkReceiverStatus) DeepCopy() *DingTalkReceiverStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailConfig) DeepCopyInto(out *EmailConfig) {
+func (in *ElasticsearchConfig) DeepCopyInto(out *ElasticsearchConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -358,18 +520,18 @@ func (in *EmailConfig) DeepCopyInto(out *EmailConfig) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfig.
-func (in *EmailConfig) DeepCopy() *EmailConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchConfig.
+func (in *ElasticsearchConfig) DeepCopy() *ElasticsearchConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailConfig)
+	out := new(ElasticsearchConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EmailConfig) DeepCopyObject() runtime.Object {
+func (in *ElasticsearchConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -377,31 +539,31 @@ func (in *EmailConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailConfigList) DeepCopyInto(out *EmailConfigList) {
+func (in *ElasticsearchConfigList) DeepCopyInto(out *ElasticsearchConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EmailConfig, len(*in))
+		*out = make([]ElasticsearchConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigList.
-func (in *EmailConfigList) DeepCopy() *EmailConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchConfigList.
+func (in *ElasticsearchConfigList) DeepCopy() *ElasticsearchConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailConfigList)
+	out := new(ElasticsearchConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EmailConfigList) DeepCopyObject() runtime.Object {
+func (in *ElasticsearchConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -409,68 +571,47 @@ func (in *EmailConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailConfigSpec) DeepCopyInto(out *EmailConfigSpec) {
+func (in *ElasticsearchConfigSpec) DeepCopyInto(out *ElasticsearchConfigSpec) {
 	*out = *in
-	out.SmartHost = in.SmartHost
-	if in.Hello != nil {
-		in, out := &in.Hello, &out.Hello
-		*out = new(string)
-		**out = **in
-	}
-	if in.AuthUsername != nil {
-		in, out := &in.AuthUsername, &out.AuthUsername
-		*out = new(string)
-		**out = **in
-	}
-	if in.AuthIdentify != nil {
-		in, out := &in.AuthIdentify, &out.AuthIdentify
-		*out = new(string)
-		**out = **in
-	}
-	if in.AuthPassword != nil {
-		in, out := &in.AuthPassword, &out.AuthPassword
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.AuthSecret != nil {
-		in, out := &in.AuthSecret, &out.AuthSecret
-		*out = new(v1.SecretKeySelector)
+	if in.HTTPConfig != nil {
+		in, out := &in.HTTPConfig, &out.HTTPConfig
+		*out = new(HTTPClientConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.RequireTLS != nil {
-		in, out := &in.RequireTLS, &out.RequireTLS
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigSpec.
-func (in *EmailConfigSpec) DeepCopy() *EmailConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchConfigSpec.
+func (in *ElasticsearchConfigSpec) DeepCopy() *ElasticsearchConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailConfigSpec)
+	out := new(ElasticsearchConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailConfigStatus) DeepCopyInto(out *EmailConfigStatus) {
+func (in *ElasticsearchConfigStatus) DeepCopyInto(out *ElasticsearchConfigStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigStatus.
-func (in *EmailConfigStatus) DeepCopy() *EmailConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchConfigStatus.
+func (in *ElasticsearchConfigStatus) DeepCopy() *ElasticsearchConfigStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailConfigStatus)
+	out := new(ElasticsearchConfigStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailOptions) DeepCopyInto(out *EmailOptions) {
+func (in *ElasticsearchOptions) DeepCopyInto(out *ElasticsearchOptions) {
 	*out = *in
 	if in.NotificationTimeout != nil {
 		in, out := &in.NotificationTimeout, &out.NotificationTimeout
@@ -479,18 +620,18 @@ func (in *EmailOptions) DeepCopyInto(out *EmailOptions) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailOptions.
-func (in *EmailOptions) DeepCopy() *EmailOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchOptions.
+func (in *ElasticsearchOptions) DeepCopy() *ElasticsearchOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailOptions)
+	out := new(ElasticsearchOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailReceiver) DeepCopyInto(out *EmailReceiver) {
+func (in *ElasticsearchReceiver) DeepCopyInto(out *ElasticsearchReceiver) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -498,18 +639,18 @@ func (in *EmailReceiver) DeepCopyInto(out *EmailReceiver) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiver.
-func (in *EmailReceiver) DeepCopy() *EmailReceiver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReceiver.
+func (in *ElasticsearchReceiver) DeepCopy() *ElasticsearchReceiver {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailReceiver)
+	out := new(ElasticsearchReceiver)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EmailReceiver) DeepCopyObject() runtime.Object {
+func (in *ElasticsearchReceiver) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -517,31 +658,31 @@ func (in *EmailReceiver) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailReceiverList) DeepCopyInto(out *EmailReceiverList) {
+func (in *ElasticsearchReceiverList) DeepCopyInto(out *ElasticsearchReceiverList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]EmailReceiver, len(*in))
+		*out = make([]ElasticsearchReceiver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverList.
-func (in *EmailReceiverList) DeepCopy() *EmailReceiverList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReceiverList.
+func (in *ElasticsearchReceiverList) DeepCopy() *ElasticsearchReceiverList {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailReceiverList)
+	out := new(ElasticsearchReceiverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *EmailReceiverList) DeepCopyObject() runtime.Object {
+func (in *ElasticsearchReceiverList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -549,112 +690,197 @@ func (in *EmailReceiverList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailReceiverSpec) DeepCopyInto(out *EmailReceiverSpec) {
+func (in *ElasticsearchReceiverSpec) DeepCopyInto(out *ElasticsearchReceiverSpec) {
 	*out = *in
-	if in.To != nil {
-		in, out := &in.To, &out.To
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.EmailConfigSelector != nil {
-		in, out := &in.EmailConfigSelector, &out.EmailConfigSelector
+	if in.ElasticsearchConfigSelector != nil {
+		in, out := &in.ElasticsearchConfigSelector, &out.ElasticsearchConfigSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverSpec.
-func (in *EmailReceiverSpec) DeepCopy() *EmailReceiverSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReceiverSpec.
+func (in *ElasticsearchReceiverSpec) DeepCopy() *ElasticsearchReceiverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailReceiverSpec)
+	out := new(ElasticsearchReceiverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EmailReceiverStatus) DeepCopyInto(out *EmailReceiverStatus) {
+func (in *ElasticsearchReceiverStatus) DeepCopyInto(out *ElasticsearchReceiverStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverStatus.
-func (in *EmailReceiverStatus) DeepCopy() *EmailReceiverStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchReceiverStatus.
+func (in *ElasticsearchReceiverStatus) DeepCopy() *ElasticsearchReceiverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(EmailReceiverStatus)
+	out := new(ElasticsearchReceiverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalOptions) DeepCopyInto(out *GlobalOptions) {
+func (in *EmailConfig) DeepCopyInto(out *EmailConfig) {
 	*out = *in
-	if in.TemplateFiles != nil {
-		in, out := &in.TemplateFiles, &out.TemplateFiles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalOptions.
-func (in *GlobalOptions) DeepCopy() *GlobalOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfig.
+func (in *EmailConfig) DeepCopy() *EmailConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalOptions)
+	out := new(EmailConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EmailConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPClientConfig) DeepCopyInto(out *HTTPClientConfig) {
+func (in *EmailConfigList) DeepCopyInto(out *EmailConfigList) {
 	*out = *in
-	if in.BasicAuth != nil {
-		in, out := &in.BasicAuth, &out.BasicAuth
-		*out = new(BasicAuth)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.BearerToken != nil {
-		in, out := &in.BearerToken, &out.BearerToken
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TLSConfig != nil {
-		in, out := &in.TLSConfig, &out.TLSConfig
-		*out = new(TLSConfig)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EmailConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPClientConfig.
-func (in *HTTPClientConfig) DeepCopy() *HTTPClientConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigList.
+func (in *EmailConfigList) DeepCopy() *EmailConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPClientConfig)
+	out := new(EmailConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostPort) DeepCopyInto(out *HostPort) {
-	*out = *in
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EmailConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPort.
-func (in *HostPort) DeepCopy() *HostPort {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailConfigSpec) DeepCopyInto(out *EmailConfigSpec) {
+	*out = *in
+	out.SmartHost = in.SmartHost
+	if in.Hello != nil {
+		in, out := &in.Hello, &out.Hello
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthUsername != nil {
+		in, out := &in.AuthUsername, &out.AuthUsername
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthIdentify != nil {
+		in, out := &in.AuthIdentify, &out.AuthIdentify
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthPassword != nil {
+		in, out := &in.AuthPassword, &out.AuthPassword
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequireTLS != nil {
+		in, out := &in.RequireTLS, &out.RequireTLS
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigSpec.
+func (in *EmailConfigSpec) DeepCopy() *EmailConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HostPort)
+	out := new(EmailConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationManager) DeepCopyInto(out *NotificationManager) {
+func (in *EmailConfigStatus) DeepCopyInto(out *EmailConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailConfigStatus.
+func (in *EmailConfigStatus) DeepCopy() *EmailConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailHeader) DeepCopyInto(out *EmailHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailHeader.
+func (in *EmailHeader) DeepCopy() *EmailHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailOptions) DeepCopyInto(out *EmailOptions) {
+	*out = *in
+	if in.NotificationTimeout != nil {
+		in, out := &in.NotificationTimeout, &out.NotificationTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailOptions.
+func (in *EmailOptions) DeepCopy() *EmailOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailReceiver) DeepCopyInto(out *EmailReceiver) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -662,18 +888,18 @@ func (in *NotificationManager) DeepCopyInto(out *NotificationManager) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManager.
-func (in *NotificationManager) DeepCopy() *NotificationManager {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiver.
+func (in *EmailReceiver) DeepCopy() *EmailReceiver {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationManager)
+	out := new(EmailReceiver)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NotificationManager) DeepCopyObject() runtime.Object {
+func (in *EmailReceiver) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -681,31 +907,31 @@ func (in *NotificationManager) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationManagerList) DeepCopyInto(out *NotificationManagerList) {
+func (in *EmailReceiverList) DeepCopyInto(out *EmailReceiverList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NotificationManager, len(*in))
+		*out = make([]EmailReceiver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerList.
-func (in *NotificationManagerList) DeepCopy() *NotificationManagerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverList.
+func (in *EmailReceiverList) DeepCopy() *EmailReceiverList {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationManagerList)
+	out := new(EmailReceiverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NotificationManagerList) DeepCopyObject() runtime.Object {
+func (in *EmailReceiverList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -713,211 +939,1331 @@ func (in *NotificationManagerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationManagerSpec) DeepCopyInto(out *NotificationManagerSpec) {
+func (in *EmailReceiverSpec) DeepCopyInto(out *EmailReceiverSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ImagePullPolicy != nil {
-		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
-		*out = new(v1.PullPolicy)
+	if in.EmailConfigSelector != nil {
+		in, out := &in.EmailConfigSelector, &out.EmailConfigSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SubjectLabels != nil {
+		in, out := &in.SubjectLabels, &out.SubjectLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceLink != nil {
+		in, out := &in.SourceLink, &out.SourceLink
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.OptionalTemplates != nil {
+		in, out := &in.OptionalTemplates, &out.OptionalTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationSection != nil {
+		in, out := &in.AnnotationSection, &out.AnnotationSection
+		*out = make([]AnnotationField, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubjectIcons != nil {
+		in, out := &in.SubjectIcons, &out.SubjectIcons
+		*out = new(SubjectIcons)
 		**out = **in
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]EmailHeader, len(*in))
+		copy(*out, *in)
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.MarkdownAnnotations != nil {
+		in, out := &in.MarkdownAnnotations, &out.MarkdownAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverSpec.
+func (in *EmailReceiverSpec) DeepCopy() *EmailReceiverSpec {
+	if in == nil {
+		return nil
 	}
-	if in.DefaultConfigSelector != nil {
-		in, out := &in.DefaultConfigSelector, &out.DefaultConfigSelector
-		*out = new(metav1.LabelSelector)
+	out := new(EmailReceiverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailReceiverStatus) DeepCopyInto(out *EmailReceiverStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailReceiverStatus.
+func (in *EmailReceiverStatus) DeepCopy() *EmailReceiverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailReceiverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Enricher) DeepCopyInto(out *Enricher) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Enricher.
+func (in *Enricher) DeepCopy() *Enricher {
+	if in == nil {
+		return nil
+	}
+	out := new(Enricher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Enrichment) DeepCopyInto(out *Enrichment) {
+	*out = *in
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(Enricher)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Receivers != nil {
-		in, out := &in.Receivers, &out.Receivers
-		*out = new(ReceiversSpec)
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(Enricher)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.NotificationManagerNamespaces != nil {
-		in, out := &in.NotificationManagerNamespaces, &out.NotificationManagerNamespaces
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Enrichment.
+func (in *Enrichment) DeepCopy() *Enrichment {
+	if in == nil {
+		return nil
+	}
+	out := new(Enrichment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalOptions) DeepCopyInto(out *GlobalOptions) {
+	*out = *in
+	if in.TemplateFiles != nil {
+		in, out := &in.TemplateFiles, &out.TemplateFiles
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
+	if in.SourceLink != nil {
+		in, out := &in.SourceLink, &out.SourceLink
+		*out = new(SourceLink)
+		**out = **in
+	}
+	if in.Coalesce != nil {
+		in, out := &in.Coalesce, &out.Coalesce
+		*out = new(Coalesce)
+		**out = **in
+	}
+	if in.Dedup != nil {
+		in, out := &in.Dedup, &out.Dedup
+		*out = new(Dedup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReceiversLabel != nil {
+		in, out := &in.ReceiversLabel, &out.ReceiversLabel
+		*out = new(ReceiversLabel)
+		**out = **in
+	}
+	if in.TemplateSelector != nil {
+		in, out := &in.TemplateSelector, &out.TemplateSelector
+		*out = new(TemplateSelector)
+		**out = **in
+	}
+	if in.Enrichment != nil {
+		in, out := &in.Enrichment, &out.Enrichment
+		*out = new(Enrichment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Acknowledgement != nil {
+		in, out := &in.Acknowledgement, &out.Acknowledgement
+		*out = new(Acknowledgement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetThrottle != nil {
+		in, out := &in.TargetThrottle, &out.TargetThrottle
+		*out = new(Throttle)
+		**out = **in
+	}
+	if in.Suppressions != nil {
+		in, out := &in.Suppressions, &out.Suppressions
+		*out = make([]Suppression, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+	if in.CardinalityGuard != nil {
+		in, out := &in.CardinalityGuard, &out.CardinalityGuard
+		*out = new(CardinalityGuard)
+		**out = **in
+	}
+	if in.ConfigErrorNotification != nil {
+		in, out := &in.ConfigErrorNotification, &out.ConfigErrorNotification
+		*out = new(ConfigErrorNotification)
+		**out = **in
+	}
+	if in.RoutingTrace != nil {
+		in, out := &in.RoutingTrace, &out.RoutingTrace
+		*out = new(RoutingTrace)
+		**out = **in
+	}
+	if in.RecoveryNotification != nil {
+		in, out := &in.RecoveryNotification, &out.RecoveryNotification
+		*out = new(RecoveryNotification)
+		**out = **in
+	}
+	if in.TargetQuarantine != nil {
+		in, out := &in.TargetQuarantine, &out.TargetQuarantine
+		*out = new(TargetQuarantine)
+		**out = **in
+	}
+	if in.LabelNormalization != nil {
+		in, out := &in.LabelNormalization, &out.LabelNormalization
+		*out = new(LabelNormalization)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalOptions.
+func (in *GlobalOptions) DeepCopy() *GlobalOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcConfig) DeepCopyInto(out *GrpcConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcConfig.
+func (in *GrpcConfig) DeepCopy() *GrpcConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrpcConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcConfigList) DeepCopyInto(out *GrpcConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrpcConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcConfigList.
+func (in *GrpcConfigList) DeepCopy() *GrpcConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrpcConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcConfigSpec) DeepCopyInto(out *GrpcConfigSpec) {
+	*out = *in
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcConfigSpec.
+func (in *GrpcConfigSpec) DeepCopy() *GrpcConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcConfigStatus) DeepCopyInto(out *GrpcConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcConfigStatus.
+func (in *GrpcConfigStatus) DeepCopy() *GrpcConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcOptions) DeepCopyInto(out *GrpcOptions) {
+	*out = *in
+	if in.NotificationTimeout != nil {
+		in, out := &in.NotificationTimeout, &out.NotificationTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcOptions.
+func (in *GrpcOptions) DeepCopy() *GrpcOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcReceiver) DeepCopyInto(out *GrpcReceiver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcReceiver.
+func (in *GrpcReceiver) DeepCopy() *GrpcReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrpcReceiver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcReceiverList) DeepCopyInto(out *GrpcReceiverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GrpcReceiver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcReceiverList.
+func (in *GrpcReceiverList) DeepCopy() *GrpcReceiverList {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcReceiverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GrpcReceiverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcReceiverSpec) DeepCopyInto(out *GrpcReceiverSpec) {
+	*out = *in
+	if in.GrpcConfigSelector != nil {
+		in, out := &in.GrpcConfigSelector, &out.GrpcConfigSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcReceiverSpec.
+func (in *GrpcReceiverSpec) DeepCopy() *GrpcReceiverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcReceiverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcReceiverStatus) DeepCopyInto(out *GrpcReceiverStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcReceiverStatus.
+func (in *GrpcReceiverStatus) DeepCopy() *GrpcReceiverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcReceiverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPClientConfig) DeepCopyInto(out *HTTPClientConfig) {
+	*out = *in
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPClientConfig.
+func (in *HTTPClientConfig) DeepCopy() *HTTPClientConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPClientConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPort) DeepCopyInto(out *HostPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPort.
+func (in *HostPort) DeepCopy() *HostPort {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelNormalization) DeepCopyInto(out *LabelNormalization) {
+	*out = *in
+	if in.LowercaseValues != nil {
+		in, out := &in.LowercaseValues, &out.LowercaseValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelNormalization.
+func (in *LabelNormalization) DeepCopy() *LabelNormalization {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelNormalization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationManager) DeepCopyInto(out *NotificationManager) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManager.
+func (in *NotificationManager) DeepCopy() *NotificationManager {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationManager) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationManagerList) DeepCopyInto(out *NotificationManagerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationManager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerList.
+func (in *NotificationManagerList) DeepCopy() *NotificationManagerList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationManagerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationManagerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationManagerSpec) DeepCopyInto(out *NotificationManagerSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(v1.PullPolicy)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultConfigSelector != nil {
+		in, out := &in.DefaultConfigSelector, &out.DefaultConfigSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = new(ReceiversSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotificationManagerNamespaces != nil {
+		in, out := &in.NotificationManagerNamespaces, &out.NotificationManagerNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerSpec.
+func (in *NotificationManagerSpec) DeepCopy() *NotificationManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationManagerStatus) DeepCopyInto(out *NotificationManagerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerStatus.
+func (in *NotificationManagerStatus) DeepCopy() *NotificationManagerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationManagerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Options) DeepCopyInto(out *Options) {
+	*out = *in
+	if in.Global != nil {
+		in, out := &in.Global, &out.Global
+		*out = new(GlobalOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wechat != nil {
+		in, out := &in.Wechat, &out.Wechat
+		*out = new(WechatOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DingTalk != nil {
+		in, out := &in.DingTalk, &out.DingTalk
+		*out = new(DingTalkOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Elasticsearch != nil {
+		in, out := &in.Elasticsearch, &out.Elasticsearch
+		*out = new(ElasticsearchOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Splunk != nil {
+		in, out := &in.Splunk, &out.Splunk
+		*out = new(SplunkOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Grpc != nil {
+		in, out := &in.Grpc, &out.Grpc
+		*out = new(GrpcOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pushgateway != nil {
+		in, out := &in.Pushgateway, &out.Pushgateway
+		*out = new(PushgatewayOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Options.
+func (in *Options) DeepCopy() *Options {
+	if in == nil {
+		return nil
+	}
+	out := new(Options)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayConfig) DeepCopyInto(out *PushgatewayConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayConfig.
+func (in *PushgatewayConfig) DeepCopy() *PushgatewayConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushgatewayConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayConfigList) DeepCopyInto(out *PushgatewayConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PushgatewayConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayConfigList.
+func (in *PushgatewayConfigList) DeepCopy() *PushgatewayConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushgatewayConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayConfigSpec) DeepCopyInto(out *PushgatewayConfigSpec) {
+	*out = *in
+	if in.HTTPConfig != nil {
+		in, out := &in.HTTPConfig, &out.HTTPConfig
+		*out = new(HTTPClientConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayConfigSpec.
+func (in *PushgatewayConfigSpec) DeepCopy() *PushgatewayConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayConfigStatus) DeepCopyInto(out *PushgatewayConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayConfigStatus.
+func (in *PushgatewayConfigStatus) DeepCopy() *PushgatewayConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayOptions) DeepCopyInto(out *PushgatewayOptions) {
+	*out = *in
+	if in.NotificationTimeout != nil {
+		in, out := &in.NotificationTimeout, &out.NotificationTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayOptions.
+func (in *PushgatewayOptions) DeepCopy() *PushgatewayOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayReceiver) DeepCopyInto(out *PushgatewayReceiver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayReceiver.
+func (in *PushgatewayReceiver) DeepCopy() *PushgatewayReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushgatewayReceiver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayReceiverList) DeepCopyInto(out *PushgatewayReceiverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PushgatewayReceiver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayReceiverList.
+func (in *PushgatewayReceiverList) DeepCopy() *PushgatewayReceiverList {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayReceiverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PushgatewayReceiverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayReceiverSpec) DeepCopyInto(out *PushgatewayReceiverSpec) {
+	*out = *in
+	if in.PushgatewayConfigSelector != nil {
+		in, out := &in.PushgatewayConfigSelector, &out.PushgatewayConfigSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayReceiverSpec.
+func (in *PushgatewayReceiverSpec) DeepCopy() *PushgatewayReceiverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayReceiverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushgatewayReceiverStatus) DeepCopyInto(out *PushgatewayReceiverStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushgatewayReceiverStatus.
+func (in *PushgatewayReceiverStatus) DeepCopy() *PushgatewayReceiverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushgatewayReceiverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiversLabel) DeepCopyInto(out *ReceiversLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiversLabel.
+func (in *ReceiversLabel) DeepCopy() *ReceiversLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiversLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReceiversSpec) DeepCopyInto(out *ReceiversSpec) {
+	*out = *in
+	if in.GlobalReceiverSelector != nil {
+		in, out := &in.GlobalReceiverSelector, &out.GlobalReceiverSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TenantReceiverSelector != nil {
+		in, out := &in.TenantReceiverSelector, &out.TenantReceiverSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(Options)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiversSpec.
+func (in *ReceiversSpec) DeepCopy() *ReceiversSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReceiversSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryNotification) DeepCopyInto(out *RecoveryNotification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryNotification.
+func (in *RecoveryNotification) DeepCopy() *RecoveryNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingTrace) DeepCopyInto(out *RoutingTrace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingTrace.
+func (in *RoutingTrace) DeepCopy() *RoutingTrace {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingTrace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
+	*out = *in
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Scheme != nil {
+		in, out := &in.Scheme, &out.Scheme
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
+func (in *ServiceReference) DeepCopy() *ServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackBlockKit) DeepCopyInto(out *SlackBlockKit) {
+	*out = *in
+	if in.Buttons != nil {
+		in, out := &in.Buttons, &out.Buttons
+		*out = make([]SlackButton, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackBlockKit.
+func (in *SlackBlockKit) DeepCopy() *SlackBlockKit {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackBlockKit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackButton) DeepCopyInto(out *SlackButton) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackButton.
+func (in *SlackButton) DeepCopy() *SlackButton {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackButton)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackConfig) DeepCopyInto(out *SlackConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfig.
+func (in *SlackConfig) DeepCopy() *SlackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackConfigList) DeepCopyInto(out *SlackConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlackConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigList.
+func (in *SlackConfigList) DeepCopy() *SlackConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackConfigSpec) DeepCopyInto(out *SlackConfigSpec) {
+	*out = *in
+	if in.SlackTokenSecret != nil {
+		in, out := &in.SlackTokenSecret, &out.SlackTokenSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigSpec.
+func (in *SlackConfigSpec) DeepCopy() *SlackConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackConfigStatus) DeepCopyInto(out *SlackConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigStatus.
+func (in *SlackConfigStatus) DeepCopy() *SlackConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackOptions) DeepCopyInto(out *SlackOptions) {
+	*out = *in
+	if in.NotificationTimeout != nil {
+		in, out := &in.NotificationTimeout, &out.NotificationTimeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackOptions.
+func (in *SlackOptions) DeepCopy() *SlackOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackReceiver) DeepCopyInto(out *SlackReceiver) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiver.
+func (in *SlackReceiver) DeepCopy() *SlackReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackReceiver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackReceiverList) DeepCopyInto(out *SlackReceiverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlackReceiver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerSpec.
-func (in *NotificationManagerSpec) DeepCopy() *NotificationManagerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverList.
+func (in *SlackReceiverList) DeepCopy() *SlackReceiverList {
 	if in == nil {
 		return nil
 	}
-	out := new(NotificationManagerSpec)
+	out := new(SlackReceiverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NotificationManagerStatus) DeepCopyInto(out *NotificationManagerStatus) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerStatus.
-func (in *NotificationManagerStatus) DeepCopy() *NotificationManagerStatus {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackReceiverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(NotificationManagerStatus)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Options) DeepCopyInto(out *Options) {
+func (in *SlackReceiverSpec) DeepCopyInto(out *SlackReceiverSpec) {
 	*out = *in
-	if in.Global != nil {
-		in, out := &in.Global, &out.Global
-		*out = new(GlobalOptions)
+	if in.SlackConfigSelector != nil {
+		in, out := &in.SlackConfigSelector, &out.SlackConfigSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Email != nil {
-		in, out := &in.Email, &out.Email
-		*out = new(EmailOptions)
-		(*in).DeepCopyInto(*out)
+	if in.SourceLink != nil {
+		in, out := &in.SourceLink, &out.SourceLink
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Wechat != nil {
-		in, out := &in.Wechat, &out.Wechat
-		*out = new(WechatOptions)
-		(*in).DeepCopyInto(*out)
+	if in.OptionalTemplates != nil {
+		in, out := &in.OptionalTemplates, &out.OptionalTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Slack != nil {
-		in, out := &in.Slack, &out.Slack
-		*out = new(SlackOptions)
-		(*in).DeepCopyInto(*out)
+	if in.AnnotationSection != nil {
+		in, out := &in.AnnotationSection, &out.AnnotationSection
+		*out = make([]AnnotationField, len(*in))
+		copy(*out, *in)
 	}
-	if in.Webhook != nil {
-		in, out := &in.Webhook, &out.Webhook
-		*out = new(WebhookOptions)
+	if in.BlockKit != nil {
+		in, out := &in.BlockKit, &out.BlockKit
+		*out = new(SlackBlockKit)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.DingTalk != nil {
-		in, out := &in.DingTalk, &out.DingTalk
-		*out = new(DingTalkOptions)
+	if in.AlertAccent != nil {
+		in, out := &in.AlertAccent, &out.AlertAccent
+		*out = new(AlertAccent)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Options.
-func (in *Options) DeepCopy() *Options {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverSpec.
+func (in *SlackReceiverSpec) DeepCopy() *SlackReceiverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Options)
+	out := new(SlackReceiverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReceiversSpec) DeepCopyInto(out *ReceiversSpec) {
+func (in *SlackReceiverStatus) DeepCopyInto(out *SlackReceiverStatus) {
 	*out = *in
-	if in.GlobalReceiverSelector != nil {
-		in, out := &in.GlobalReceiverSelector, &out.GlobalReceiverSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TenantReceiverSelector != nil {
-		in, out := &in.TenantReceiverSelector, &out.TenantReceiverSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
-		*out = new(Options)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiversSpec.
-func (in *ReceiversSpec) DeepCopy() *ReceiversSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverStatus.
+func (in *SlackReceiverStatus) DeepCopy() *SlackReceiverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReceiversSpec)
+	out := new(SlackReceiverStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
+func (in *SourceLink) DeepCopyInto(out *SourceLink) {
 	*out = *in
-	if in.Path != nil {
-		in, out := &in.Path, &out.Path
-		*out = new(string)
-		**out = **in
-	}
-	if in.Port != nil {
-		in, out := &in.Port, &out.Port
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Scheme != nil {
-		in, out := &in.Scheme, &out.Scheme
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
-func (in *ServiceReference) DeepCopy() *ServiceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceLink.
+func (in *SourceLink) DeepCopy() *SourceLink {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceReference)
+	out := new(SourceLink)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackConfig) DeepCopyInto(out *SlackConfig) {
+func (in *SplunkConfig) DeepCopyInto(out *SplunkConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -925,18 +2271,18 @@ func (in *SlackConfig) DeepCopyInto(out *SlackConfig) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfig.
-func (in *SlackConfig) DeepCopy() *SlackConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkConfig.
+func (in *SplunkConfig) DeepCopy() *SplunkConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackConfig)
+	out := new(SplunkConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SlackConfig) DeepCopyObject() runtime.Object {
+func (in *SplunkConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -944,31 +2290,31 @@ func (in *SlackConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackConfigList) DeepCopyInto(out *SlackConfigList) {
+func (in *SplunkConfigList) DeepCopyInto(out *SplunkConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SlackConfig, len(*in))
+		*out = make([]SplunkConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigList.
-func (in *SlackConfigList) DeepCopy() *SlackConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkConfigList.
+func (in *SplunkConfigList) DeepCopy() *SplunkConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackConfigList)
+	out := new(SplunkConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SlackConfigList) DeepCopyObject() runtime.Object {
+func (in *SplunkConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -976,42 +2322,47 @@ func (in *SlackConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackConfigSpec) DeepCopyInto(out *SlackConfigSpec) {
+func (in *SplunkConfigSpec) DeepCopyInto(out *SplunkConfigSpec) {
 	*out = *in
-	if in.SlackTokenSecret != nil {
-		in, out := &in.SlackTokenSecret, &out.SlackTokenSecret
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
 		*out = new(v1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigSpec.
-func (in *SlackConfigSpec) DeepCopy() *SlackConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkConfigSpec.
+func (in *SplunkConfigSpec) DeepCopy() *SplunkConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackConfigSpec)
+	out := new(SplunkConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackConfigStatus) DeepCopyInto(out *SlackConfigStatus) {
+func (in *SplunkConfigStatus) DeepCopyInto(out *SplunkConfigStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackConfigStatus.
-func (in *SlackConfigStatus) DeepCopy() *SlackConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkConfigStatus.
+func (in *SplunkConfigStatus) DeepCopy() *SplunkConfigStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackConfigStatus)
+	out := new(SplunkConfigStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackOptions) DeepCopyInto(out *SlackOptions) {
+func (in *SplunkOptions) DeepCopyInto(out *SplunkOptions) {
 	*out = *in
 	if in.NotificationTimeout != nil {
 		in, out := &in.NotificationTimeout, &out.NotificationTimeout
@@ -1020,18 +2371,18 @@ func (in *SlackOptions) DeepCopyInto(out *SlackOptions) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackOptions.
-func (in *SlackOptions) DeepCopy() *SlackOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkOptions.
+func (in *SplunkOptions) DeepCopy() *SplunkOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackOptions)
+	out := new(SplunkOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackReceiver) DeepCopyInto(out *SlackReceiver) {
+func (in *SplunkReceiver) DeepCopyInto(out *SplunkReceiver) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1039,18 +2390,18 @@ func (in *SlackReceiver) DeepCopyInto(out *SlackReceiver) {
 	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiver.
-func (in *SlackReceiver) DeepCopy() *SlackReceiver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkReceiver.
+func (in *SplunkReceiver) DeepCopy() *SplunkReceiver {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackReceiver)
+	out := new(SplunkReceiver)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SlackReceiver) DeepCopyObject() runtime.Object {
+func (in *SplunkReceiver) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1058,31 +2409,31 @@ func (in *SlackReceiver) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackReceiverList) DeepCopyInto(out *SlackReceiverList) {
+func (in *SplunkReceiverList) DeepCopyInto(out *SplunkReceiverList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SlackReceiver, len(*in))
+		*out = make([]SplunkReceiver, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverList.
-func (in *SlackReceiverList) DeepCopy() *SlackReceiverList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkReceiverList.
+func (in *SplunkReceiverList) DeepCopy() *SplunkReceiverList {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackReceiverList)
+	out := new(SplunkReceiverList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SlackReceiverList) DeepCopyObject() runtime.Object {
+func (in *SplunkReceiverList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1090,36 +2441,76 @@ func (in *SlackReceiverList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackReceiverSpec) DeepCopyInto(out *SlackReceiverSpec) {
+func (in *SplunkReceiverSpec) DeepCopyInto(out *SplunkReceiverSpec) {
 	*out = *in
-	if in.SlackConfigSelector != nil {
-		in, out := &in.SlackConfigSelector, &out.SlackConfigSelector
+	if in.SplunkConfigSelector != nil {
+		in, out := &in.SplunkConfigSelector, &out.SplunkConfigSelector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverSpec.
-func (in *SlackReceiverSpec) DeepCopy() *SlackReceiverSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkReceiverSpec.
+func (in *SplunkReceiverSpec) DeepCopy() *SplunkReceiverSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackReceiverSpec)
+	out := new(SplunkReceiverSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SlackReceiverStatus) DeepCopyInto(out *SlackReceiverStatus) {
+func (in *SplunkReceiverStatus) DeepCopyInto(out *SplunkReceiverStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackReceiverStatus.
-func (in *SlackReceiverStatus) DeepCopy() *SlackReceiverStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SplunkReceiverStatus.
+func (in *SplunkReceiverStatus) DeepCopy() *SplunkReceiverStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SlackReceiverStatus)
+	out := new(SplunkReceiverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubjectIcons) DeepCopyInto(out *SubjectIcons) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectIcons.
+func (in *SubjectIcons) DeepCopy() *SubjectIcons {
+	if in == nil {
+		return nil
+	}
+	out := new(SubjectIcons)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Suppression) DeepCopyInto(out *Suppression) {
+	*out = *in
+	if in.Cause != nil {
+		in, out := &in.Cause, &out.Cause
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Symptom != nil {
+		in, out := &in.Symptom, &out.Symptom
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Suppression.
+func (in *Suppression) DeepCopy() *Suppression {
+	if in == nil {
+		return nil
+	}
+	out := new(Suppression)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1149,6 +2540,36 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetQuarantine) DeepCopyInto(out *TargetQuarantine) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetQuarantine.
+func (in *TargetQuarantine) DeepCopy() *TargetQuarantine {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetQuarantine)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSelector) DeepCopyInto(out *TemplateSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSelector.
+func (in *TemplateSelector) DeepCopy() *TemplateSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Throttle) DeepCopyInto(out *Throttle) {
 	*out = *in
@@ -1241,6 +2662,11 @@ func (in *WebhookConfigSpec) DeepCopyInto(out *WebhookConfigSpec) {
 		*out = new(HTTPClientConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SuccessCriteria != nil {
+		in, out := &in.SuccessCriteria, &out.SuccessCriteria
+		*out = new(WebhookSuccessCriteria)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfigSpec.
@@ -1355,6 +2781,16 @@ func (in *WebhookReceiverSpec) DeepCopyInto(out *WebhookReceiverSpec) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OptionalTemplates != nil {
+		in, out := &in.OptionalTemplates, &out.OptionalTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(WebhookRedirect)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookReceiverSpec.
@@ -1382,6 +2818,41 @@ func (in *WebhookReceiverStatus) DeepCopy() *WebhookReceiverStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookRedirect) DeepCopyInto(out *WebhookRedirect) {
+	*out = *in
+	if in.Follow != nil {
+		in, out := &in.Follow, &out.Follow
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookRedirect.
+func (in *WebhookRedirect) DeepCopy() *WebhookRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSuccessCriteria) DeepCopyInto(out *WebhookSuccessCriteria) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSuccessCriteria.
+func (in *WebhookSuccessCriteria) DeepCopy() *WebhookSuccessCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSuccessCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WechatConfig) DeepCopyInto(out *WechatConfig) {
 	*out = *in
@@ -1563,6 +3034,26 @@ func (in *WechatReceiverSpec) DeepCopyInto(out *WechatReceiverSpec) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SourceLink != nil {
+		in, out := &in.SourceLink, &out.SourceLink
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OptionalTemplates != nil {
+		in, out := &in.OptionalTemplates, &out.OptionalTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AnnotationSection != nil {
+		in, out := &in.AnnotationSection, &out.AnnotationSection
+		*out = make([]AnnotationField, len(*in))
+		copy(*out, *in)
+	}
+	if in.AlertAccent != nil {
+		in, out := &in.AlertAccent, &out.AlertAccent
+		*out = new(AlertAccent)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WechatReceiverSpec.
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"sync"
+	"time"
+)
+
+var acks = &ackStates{
+	states: make(map[string]*Ack),
+}
+
+// The acknowledgement of each alert group, it is removed when the group is resolved.
+type ackStates struct {
+	states map[string]*Ack
+	mutex  sync.Mutex
+}
+
+type Ack struct {
+	By string    `json:"by"`
+	At time.Time `json:"at"`
+}
+
+// Acknowledge the group identified by the alertmanager receiver, the namespace and the group labels,
+// in the same way as the group key.
+func Acknowledge(receiver, namespace string, groupLabels map[string]string, by string) {
+
+	data := template.Data{
+		Receiver:     receiver,
+		CommonLabels: template.KV{"namespace": namespace},
+		GroupLabels:  groupLabels,
+	}
+	acks.set(groupKey(data), &Ack{By: by, At: time.Now()})
+}
+
+func (a *ackStates) set(key string, ack *Ack) {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for k, s := range a.states {
+		if ack.At.Sub(s.At) > groupStateExpires {
+			delete(a.states, k)
+		}
+	}
+
+	a.states[key] = ack
+}
+
+func (a *ackStates) get(key string) *Ack {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.states[key]
+}
+
+func (a *ackStates) delete(key string) {
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	delete(a.states, key)
+}
+
+// The acknowledgement of the group, the acknowledgement is removed if the group is resolved.
+// It is nil if the group is not acknowledged, or an alert starts firing after the acknowledgement,
+// so the new alert is notified as usual.
+func acknowledged(data template.Data) *Ack {
+
+	key := groupKey(data)
+	firing := data.Alerts.Firing()
+	if len(firing) == 0 {
+		acks.delete(key)
+		return nil
+	}
+
+	ack := acks.get(key)
+	if ack == nil {
+		return nil
+	}
+
+	for _, alert := range firing {
+		if alert.StartsAt.After(ack.At) {
+			return nil
+		}
+	}
+
+	return ack
+}
+
+// Set the acknowledgement to the common annotations, so the templates and the subject show it.
+func markAcknowledged(data template.Data, ack *Ack) template.Data {
+
+	data.CommonAnnotations = copyKV(data.CommonAnnotations)
+	data.CommonAnnotations[notifier.AcknowledgedByAnnotation] = ack.By
+	data.CommonAnnotations[notifier.AcknowledgedAtAnnotation] = ack.At.Format(time.RFC3339)
+	stats.GetCounters().Add("acknowledged", 1)
+	return data
+}
+
+// The receivers of the types the notifications of the acknowledged groups are sent to.
+func downgradeReceivers(receivers []config.Receiver, ack *v1alpha1.Acknowledgement) []config.Receiver {
+
+	if len(ack.DowngradeTo) == 0 {
+		return receivers
+	}
+
+	var res []config.Receiver
+	for _, r := range receivers {
+		for _, t := range ack.DowngradeTo {
+			if config.ReceiverType(r) == t {
+				res = append(res, r)
+				break
+			}
+		}
+	}
+
+	return res
+}
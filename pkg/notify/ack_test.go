@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestAcknowledged(t *testing.T) {
+
+	newAlert := testAlert("new", "firing")
+	newAlert.StartsAt = time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		ack   bool
+		data  template.Data
+		acked bool
+		kept  bool
+	}{
+		{"not acknowledged", false, testGroup("ack-none", testAlert("a", "firing")), false, false},
+		{"acknowledged", true, testGroup("ack-firing", testAlert("a", "firing"), testAlert("b", "resolved")), true, true},
+		{"alert started after the acknowledgement", true, testGroup("ack-new", testAlert("a", "firing"), newAlert), false, true},
+		{"group resolved", true, testGroup("ack-resolved", testAlert("a", "resolved")), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ack {
+				Acknowledge(tt.data.Receiver, "default", tt.data.GroupLabels, "alice")
+			}
+
+			ack := acknowledged(tt.data)
+			if (ack != nil) != tt.acked {
+				t.Fatalf("acknowledged() = %v, want acknowledged %v", ack, tt.acked)
+			}
+			if ack != nil && ack.By != "alice" {
+				t.Errorf("acknowledged by %s, want alice", ack.By)
+			}
+			if kept := acks.get(groupKey(tt.data)) != nil; kept != tt.kept {
+				t.Errorf("acknowledgement kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestAckExpires(t *testing.T) {
+
+	states := &ackStates{states: make(map[string]*Ack)}
+	now := time.Now()
+	states.set("old", &Ack{By: "alice", At: now.Add(-groupStateExpires - time.Minute)})
+	states.set("recent", &Ack{By: "bob", At: now.Add(-time.Hour)})
+	states.set("new", &Ack{By: "carol", At: now})
+
+	tests := []struct {
+		key  string
+		kept bool
+	}{
+		{"old", false},
+		{"recent", true},
+		{"new", true},
+	}
+
+	for _, tt := range tests {
+		if kept := states.get(tt.key) != nil; kept != tt.kept {
+			t.Errorf("acknowledgement %s kept = %v, want %v", tt.key, kept, tt.kept)
+		}
+	}
+}
+
+func TestMarkAcknowledged(t *testing.T) {
+
+	data := testGroup("ack-marked", testAlert("a", "firing"))
+	at := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	marked := markAcknowledged(data, &Ack{By: "alice", At: at})
+
+	if data.CommonAnnotations[notifier.AcknowledgedByAnnotation] != "" {
+		t.Errorf("the common annotations of the original data are changed")
+	}
+	if by := marked.CommonAnnotations[notifier.AcknowledgedByAnnotation]; by != "alice" {
+		t.Errorf("%s = %s, want alice", notifier.AcknowledgedByAnnotation, by)
+	}
+	if ts := marked.CommonAnnotations[notifier.AcknowledgedAtAnnotation]; ts != "2021-01-01T00:00:00Z" {
+		t.Errorf("%s = %s, want 2021-01-01T00:00:00Z", notifier.AcknowledgedAtAnnotation, ts)
+	}
+
+	tmpl := sampleTemplate(t)
+	for _, name := range []string{"nm.default.text", "nm.default.html"} {
+		msg, err := tmpl.TempleText(`{{ template "`+name+`" . }}`, marked, log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(msg, "Acknowledged by alice") {
+			t.Errorf("expect the acknowledgement in %s:\n%s", name, msg)
+		}
+	}
+}
+
+func TestDowngradeReceivers(t *testing.T) {
+
+	receivers := []config.Receiver{config.NewEmailReceiver(), config.NewSlackReceiver(), config.NewWebhookReceiver()}
+
+	tests := []struct {
+		name        string
+		downgradeTo []string
+		want        []string
+	}{
+		{"not downgraded", nil, []string{"email", "slack", "webhook"}},
+		{"email only", []string{"email"}, []string{"email"}},
+		{"email and webhook", []string{"webhook", "email"}, []string{"email", "webhook"}},
+		{"no receiver of the types", []string{"wechat"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range downgradeReceivers(receivers, &v1alpha1.Acknowledgement{DowngradeTo: tt.downgradeTo}) {
+				got = append(got, config.ReceiverType(r))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("downgradeReceivers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"sync"
+	"time"
+)
+
+const (
+	// The maximum delay of the first notification of a group.
+	MaxFirstNotificationDelay = time.Minute * 5
+	// The maximum number of the groups buffered, the buffer will be flushed if exceeded.
+	MaxBatchGroups = 1000
+	// The groups not notified for this long are forgotten, so their next notification is the first one again.
+	batchGroupTTL = time.Hour * 24
+	// The notified groups are cleaned up when there are more than this many.
+	maxNotifiedGroups = 10000
+)
+
+// Batcher delays the first notification of a new group to the receivers with the first notification delay,
+// the alerts of the group arriving within the delay are merged into one notification, such as the alerts
+// trickling in at the start of an incident. The later notifications of the group are sent directly.
+// The notifications are preprocessed before they are added, so they are not deduplicated again when the batch is sent.
+type Batcher struct {
+	logger      log.Logger
+	notifierCfg *config.Config
+	// The groups waiting for the first notification, the key is the receiver and the group.
+	// The receiver is identified by its type, namespace and name, so the groups are kept when the receivers are reloaded.
+	batches map[string]*batch
+	// The time of the last notification of each group, the groups in it are not new.
+	notified map[string]time.Time
+	mutex    sync.Mutex
+}
+
+type batch struct {
+	receiver config.Receiver
+	data     template.Data
+	timer    *time.Timer
+}
+
+func NewBatcher(logger log.Logger, notifierCfg *config.Config) *Batcher {
+	return &Batcher{
+		logger:      logger,
+		notifierCfg: notifierCfg,
+		batches:     make(map[string]*batch),
+		notified:    make(map[string]time.Time),
+	}
+}
+
+// Add buffers the preprocessed group for the receivers whose first notification of the group is delayed,
+// it returns the other receivers, which the group should be sent to directly.
+func (b *Batcher) Add(receivers []config.Receiver, data template.Data) []config.Receiver {
+
+	// The alerts are dropped by the preprocessing.
+	if len(data.Alerts) == 0 {
+		return receivers
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	var direct []config.Receiver
+	for _, r := range receivers {
+		delay := r.GetFirstNotificationDelay()
+		if delay <= 0 {
+			direct = append(direct, r)
+			continue
+		}
+		if delay > MaxFirstNotificationDelay {
+			delay = MaxFirstNotificationDelay
+		}
+
+		key := fmt.Sprintf("%s/%s/%s/%s", config.ReceiverType(r), r.GetNamespace(), r.GetName(), groupKey(data))
+		if bt, ok := b.batches[key]; ok {
+			// The batch is sent with the latest config of the receiver.
+			bt.receiver = r
+			bt.data = mergeBatch(bt.data, data)
+			continue
+		}
+
+		// The group is not new, or it has nothing firing to wait for.
+		if t, ok := b.notified[key]; (ok && now.Sub(t) < batchGroupTTL) || data.Status == string(model.AlertResolved) {
+			b.markNotified(key, data, now)
+			direct = append(direct, r)
+			continue
+		}
+
+		if len(b.batches) >= MaxBatchGroups {
+			_ = level.Warn(b.logger).Log("msg", "Batcher: too many groups buffered, flush all", "size", len(b.batches))
+			for k := range b.batches {
+				b.flushLocked(k)
+			}
+		}
+
+		k := key
+		b.batches[key] = &batch{
+			receiver: r,
+			data:     data,
+			timer: time.AfterFunc(delay, func() {
+				b.mutex.Lock()
+				defer b.mutex.Unlock()
+				b.flushLocked(k)
+			}),
+		}
+	}
+
+	return direct
+}
+
+// Flush sends all the buffered groups, it is called when shutting down.
+func (b *Batcher) Flush(ctx context.Context) {
+
+	b.mutex.Lock()
+	var batches []*batch
+	for k, bt := range b.batches {
+		bt.timer.Stop()
+		batches = append(batches, bt)
+		delete(b.batches, k)
+	}
+	b.mutex.Unlock()
+
+	for _, bt := range batches {
+		b.send(ctx, bt)
+	}
+}
+
+// Must be called with the mutex held, the group is sent asynchronously.
+func (b *Batcher) flushLocked(key string) {
+
+	bt, ok := b.batches[key]
+	if !ok {
+		return
+	}
+
+	bt.timer.Stop()
+	delete(b.batches, key)
+	b.markNotified(key, bt.data, time.Now())
+
+	go b.send(context.Background(), bt)
+}
+
+func (b *Batcher) send(ctx context.Context, bt *batch) {
+
+	if errs := NewPreprocessedNotification(b.logger, []config.Receiver{bt.receiver}, b.notifierCfg, bt.data).Notify(ctx); len(errs) > 0 {
+		_ = level.Error(b.logger).Log("msg", "Batcher: send notification error", "group", groupKey(bt.data))
+	}
+}
+
+// Must be called with the mutex held. A resolved group is forgotten, so it is new again when it fires again.
+func (b *Batcher) markNotified(key string, data template.Data, now time.Time) {
+
+	if data.Status == string(model.AlertResolved) {
+		delete(b.notified, key)
+		return
+	}
+
+	if _, ok := b.notified[key]; !ok && len(b.notified) >= maxNotifiedGroups {
+		for k, t := range b.notified {
+			if now.Sub(t) >= batchGroupTTL {
+				delete(b.notified, k)
+			}
+		}
+	}
+
+	b.notified[key] = now
+}
+
+// Merge the later notification of the group into the buffered one, the alerts are the union of them,
+// and the alert in the later notification replaces the same one in the buffered notification.
+func mergeBatch(buffered, later template.Data) template.Data {
+
+	data := later
+	data.CommonLabels = copyKV(later.CommonLabels)
+	data.CommonAnnotations = copyKV(later.CommonAnnotations)
+	intersectKV(data.CommonLabels, buffered.CommonLabels)
+	intersectKV(data.CommonAnnotations, buffered.CommonAnnotations)
+
+	index := make(map[string]int)
+	data.Alerts = nil
+	for _, a := range append(append(template.Alerts{}, buffered.Alerts...), later.Alerts...) {
+		key := fingerprint(a)
+		if i, ok := index[key]; ok {
+			data.Alerts[i] = a
+			continue
+		}
+		index[key] = len(data.Alerts)
+		data.Alerts = append(data.Alerts, a)
+	}
+
+	// The batch is the first notification of the group to the receiver, so it keeps the reason of the first one.
+	if reason, ok := buffered.CommonAnnotations[ReasonAnnotation]; ok {
+		data.CommonAnnotations[ReasonAnnotation] = reason
+	}
+
+	data.Status = string(model.AlertResolved)
+	if len(data.Alerts.Firing()) > 0 {
+		data.Status = string(model.AlertFiring)
+	}
+
+	return data
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// delayedReceiver sets the first notification delay of the receiver it wraps.
+type delayedReceiver struct {
+	config.Receiver
+	delay time.Duration
+}
+
+func (r *delayedReceiver) GetFirstNotificationDelay() time.Duration {
+	return r.delay
+}
+
+func TestBatcherSendsPreprocessedBatch(t *testing.T) {
+
+	s := newWebhookServer(t)
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		NotificationReason: true,
+		Dedup:              &v1alpha1.Dedup{Enabled: true},
+	}}}
+	b := NewBatcher(log.NewNopLogger(), cfg)
+
+	hook := newTestWebhook("hook", s.URL)
+	a, c := testAlert("a", "firing"), testAlert("b", "firing")
+
+	// The alerts of the new group trickle in within the delay.
+	for _, alerts := range [][]template.Alert{{a}, {a, c}} {
+		d := Preprocess(log.NewNopLogger(), cfg, testGroup("batch-preprocessed", alerts...))
+		if direct := b.Add([]config.Receiver{&delayedReceiver{Receiver: hook, delay: time.Hour}}, d); len(direct) != 0 {
+			t.Fatalf("expect the group buffered, got %d receivers to send directly", len(direct))
+		}
+	}
+	if len(b.batches) != 1 {
+		t.Fatalf("expect 1 batch, got %d", len(b.batches))
+	}
+
+	// The wrapper only sets the delay, the batch is sent to the webhook it wraps.
+	for _, bt := range b.batches {
+		bt.receiver = hook
+	}
+	b.Flush(context.Background())
+
+	received := s.notifications()
+	if len(received) != 1 {
+		t.Fatalf("expect 1 notification, got %d", len(received))
+	}
+	if len(received[0].Alerts) != 2 {
+		t.Fatalf("expect the batch has 2 alerts, got %d", len(received[0].Alerts))
+	}
+	if got := received[0].CommonAnnotations[ReasonAnnotation]; got != ReasonInitialFiring {
+		t.Fatalf("reason = %s, want %s", got, ReasonInitialFiring)
+	}
+}
+
+func TestBatcherKeepsBatchWhenReceiverReloaded(t *testing.T) {
+
+	b := NewBatcher(log.NewNopLogger(), &config.Config{})
+	data := testGroup("batch-reloaded", testAlert("a", "firing"))
+
+	before := &delayedReceiver{Receiver: newTestWebhook("hook", "http://before"), delay: time.Hour}
+	after := &delayedReceiver{Receiver: newTestWebhook("hook", "http://after"), delay: time.Hour}
+	other := &delayedReceiver{Receiver: newTestWebhook("other", "http://other"), delay: time.Hour}
+
+	b.Add([]config.Receiver{before}, data)
+	// The receiver is reloaded as a new object.
+	b.Add([]config.Receiver{after, other}, data)
+
+	if len(b.batches) != 2 {
+		t.Fatalf("expect 2 batches, got %d", len(b.batches))
+	}
+	for _, bt := range b.batches {
+		bt.timer.Stop()
+		if bt.receiver == before {
+			t.Fatal("expect the batch sent with the reloaded receiver")
+		}
+	}
+}
+
+func TestBatcherSkipsEmptyData(t *testing.T) {
+
+	b := NewBatcher(log.NewNopLogger(), &config.Config{})
+	r := &delayedReceiver{Receiver: newTestWebhook("hook", "http://hook"), delay: time.Hour}
+
+	if direct := b.Add([]config.Receiver{r}, testGroup("batch-empty")); len(direct) != 1 {
+		t.Fatalf("expect the receiver returned, got %d", len(direct))
+	}
+	if len(b.batches) != 0 {
+		t.Fatalf("expect nothing buffered, got %d", len(b.batches))
+	}
+}
+
+func TestMergeBatchReason(t *testing.T) {
+
+	withReason := func(data template.Data, reason string) template.Data {
+		data.CommonAnnotations = template.KV{ReasonAnnotation: reason}
+		return data
+	}
+	a, c := testAlert("a", "firing"), testAlert("b", "firing")
+
+	tests := []struct {
+		name     string
+		buffered template.Data
+		later    template.Data
+		want     string
+	}{
+		{
+			name:     "the reason of the first notification is kept",
+			buffered: withReason(testGroup("batch-reason", a), ReasonInitialFiring),
+			later:    withReason(testGroup("batch-reason", a, c), ReasonUpdateFiring),
+			want:     ReasonInitialFiring,
+		},
+		{
+			name:     "no reason if the first one has none",
+			buffered: testGroup("batch-reason", a),
+			later:    withReason(testGroup("batch-reason", a, c), ReasonUpdateFiring),
+			want:     "",
+		},
+		{
+			name:     "the later one without a reason",
+			buffered: withReason(testGroup("batch-reason", a), ReasonInitialFiring),
+			later:    testGroup("batch-reason", c),
+			want:     ReasonInitialFiring,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := mergeBatch(tt.buffered, tt.later)
+			if got := data.CommonAnnotations[ReasonAnnotation]; got != tt.want {
+				t.Fatalf("reason = %q, want %q", got, tt.want)
+			}
+			if len(data.Alerts) != 2 {
+				t.Fatalf("expect 2 alerts, got %d", len(data.Alerts))
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCoalesceLabel = "alertname"
+	// The common annotation to pass the number of the groups merged into the notification.
+	CoalescedGroupsAnnotation = "coalescedGroups"
+	// The maximum number of the groups buffered, the buffer will be flushed if exceeded.
+	MaxCoalesceGroups = 1000
+)
+
+// Coalescer merges the groups with the same value of the coalesce label received within a window into one notification,
+// such as the groups of the same alert in many namespaces caused by a single root cause.
+type Coalescer struct {
+	logger      log.Logger
+	notifierCfg *config.Config
+	buckets     map[string]*coalesceBucket
+	size        int
+	mutex       sync.Mutex
+	// Send the groups flushed, it can be replaced.
+	sendGroups func(ctx context.Context, groups []*coalesceGroup)
+}
+
+type coalesceBucket struct {
+	groups []*coalesceGroup
+	// The number of the alerts of the groups.
+	alerts int
+	timer  *time.Timer
+}
+
+type coalesceGroup struct {
+	namespace *string
+	data      template.Data
+}
+
+func NewCoalescer(logger log.Logger, notifierCfg *config.Config) *Coalescer {
+
+	c := &Coalescer{
+		logger:      logger,
+		notifierCfg: notifierCfg,
+		buckets:     make(map[string]*coalesceBucket),
+	}
+	c.sendGroups = c.send
+	return c
+}
+
+// Add buffers the group until the window is over or the alerts buffered reach the maximum,
+// it returns false if the group is not coalesced and should be sent directly.
+func (c *Coalescer) Add(ns *string, data template.Data) bool {
+
+	window, label, maxAlerts := c.options()
+	if window <= 0 {
+		return false
+	}
+
+	value := coalesceValue(data, label)
+	if len(value) == 0 {
+		return false
+	}
+
+	key := fmt.Sprintf("%s/%s=%s", data.Receiver, label, value)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.size >= MaxCoalesceGroups {
+		_ = level.Warn(c.logger).Log("msg", "Coalescer: too many groups buffered, flush all", "size", c.size)
+		for k := range c.buckets {
+			c.flushLocked(k)
+		}
+	}
+
+	b, ok := c.buckets[key]
+	if !ok {
+		nb := &coalesceBucket{}
+		nb.timer = time.AfterFunc(window, func() {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			// The bucket may have been flushed by the count while the timer is firing,
+			// the new bucket of the key waits for its own window.
+			if c.buckets[key] == nb {
+				c.flushLocked(key)
+			}
+		})
+		b = nb
+		c.buckets[key] = b
+	}
+
+	b.groups = append(b.groups, &coalesceGroup{
+		namespace: ns,
+		data:      data,
+	})
+	b.alerts += len(data.Alerts)
+	c.size++
+
+	// The bucket is removed when flushed, so the next group of the key starts a new window and a new count.
+	if maxAlerts > 0 && b.alerts >= maxAlerts {
+		c.flushLocked(key)
+	}
+
+	return true
+}
+
+// Flush sends all the buffered groups, it is called when shutting down.
+func (c *Coalescer) Flush(ctx context.Context) {
+
+	c.mutex.Lock()
+	var buckets []*coalesceBucket
+	for k, b := range c.buckets {
+		b.timer.Stop()
+		buckets = append(buckets, b)
+		delete(c.buckets, k)
+	}
+	c.size = 0
+	c.mutex.Unlock()
+
+	for _, b := range buckets {
+		c.sendGroups(ctx, b.groups)
+	}
+}
+
+// Must be called with the mutex held, the groups are sent asynchronously.
+func (c *Coalescer) flushLocked(key string) {
+
+	b, ok := c.buckets[key]
+	if !ok {
+		return
+	}
+
+	b.timer.Stop()
+	delete(c.buckets, key)
+	c.size -= len(b.groups)
+
+	go c.sendGroups(context.Background(), b.groups)
+}
+
+// Send the groups to the receivers, the groups sent to the same receivers are merged into one notification.
+// The global receivers receive all the groups, the tenant receivers only receive the groups of their namespaces.
+func (c *Coalescer) send(ctx context.Context, groups []*coalesceGroup) {
+
+	var receivers []config.Receiver
+	groupsOfReceiver := make(map[config.Receiver][]int)
+	for i, g := range groups {
+		for _, r := range c.notifierCfg.RcvsFromNs(g.namespace) {
+			if _, ok := groupsOfReceiver[r]; !ok {
+				receivers = append(receivers, r)
+			}
+			groupsOfReceiver[r] = append(groupsOfReceiver[r], i)
+		}
+	}
+
+	var keys []string
+	receiversOfGroups := make(map[string][]config.Receiver)
+	indexes := make(map[string][]int)
+	for _, r := range receivers {
+		var s []string
+		for _, i := range groupsOfReceiver[r] {
+			s = append(s, fmt.Sprint(i))
+		}
+		key := strings.Join(s, ",")
+
+		if _, ok := receiversOfGroups[key]; !ok {
+			keys = append(keys, key)
+			indexes[key] = groupsOfReceiver[r]
+		}
+		receiversOfGroups[key] = append(receiversOfGroups[key], r)
+	}
+
+	for _, key := range keys {
+		var ds []template.Data
+		for _, i := range indexes[key] {
+			ds = append(ds, groups[i].data)
+		}
+
+		data := mergeData(ds)
+		if errs := NewNotification(c.logger, receiversOfGroups[key], c.notifierCfg, data).Notify(ctx); len(errs) > 0 {
+			_ = level.Error(c.logger).Log("msg", "Coalescer: send notification error", "group", groupKey(data))
+		}
+	}
+}
+
+func (c *Coalescer) options() (time.Duration, string, int) {
+
+	opts := c.notifierCfg.ReceiverOpts
+	if opts == nil || opts.Global == nil || opts.Global.Coalesce == nil {
+		return 0, "", 0
+	}
+
+	label := opts.Global.Coalesce.Label
+	if len(label) == 0 {
+		label = DefaultCoalesceLabel
+	}
+
+	return opts.Global.Coalesce.Window, label, opts.Global.Coalesce.MaxAlerts
+}
+
+// The value of the coalesce label shared by all the alerts of the group.
+func coalesceValue(data template.Data, label string) string {
+
+	if v, ok := data.CommonLabels[label]; ok {
+		return v
+	}
+
+	value := ""
+	for _, alert := range data.Alerts {
+		v := alert.Labels[label]
+		if len(v) == 0 || (len(value) > 0 && v != value) {
+			return ""
+		}
+		value = v
+	}
+
+	return value
+}
+
+// Merge the groups into one, the common labels and annotations are the ones shared by all the groups.
+// The alerts are deduplicated by the fingerprint and the latest one is kept, as a group may be sent again within the window.
+func mergeData(ds []template.Data) template.Data {
+
+	if len(ds) == 1 {
+		return ds[0]
+	}
+
+	data := template.Data{
+		Receiver:          ds[0].Receiver,
+		Status:            string(model.AlertResolved),
+		GroupLabels:       template.KV{},
+		CommonLabels:      copyKV(ds[0].CommonLabels),
+		CommonAnnotations: copyKV(ds[0].CommonAnnotations),
+		ExternalURL:       ds[0].ExternalURL,
+	}
+
+	index := make(map[string]int)
+	groups := make(map[string]bool)
+	for _, d := range ds {
+		for _, a := range d.Alerts {
+			key := fingerprint(a)
+			if i, ok := index[key]; ok {
+				data.Alerts[i] = a
+				continue
+			}
+			index[key] = len(data.Alerts)
+			data.Alerts = append(data.Alerts, a)
+		}
+		groups[groupKey(d)] = true
+		intersectKV(data.CommonLabels, d.CommonLabels)
+		intersectKV(data.CommonAnnotations, d.CommonAnnotations)
+	}
+
+	if len(data.Alerts.Firing()) > 0 {
+		data.Status = string(model.AlertFiring)
+	}
+
+	// The group labels shared by all the groups are kept, such as the coalesce label.
+	for k, v := range ds[0].GroupLabels {
+		data.GroupLabels[k] = v
+	}
+	for _, d := range ds {
+		intersectKV(data.GroupLabels, d.GroupLabels)
+	}
+
+	sort.SliceStable(data.Alerts, func(i, j int) bool {
+		return data.Alerts[i].Labels["namespace"] < data.Alerts[j].Labels["namespace"]
+	})
+
+	data.CommonAnnotations[CoalescedGroupsAnnotation] = fmt.Sprint(len(groups))
+	return data
+}
+
+// The fingerprint of the alert, it is computed by the labels if alertmanager does not send it.
+func fingerprint(alert template.Alert) string {
+
+	if len(alert.Fingerprint) > 0 {
+		return alert.Fingerprint
+	}
+
+	return notifier.KvToLabelSet(alert.Labels).Fingerprint().String()
+}
+
+func intersectKV(dst, src template.KV) {
+
+	for k, v := range dst {
+		if src[k] != v {
+			delete(dst, k)
+		}
+	}
+}
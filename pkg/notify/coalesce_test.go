@@ -0,0 +1,242 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// A coalescer recording the numbers of the alerts of the flushes instead of sending them.
+func newTestCoalescer(t *testing.T, window time.Duration, maxAlerts int) (*Coalescer, func() []int) {
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		Coalesce: &v1alpha1.Coalesce{Window: window, MaxAlerts: maxAlerts},
+	}}}
+	c := NewCoalescer(log.NewNopLogger(), cfg)
+
+	var mutex sync.Mutex
+	var flushed []int
+	c.sendGroups = func(_ context.Context, groups []*coalesceGroup) {
+		n := 0
+		for _, g := range groups {
+			n += len(g.data.Alerts)
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		flushed = append(flushed, n)
+	}
+	t.Cleanup(func() { c.Flush(context.Background()) })
+
+	return c, func() []int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]int(nil), flushed...)
+	}
+}
+
+// A group of the alerts in the namespace.
+func coalesceGroupOf(namespace string, alerts int) template.Data {
+
+	d := testGroup("coalesce")
+	d.CommonLabels = template.KV{"alertname": "KubePodCrashLooping", "namespace": namespace}
+	for i := 0; i < alerts; i++ {
+		d.Alerts = append(d.Alerts, testAlert(fmt.Sprintf("%s-%d", namespace, i), "firing", "namespace", namespace))
+	}
+	return d
+}
+
+func TestCoalescerMergesGroups(t *testing.T) {
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		Coalesce: &v1alpha1.Coalesce{Window: time.Hour},
+	}}}
+	c := NewCoalescer(log.NewNopLogger(), cfg)
+
+	var flushed [][]*coalesceGroup
+	c.sendGroups = func(_ context.Context, groups []*coalesceGroup) {
+		flushed = append(flushed, groups)
+	}
+
+	for i := 0; i < 5; i++ {
+		ns := fmt.Sprintf("ns-%d", i)
+		if !c.Add(&ns, coalesceGroupOf(ns, 1)) {
+			t.Fatalf("expect group %d coalesced", i)
+		}
+	}
+	c.Flush(context.Background())
+
+	if len(flushed) != 1 || len(flushed[0]) != 5 {
+		t.Fatalf("expect 5 groups flushed together, got %d flushes", len(flushed))
+	}
+
+	var ds []template.Data
+	for _, g := range flushed[0] {
+		ds = append(ds, g.data)
+	}
+	data := mergeData(ds)
+	if len(data.Alerts) != 5 {
+		t.Fatalf("expect one notification of 5 alerts, got %d alerts", len(data.Alerts))
+	}
+	if got := data.CommonAnnotations[CoalescedGroupsAnnotation]; got != "5" {
+		t.Fatalf("coalesced groups = %q, want 5", got)
+	}
+	if _, ok := data.CommonLabels["namespace"]; ok || data.CommonLabels["alertname"] != "KubePodCrashLooping" {
+		t.Fatalf("unexpected common labels %v", data.CommonLabels)
+	}
+}
+
+func TestMergeData(t *testing.T) {
+
+	resolved := coalesceGroupOf("a", 2)
+	resolved.Alerts[1].Status = "resolved"
+
+	allResolved := coalesceGroupOf("a", 2)
+	allResolved.Status = "resolved"
+	for i := range allResolved.Alerts {
+		allResolved.Alerts[i].Status = "resolved"
+	}
+
+	tests := []struct {
+		name   string
+		groups []template.Data
+		// The statuses of the merged alerts, in order.
+		want       []string
+		wantStatus string
+		wantGroups string
+	}{
+		{
+			name:       "groups of namespaces",
+			groups:     []template.Data{coalesceGroupOf("b", 1), coalesceGroupOf("a", 2)},
+			want:       []string{"firing", "firing", "firing"},
+			wantStatus: "firing",
+			wantGroups: "2",
+		},
+		{
+			name:       "re-sent group keeps the latest",
+			groups:     []template.Data{coalesceGroupOf("a", 2), coalesceGroupOf("b", 1), resolved},
+			want:       []string{"firing", "resolved", "firing"},
+			wantStatus: "firing",
+			wantGroups: "2",
+		},
+		{
+			name:       "re-sent group resolved",
+			groups:     []template.Data{coalesceGroupOf("a", 2), allResolved},
+			want:       []string{"resolved", "resolved"},
+			wantStatus: "resolved",
+			wantGroups: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := mergeData(tt.groups)
+
+			var got []string
+			for _, a := range data.Alerts {
+				got = append(got, a.Status)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("alerts = %v, want %v", got, tt.want)
+			}
+			if data.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", data.Status, tt.wantStatus)
+			}
+			if got := data.CommonAnnotations[CoalescedGroupsAnnotation]; got != tt.wantGroups {
+				t.Fatalf("coalesced groups = %q, want %q", got, tt.wantGroups)
+			}
+		})
+	}
+}
+
+// Wait until the flushes reach the number, the flushes are sent asynchronously.
+func waitFlushed(flushed func() []int, n int, timeout time.Duration) []int {
+
+	for deadline := time.Now().Add(timeout); len(flushed()) < n && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return flushed()
+}
+
+func TestCoalescerFlushesOnMaxAlerts(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		maxAlerts int
+		groups    []int
+		// The alerts of the flushes before the window is over, in ascending order.
+		want []int
+		// The alerts buffered in the new window after the flushes.
+		buffered int
+	}{
+		{"below the maximum", 5, []int{1, 2}, nil, 3},
+		{"reach the maximum", 3, []int{1, 2}, []int{3}, 0},
+		{"exceed the maximum", 3, []int{2, 4}, []int{6}, 0},
+		{"new window after the flush", 3, []int{2, 2, 1, 1}, []int{4}, 2},
+		{"flushed twice", 2, []int{1, 1, 3}, []int{2, 3}, 0},
+		{"no maximum", 0, []int{5, 5}, nil, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, flushed := newTestCoalescer(t, time.Hour, tt.maxAlerts)
+			for i, n := range tt.groups {
+				ns := fmt.Sprintf("ns-%d", i)
+				if !c.Add(&ns, coalesceGroupOf(ns, n)) {
+					t.Fatalf("expect group %d coalesced", i)
+				}
+			}
+
+			// The flushes are sent concurrently, so they are compared regardless of the order.
+			got := waitFlushed(flushed, len(tt.want), time.Second)
+			sort.Ints(got)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("flushed %v, want %v", got, tt.want)
+			}
+
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			buffered := 0
+			for _, b := range c.buckets {
+				buffered += b.alerts
+			}
+			if buffered != tt.buffered {
+				t.Fatalf("buffered %d alerts, want %d", buffered, tt.buffered)
+			}
+		})
+	}
+}
+
+func TestCoalescerFlushesOnWindow(t *testing.T) {
+
+	c, flushed := newTestCoalescer(t, 50*time.Millisecond, 10)
+	for _, ns := range []string{"a", "b"} {
+		namespace := ns
+		c.Add(&namespace, coalesceGroupOf(namespace, 1))
+	}
+
+	if got := waitFlushed(flushed, 1, time.Second); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expect the groups flushed together when the window is over, got %v", got)
+	}
+
+	// The flush resets the bucket, the next group waits for a new window and counts from zero.
+	c.mutex.Lock()
+	if len(c.buckets) != 0 || c.size != 0 {
+		c.mutex.Unlock()
+		t.Fatalf("expect the buffer empty after the flush, got %d buckets of %d groups", len(c.buckets), c.size)
+	}
+	c.mutex.Unlock()
+
+	ns := "c"
+	c.Add(&ns, coalesceGroupOf(ns, 9))
+	if got := waitFlushed(flushed, 2, time.Second); len(got) != 2 || got[1] != 9 {
+		t.Fatalf("expect the next group flushed in its own window, got %v", got)
+	}
+}
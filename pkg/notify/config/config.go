@@ -6,6 +6,7 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -22,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	kconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -36,9 +38,16 @@ const (
 	slack               = "slack"
 	webhook             = "webhook"
 	dingtalk            = "dingtalk"
+	elasticsearch       = "elasticsearch"
+	splunk              = "splunk"
+	grpc                = "grpc"
+	pushgateway         = "pushgateway"
 	opAdd               = "add"
 	opDel               = "delete"
 	opGet               = "get"
+	opDiff              = "diff"
+	opApply             = "apply"
+	opGetByName         = "getByName"
 	tenantKeyNamespace  = "namespace"
 )
 
@@ -51,6 +60,8 @@ type Config struct {
 	ctx    context.Context
 	cache  cache.Cache
 	client client.Client
+	// The client reading from the apiserver directly, for the objects not cached such as pods.
+	reader client.Reader
 	// Default config selector
 	defaultConfigSelector *metav1.LabelSelector
 	// Default config for email, wechat, slack etc.
@@ -70,6 +81,13 @@ type Config struct {
 	nmNamespaces []string
 	// Dose the notification manager crd add.
 	nmAdd bool
+	// The receivers in use if the changes are applied manually, in the same form as `receivers`.
+	// It is nil if the changes take effect immediately.
+	applied map[string]map[string]Receiver
+	// Functions called when a receiver is changed.
+	receiverHandlers []func(key string, r Receiver)
+	optionsHandlers  []func(opts *v1alpha1.Options)
+	handlerMutex     sync.Mutex
 }
 
 type param struct {
@@ -94,6 +112,7 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 	scheme := runtime.NewScheme()
 	_ = v1alpha1.AddToScheme(scheme)
 	_ = v1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
 	_ = rbacv1.AddToScheme(scheme)
 
 	cfg, err := kconfig.GetConfig()
@@ -112,7 +131,7 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 
 		// Notification manager namespaces must include the namespace notification manager in.
 		nmNamespaces = strings.Split(namespaces, ":")
-		if !sliceIn(nmNamespaces, ns) {
+		if !StringIn(nmNamespaces, ns) {
 			nmNamespaces = append(nmNamespaces, ns)
 		}
 		ncf := cache.MultiNamespacedCacheBuilder(nmNamespaces)
@@ -129,7 +148,7 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 		return nil, err
 	}
 
-	c, err := newClient(cfg, informerCache, scheme)
+	c, reader, err := newClient(cfg, informerCache, scheme)
 	if err != nil {
 		_ = level.Error(logger).Log("msg", "Failed to create client", "err", err)
 		return nil, err
@@ -162,6 +181,19 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 		func() runtime.Object {
 			return &v1alpha1.DingTalkConfigList{}
 		})
+	register(elasticsearch, NewElasticsearchReceiver,
+		func() runtime.Object {
+			return &v1alpha1.ElasticsearchReceiver{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.ElasticsearchReceiverList{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.ElasticsearchConfig{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.ElasticsearchConfigList{}
+		})
 	register(email, NewEmailReceiver,
 		func() runtime.Object {
 			return &v1alpha1.EmailReceiver{}
@@ -188,6 +220,45 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 		func() runtime.Object {
 			return &v1alpha1.SlackConfigList{}
 		})
+	register(grpc, NewGrpcReceiver,
+		func() runtime.Object {
+			return &v1alpha1.GrpcReceiver{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.GrpcReceiverList{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.GrpcConfig{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.GrpcConfigList{}
+		})
+	register(pushgateway, NewPushgatewayReceiver,
+		func() runtime.Object {
+			return &v1alpha1.PushgatewayReceiver{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.PushgatewayReceiverList{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.PushgatewayConfig{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.PushgatewayConfigList{}
+		})
+	register(splunk, NewSplunkReceiver,
+		func() runtime.Object {
+			return &v1alpha1.SplunkReceiver{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.SplunkReceiverList{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.SplunkConfig{}
+		},
+		func() runtime.Object {
+			return &v1alpha1.SplunkConfigList{}
+		})
 	register(webhook, NewWebhookReceiver,
 		func() runtime.Object {
 			return &v1alpha1.WebhookReceiver{}
@@ -220,6 +291,7 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 		logger:                 logger,
 		cache:                  informerCache,
 		client:                 c,
+		reader:                 reader,
 		defaultConfig:          make(map[string]interface{}),
 		tenantKey:              defaultTenantKey,
 		defaultConfigSelector:  nil,
@@ -233,18 +305,18 @@ func New(ctx context.Context, logger log.Logger, namespaces string) (*Config, er
 	}, nil
 }
 
-// Setting up client
-func newClient(cfg *rest.Config, cache cache.Cache, scheme *runtime.Scheme) (client.Client, error) {
+// Setting up client, it returns the client reading from the cache and the one reading from the apiserver.
+func newClient(cfg *rest.Config, cache cache.Cache, scheme *runtime.Scheme) (client.Client, client.Reader, error) {
 	mapper, err := func(c *rest.Config) (meta.RESTMapper, error) {
 		return apiutil.NewDynamicRESTMapper(c)
 	}(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	c, err := client.New(cfg, client.Options{Scheme: scheme, Mapper: mapper})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	return &client.DelegatingClient{
@@ -254,7 +326,7 @@ func newClient(cfg *rest.Config, cache cache.Cache, scheme *runtime.Scheme) (cli
 		},
 		Writer:       c,
 		StatusClient: c,
-	}, nil
+	}, c, nil
 }
 
 func (c *Config) Run() error {
@@ -356,10 +428,25 @@ func (c *Config) onChange(obj interface{}, op, opType string, isConfig bool) {
 
 func (c *Config) sync(p *param) {
 
+	if p.op == opGetByName {
+		// Return the receivers named `name` of all tenants via the done channel
+		var rcvs []Receiver
+		for _, v := range c.activeReceivers() {
+			for k, r := range v {
+				if k[strings.LastIndex(k, "/")+1:] == p.name {
+					rcvs = append(rcvs, r)
+				}
+			}
+		}
+		p.done <- rcvs
+		return
+	}
+
 	if p.op == opGet {
+		receivers := c.activeReceivers()
 		// Return all receivers of the specified tenant (map[opType/namespace/name]*Receiver)
 		// via the done channel if exists
-		if v, exist := c.receivers[p.tenantID]; exist {
+		if v, exist := receivers[p.tenantID]; exist {
 			p.done <- v
 			// Return empty struct if receivers of the specified tenant cannot be found
 		} else {
@@ -368,6 +455,21 @@ func (c *Config) sync(p *param) {
 		return
 	}
 
+	if p.op == opDiff {
+		p.done <- DiffReceivers(allReceivers(c.activeReceivers()), allReceivers(c.receivers))
+		return
+	}
+
+	if p.op == opApply {
+		if c.applied != nil {
+			applied := cloneReceivers(c.receivers)
+			c.changesApplied(c.applied, applied)
+			c.applied = applied
+		}
+		p.done <- struct{}{}
+		return
+	}
+
 	if p.opType == notificationManager {
 		c.nmChange(p)
 		return
@@ -397,6 +499,7 @@ func (c *Config) sync(p *param) {
 					for k := range c.receivers[tenantID] {
 						if strings.HasPrefix(k, p.opType) && c.receivers[tenantID][k].UseDefault() {
 							_ = c.receivers[tenantID][k].SetConfig(config)
+							c.receiverChanged(k, c.receivers[tenantID][k])
 						}
 					}
 				}
@@ -407,6 +510,7 @@ func (c *Config) sync(p *param) {
 						if strings.HasPrefix(k, p.opType) {
 							_ = c.receivers[p.tenantID][k].SetConfig(config)
 							c.receivers[p.tenantID][k].SetUseDefault(false)
+							c.receiverChanged(k, c.receivers[p.tenantID][k])
 						}
 					}
 				}
@@ -425,6 +529,7 @@ func (c *Config) sync(p *param) {
 				c.receivers[p.tenantID] = make(map[string]Receiver)
 			}
 			c.receivers[p.tenantID][rcvKey] = p.receiver
+			c.receiverChanged(rcvKey, p.receiver)
 		}
 	} else if p.op == opDel {
 		if p.isConfig {
@@ -449,6 +554,7 @@ func (c *Config) sync(p *param) {
 							if dc, ok := c.defaultConfig[p.opType]; ok {
 								_ = c.receivers[p.tenantID][k].SetConfig(dc)
 							}
+							c.receiverChanged(k, c.receivers[p.tenantID][k])
 						}
 					}
 				}
@@ -458,6 +564,7 @@ func (c *Config) sync(p *param) {
 			rcvKey := fmt.Sprintf("%s/%s/%s", p.opType, p.namespace, p.name)
 			if _, exist := c.receivers[p.tenantID]; exist {
 				delete(c.receivers[p.tenantID], rcvKey)
+				c.receiverChanged(rcvKey, nil)
 				// If the tenant has no receiver, delete it
 				if len(c.receivers[p.tenantID]) <= 0 {
 					delete(c.receivers, p.tenantID)
@@ -469,6 +576,76 @@ func (c *Config) sync(p *param) {
 	p.done <- struct{}{}
 }
 
+// OnReceiverChange registers a function which is called when a receiver is added or updated, or its config is changed,
+// the key is in form of type/namespace/name, and the receiver is nil if it is deleted.
+// If the changes are applied manually, the function is called when they are applied.
+// The function is called in the goroutine syncing the receivers, so it must not block.
+func (c *Config) OnReceiverChange(f func(key string, r Receiver)) {
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	c.receiverHandlers = append(c.receiverHandlers, f)
+}
+
+// The handlers are called when the changes take effect, that is, when the changes are applied if they are applied manually.
+func (c *Config) receiverChanged(key string, r Receiver) {
+
+	if c.applied != nil {
+		return
+	}
+
+	c.callReceiverHandlers(key, r)
+}
+
+// Call the handlers with the changes of the receivers which take effect.
+func (c *Config) changesApplied(previous, current map[string]map[string]Receiver) {
+
+	receivers := allReceivers(current)
+	d := DiffReceivers(allReceivers(previous), receivers)
+	for _, k := range d.Added {
+		c.callReceiverHandlers(k, receivers[k])
+	}
+	for _, m := range d.Modified {
+		c.callReceiverHandlers(m.Receiver, receivers[m.Receiver])
+	}
+	for _, k := range d.Removed {
+		c.callReceiverHandlers(k, nil)
+	}
+}
+
+func (c *Config) callReceiverHandlers(key string, r Receiver) {
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	for _, f := range c.receiverHandlers {
+		f(key, r)
+	}
+}
+
+// OnOptionsChange registers a function which is called with the current options when it is registered, as the config
+// may have been synced, and then when the options of the notification manager are changed, the options are nil
+// if the notification manager is deleted. It is called in the goroutine syncing the receivers too.
+func (c *Config) OnOptionsChange(f func(opts *v1alpha1.Options)) {
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	c.optionsHandlers = append(c.optionsHandlers, f)
+	f(c.ReceiverOpts)
+}
+
+func (c *Config) optionsChanged(opts *v1alpha1.Options) {
+
+	c.handlerMutex.Lock()
+	defer c.handlerMutex.Unlock()
+
+	for _, f := range c.optionsHandlers {
+		f(opts)
+	}
+}
+
 func (c *Config) nmChange(p *param) {
 	if p.op == opAdd {
 		c.tenantKey = p.tenantKey
@@ -487,10 +664,56 @@ func (c *Config) nmChange(p *param) {
 		c.defaultConfigSelector = nil
 		c.ReceiverOpts = nil
 	}
+	c.optionsChanged(c.ReceiverOpts)
+
+	if !c.manualApply() {
+		// The pending changes take effect.
+		if c.applied != nil {
+			c.changesApplied(c.applied, c.receivers)
+		}
+		c.applied = nil
+	} else if c.applied == nil {
+		c.applied = cloneReceivers(c.receivers)
+	}
 
 	p.done <- struct{}{}
 }
 
+// The receivers used to send notifications, the applied ones if the changes are applied manually.
+func (c *Config) activeReceivers() map[string]map[string]Receiver {
+
+	if c.applied != nil {
+		return c.applied
+	}
+
+	return c.receivers
+}
+
+func (c *Config) manualApply() bool {
+	return c.ReceiverOpts != nil && c.ReceiverOpts.Global != nil && c.ReceiverOpts.Global.ManualApply
+}
+
+// PendingDiff returns the changes of the receivers which are not applied yet.
+// It is always empty if the changes take effect immediately.
+func (c *Config) PendingDiff() *ReceiverDiff {
+
+	p := &param{}
+	p.op = opDiff
+	p.done = make(chan interface{}, 1)
+	c.ch <- p
+	return (<-p.done).(*ReceiverDiff)
+}
+
+// Apply makes the pending changes of the receivers take effect, nothing to do if the changes take effect immediately.
+func (c *Config) Apply() {
+
+	p := &param{}
+	p.op = opApply
+	p.done = make(chan interface{}, 1)
+	c.ch <- p
+	<-p.done
+}
+
 func (c *Config) tenantIDFromNs(namespace *string) ([]string, error) {
 	tenantIDs := make([]string, 0)
 	// Use namespace as TenantID directly if tenantKey is "namespace"
@@ -569,6 +792,44 @@ func (c *Config) RcvsFromNs(namespace *string) []Receiver {
 	return rcvs
 }
 
+// RcvsFromName returns the receivers whose resources are named `name`, of all tenants.
+func (c *Config) RcvsFromName(name string) []Receiver {
+
+	p := param{}
+	p.op = opGetByName
+	p.name = name
+	p.done = make(chan interface{}, 1)
+	c.ch <- &p
+	rcvs, _ := (<-p.done).([]Receiver)
+	return rcvs
+}
+
+// RcvsFromNameInNs returns the receivers named `name` which the alerts of the namespace can be sent to,
+// they are the global receivers and the receivers of the tenants of the namespace. Only the global receivers
+// are returned if the namespace is nil.
+func (c *Config) RcvsFromNameInNs(name string, namespace *string) []Receiver {
+
+	tenantIDs := map[string]bool{globalTenantID: true}
+	if namespace != nil {
+		if ids, err := c.tenantIDFromNs(namespace); err != nil {
+			_ = level.Error(c.logger).Log("msg", "Unable to find tenantID", "err", err)
+		} else {
+			for _, id := range ids {
+				tenantIDs[id] = true
+			}
+		}
+	}
+
+	var rcvs []Receiver
+	for _, r := range c.RcvsFromName(name) {
+		if tenantIDs[r.GetTenantID()] {
+			rcvs = append(rcvs, r)
+		}
+	}
+
+	return rcvs
+}
+
 func (c *Config) onNmAdd(obj interface{}) {
 	if nm, ok := obj.(*v1alpha1.NotificationManager); ok {
 		p := &param{}
@@ -767,6 +1028,11 @@ func (c *Config) OutputReceiver(tenant, receiver string) interface{} {
 	return m
 }
 
+// Reader returns the client reading the objects from the apiserver directly.
+func (c *Config) Reader() client.Reader {
+	return c.reader
+}
+
 func (c *Config) GetSecretData(namespace string, selector *v1.SecretKeySelector) (string, error) {
 
 	if selector == nil {
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+)
+
+// A config serving the receivers without the kubernetes resources, the tenant of a namespace is the namespace.
+func newTestConfig(t *testing.T, receivers map[string]map[string]Receiver) *Config {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := &Config{
+		ctx:       ctx,
+		logger:    log.NewNopLogger(),
+		tenantKey: tenantKeyNamespace,
+		receivers: receivers,
+		ch:        make(chan *param),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case p := <-c.ch:
+				c.sync(p)
+			}
+		}
+	}()
+
+	return c
+}
+
+func newTestEmail(tenantID, namespace, name string) Receiver {
+
+	r := NewEmailReceiver()
+	r.SetTenantID(tenantID)
+	r.SetName(name)
+	r.SetNamespace(namespace)
+	return r
+}
+
+func TestRcvsFromNameInNs(t *testing.T) {
+
+	c := newTestConfig(t, map[string]map[string]Receiver{
+		globalTenantID: {"email//oncall": newTestEmail(globalTenantID, "", "oncall")},
+		"team-a":       {"email/team-a/oncall": newTestEmail("team-a", "team-a", "oncall")},
+		"team-b":       {"email/team-b/oncall": newTestEmail("team-b", "team-b", "oncall")},
+	})
+
+	namespace := func(ns string) *string { return &ns }
+	tests := []struct {
+		name      string
+		namespace *string
+		want      []string
+	}{
+		{"tenant", namespace("team-a"), []string{"/oncall", "team-a/oncall"}},
+		{"other tenant", namespace("team-b"), []string{"/oncall", "team-b/oncall"}},
+		{"no tenant", namespace("team-c"), []string{"/oncall"}},
+		{"no namespace", nil, []string{"/oncall"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range c.RcvsFromNameInNs("oncall", tt.namespace) {
+				got = append(got, r.GetNamespace()+"/"+r.GetName())
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("RcvsFromNameInNs() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("RcvsFromNameInNs() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if rcvs := c.RcvsFromName("oncall"); len(rcvs) != 3 {
+		t.Fatalf("expect the receivers of all tenants, got %d", len(rcvs))
+	}
+}
+
+func TestOptionsHandlers(t *testing.T) {
+
+	initial := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{ContextPrecedence: "labels"}}
+	c := &Config{ReceiverOpts: initial}
+
+	var got []*v1alpha1.Options
+	c.OnOptionsChange(func(opts *v1alpha1.Options) {
+		got = append(got, opts)
+	})
+
+	updated := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{ContextPrecedence: "annotations"}}
+	tests := []struct {
+		name string
+		p    *param
+		want *v1alpha1.Options
+	}{
+		{"updated", &param{op: opAdd, opType: notificationManager, ReceiverOpts: updated}, updated},
+		{"deleted", &param{op: opDel, opType: notificationManager}, nil},
+	}
+
+	// The handler is called with the current options when it is registered.
+	if len(got) != 1 || got[0] != initial {
+		t.Fatalf("expect the handler called with the current options, got %v", got)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			tt.p.done = make(chan interface{}, 1)
+			c.sync(tt.p)
+			<-tt.p.done
+			if len(got) != 1 || got[0] != tt.want {
+				t.Fatalf("handler called with %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package config
+
+import (
+	"fmt"
+	"k8s.io/api/core/v1"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// The value shown instead of the secrets in the diff.
+	MaskedValue = "******"
+)
+
+// The fields which are masked in the diff besides the secret selectors, matched case-insensitively by the suffix of the name.
+var sensitiveFields = []string{"password", "secret", "token"}
+
+// ReceiverDiff is the difference between two sets of receivers, the receivers are in form of type/namespace/name.
+type ReceiverDiff struct {
+	Added    []string         `json:"added,omitempty"`
+	Removed  []string         `json:"removed,omitempty"`
+	Modified []ReceiverChange `json:"modified,omitempty"`
+}
+
+// ReceiverChange is the fields changed of a receiver, such as `EmailConfig.SmartHost.Host`.
+type ReceiverChange struct {
+	Receiver string        `json:"receiver"`
+	Fields   []FieldChange `json:"fields"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Empty returns true if there is no difference.
+func (d *ReceiverDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// DiffReceivers compares the current receivers with the proposed ones, the receivers are in form of map[type/namespace/name]Receiver.
+// The secrets are masked, a changed secret is reported without its value.
+func DiffReceivers(current, proposed map[string]Receiver) *ReceiverDiff {
+
+	d := &ReceiverDiff{}
+	for k, r := range proposed {
+		old, ok := current[k]
+		if !ok {
+			d.Added = append(d.Added, k)
+			continue
+		}
+
+		if fields := diffFields(newFields(old), newFields(r)); len(fields) > 0 {
+			d.Modified = append(d.Modified, ReceiverChange{Receiver: k, Fields: fields})
+		}
+	}
+
+	for k := range current {
+		if _, ok := proposed[k]; !ok {
+			d.Removed = append(d.Removed, k)
+		}
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Slice(d.Modified, func(i, j int) bool {
+		return d.Modified[i].Receiver < d.Modified[j].Receiver
+	})
+
+	return d
+}
+
+func diffFields(old, new *fields) []FieldChange {
+
+	var changes []FieldChange
+	for k, v := range new.values {
+		if o, ok := old.values[k]; !ok || o != v {
+			changes = append(changes, FieldChange{Field: k, Old: o, New: v})
+		}
+	}
+
+	for k, o := range old.values {
+		if _, ok := new.values[k]; !ok {
+			changes = append(changes, FieldChange{Field: k, Old: o})
+		}
+	}
+
+	for i := range changes {
+		f := changes[i].Field
+		if old.secrets[f] || new.secrets[f] || isSensitive(f) {
+			changes[i].Old = mask(changes[i].Old)
+			changes[i].New = mask(changes[i].New)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+// The flattened fields of a receiver, the embedded common fields have no prefix.
+type fields struct {
+	// In form of map[path]value, such as `EmailConfig.SmartHost.Host`.
+	values map[string]string
+	// The paths of the secret selectors.
+	secrets map[string]bool
+}
+
+func newFields(r Receiver) *fields {
+
+	f := &fields{
+		values:  make(map[string]string),
+		secrets: make(map[string]bool),
+	}
+	f.flatten("", reflect.ValueOf(r))
+	return f
+}
+
+func (f *fields) flatten(path string, v reflect.Value) {
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == reflect.TypeOf(v1.SecretKeySelector{}) {
+		// The selector is compared as a whole, and it is masked as a secret.
+		f.values[path] = fmt.Sprintf("%s/%s", v.FieldByName("Name"), v.FieldByName("Key"))
+		f.secrets[path] = true
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			p := field.Name
+			if field.Anonymous {
+				p = path
+			} else if len(path) > 0 {
+				p = path + "." + field.Name
+			}
+
+			f.flatten(p, v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		// The elements are compared by the index, such as `AnnotationSection[0].Name`.
+		for i := 0; i < v.Len(); i++ {
+			f.flatten(fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			f.flatten(fmt.Sprintf("%s[%v]", path, k), v.MapIndex(k))
+		}
+	case reflect.Func, reflect.Chan:
+		// Nothing to compare.
+	default:
+		f.values[path] = fmt.Sprint(v)
+	}
+}
+
+func isSensitive(field string) bool {
+
+	name := strings.ToLower(field[strings.LastIndex(field, ".")+1:])
+	for _, s := range sensitiveFields {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mask(v string) string {
+
+	if len(v) == 0 {
+		return v
+	}
+
+	return MaskedValue
+}
+
+// Copy the receivers deeply, so the copies are not affected by the later changes of the receivers.
+func cloneReceivers(receivers map[string]map[string]Receiver) map[string]map[string]Receiver {
+
+	m := make(map[string]map[string]Receiver)
+	for tenantID, rcvs := range receivers {
+		m[tenantID] = make(map[string]Receiver)
+		for k, r := range rcvs {
+			m[tenantID][k] = cloneReceiver(r)
+		}
+	}
+
+	return m
+}
+
+func cloneReceiver(r Receiver) Receiver {
+
+	c := deepCopy(reflect.ValueOf(r)).Interface().(Receiver)
+
+	// The common fields are unexported, so the copy shares them with `r` until they are replaced.
+	if cr, ok := r.(interface{ commonFields() *common }); ok && cr.commonFields() != nil {
+		common := *cr.commonFields()
+		setCommon(c, &common)
+	}
+
+	return c
+}
+
+// Replace the common fields of the receiver.
+func setCommon(r Receiver, c *common) {
+
+	switch v := r.(type) {
+	case *Email:
+		v.common = c
+	case *Wechat:
+		v.common = c
+	case *Slack:
+		v.common = c
+	case *Webhook:
+		v.common = c
+	case *DingTalk:
+		v.common = c
+	case *Elasticsearch:
+		v.common = c
+	case *Splunk:
+		v.common = c
+	case *Grpc:
+		v.common = c
+	case *Pushgateway:
+		v.common = c
+	}
+}
+
+// Copy the value deeply, the unexported fields of the structs are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Elem().Type())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// Merge the receivers of all tenants, in form of map[type/namespace/name]Receiver.
+func allReceivers(receivers map[string]map[string]Receiver) map[string]Receiver {
+
+	m := make(map[string]Receiver)
+	for _, rcvs := range receivers {
+		for k, r := range rcvs {
+			m[k] = r
+		}
+	}
+
+	return m
+}
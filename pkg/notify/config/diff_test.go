@@ -0,0 +1,153 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"k8s.io/api/core/v1"
+)
+
+func testEmail(host string, password string) *Email {
+
+	e := NewEmail([]string{"ops@example.com"})
+	e.SetNamespace("default")
+	enabled := true
+	e.SourceLink = &enabled
+	e.AnnotationSection = []v1alpha1.AnnotationField{{Name: "summary"}}
+	e.EmailConfig = &EmailConfig{
+		From:      "alerts@example.com",
+		SmartHost: v1alpha1.HostPort{Host: host, Port: "25"},
+		AuthPassword: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "smtp"},
+			Key:                  password,
+		},
+	}
+	return e
+}
+
+func TestDiffReceivers(t *testing.T) {
+
+	renamed := testEmail("smtp.example.com", "password")
+	renamed.AnnotationSection[0].DisplayName = "Summary"
+
+	tests := []struct {
+		name     string
+		current  map[string]Receiver
+		proposed map[string]Receiver
+		want     *ReceiverDiff
+	}{
+		{
+			name:     "unchanged pointer fields",
+			current:  map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			proposed: map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			want:     &ReceiverDiff{},
+		},
+		{
+			name:     "added and removed",
+			current:  map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			proposed: map[string]Receiver{"email/default/b": testEmail("smtp.example.com", "password")},
+			want:     &ReceiverDiff{Added: []string{"email/default/b"}, Removed: []string{"email/default/a"}},
+		},
+		{
+			name:     "changed",
+			current:  map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			proposed: map[string]Receiver{"email/default/a": testEmail("mail.example.com", "password")},
+			want: &ReceiverDiff{Modified: []ReceiverChange{{
+				Receiver: "email/default/a",
+				Fields:   []FieldChange{{Field: "EmailConfig.SmartHost.Host", Old: "smtp.example.com", New: "mail.example.com"}},
+			}}},
+		},
+		{
+			name:     "slice element changed",
+			current:  map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			proposed: map[string]Receiver{"email/default/a": renamed},
+			want: &ReceiverDiff{Modified: []ReceiverChange{{
+				Receiver: "email/default/a",
+				Fields:   []FieldChange{{Field: "AnnotationSection[0].DisplayName", New: "Summary"}},
+			}}},
+		},
+		{
+			name:     "secret masked",
+			current:  map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "password")},
+			proposed: map[string]Receiver{"email/default/a": testEmail("smtp.example.com", "new-password")},
+			want: &ReceiverDiff{Modified: []ReceiverChange{{
+				Receiver: "email/default/a",
+				Fields:   []FieldChange{{Field: "EmailConfig.AuthPassword", Old: MaskedValue, New: MaskedValue}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffReceivers(tt.current, tt.proposed); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("diff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneReceivers(t *testing.T) {
+
+	e := testEmail("smtp.example.com", "password")
+	receivers := map[string]map[string]Receiver{"default": {"email/default/a": e}}
+	cloned := cloneReceivers(receivers)
+
+	e.EmailConfig.SmartHost.Host = "mail.example.com"
+	*e.SourceLink = false
+	e.AnnotationSection[0].Name = "description"
+	e.SetNamespace("kubesphere-system")
+
+	c := cloned["default"]["email/default/a"].(*Email)
+	if c.EmailConfig.SmartHost.Host != "smtp.example.com" || !*c.SourceLink || c.AnnotationSection[0].Name != "summary" {
+		t.Fatalf("expect the copy not affected, got %+v", c)
+	}
+	if c.GetNamespace() != "default" {
+		t.Fatalf("expect the namespace of the copy not affected, got %s", c.GetNamespace())
+	}
+}
+
+func TestReceiverHandlersOnApply(t *testing.T) {
+
+	c := &Config{
+		receivers:    map[string]map[string]Receiver{"default": {"email/default/a": testEmail("smtp.example.com", "password")}},
+		ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{ManualApply: true}},
+	}
+	c.applied = cloneReceivers(c.receivers)
+
+	var changed []string
+	c.OnReceiverChange(func(key string, r Receiver) {
+		if r == nil {
+			key += " deleted"
+		}
+		changed = append(changed, key)
+	})
+
+	// The changes are staged.
+	c.receivers["default"]["email/default/a"] = testEmail("mail.example.com", "password")
+	c.receiverChanged("email/default/a", c.receivers["default"]["email/default/a"])
+	c.receivers["default"]["email/default/b"] = testEmail("smtp.example.com", "password")
+	c.receiverChanged("email/default/b", c.receivers["default"]["email/default/b"])
+	if len(changed) != 0 {
+		t.Fatalf("expect no handler called before applied, got %v", changed)
+	}
+
+	p := &param{op: opApply, done: make(chan interface{}, 1)}
+	c.sync(p)
+	<-p.done
+	if want := []string{"email/default/b", "email/default/a"}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("changed = %v, want %v", changed, want)
+	}
+
+	// The changes take effect immediately without the manual apply.
+	changed = nil
+	delete(c.receivers["default"], "email/default/b")
+	c.ReceiverOpts.Global.ManualApply = false
+	p = &param{op: opAdd, opType: notificationManager, ReceiverOpts: c.ReceiverOpts, done: make(chan interface{}, 1)}
+	c.sync(p)
+	<-p.done
+	c.receiverChanged("email/default/a", c.receivers["default"]["email/default/a"])
+	if want := []string{"email/default/b deleted", "email/default/a"}; !reflect.DeepEqual(changed, want) {
+		t.Fatalf("changed = %v, want %v", changed, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
 )
 
 type factory struct {
@@ -37,7 +38,14 @@ type Receiver interface {
 	SetConfig(c interface{}) error
 	GetTenantID() string
 	SetTenantID(id string)
+	GetName() string
+	SetName(name string)
+	GetNamespace() string
 	SetNamespace(ns string)
+	GetAnnotationMaxLength() int
+	GetMaxInFlight() int
+	GetAlertStatus() string
+	GetFirstNotificationDelay() time.Duration
 	GenerateConfig(c *Config, obj interface{})
 	GenerateReceiver(c *Config, obj interface{})
 }
@@ -46,7 +54,16 @@ type common struct {
 	// True means receiver use the default config.
 	useDefault bool
 	tenantID   string
+	name       string
 	namespace  string
+	// The maximum length of the annotation values in the notifications of the receiver.
+	annotationMaxLength int
+	// The maximum number of the notifications sent to the receiver simultaneously, 0 means no limit.
+	maxInFlight int
+	// The status of the alerts sent to the receiver, empty means both.
+	alertStatus string
+	// The time to wait before the first notification of a new alert group.
+	firstNotificationDelay time.Duration
 }
 
 func (c *common) UseDefault() bool {
@@ -65,6 +82,14 @@ func (c *common) SetTenantID(id string) {
 	c.tenantID = id
 }
 
+func (c *common) GetName() string {
+	return c.name
+}
+
+func (c *common) SetName(name string) {
+	c.name = name
+}
+
 func (c *common) GetNamespace() string {
 	return c.namespace
 }
@@ -73,8 +98,72 @@ func (c *common) SetNamespace(ns string) {
 	c.namespace = ns
 }
 
+func (c *common) GetAnnotationMaxLength() int {
+	return c.annotationMaxLength
+}
+
+func (c *common) SetAnnotationMaxLength(l int) {
+	c.annotationMaxLength = l
+}
+
+func (c *common) GetMaxInFlight() int {
+	return c.maxInFlight
+}
+
+func (c *common) SetMaxInFlight(n int) {
+	c.maxInFlight = n
+}
+
+func (c *common) GetFirstNotificationDelay() time.Duration {
+	return c.firstNotificationDelay
+}
+
+// ReceiverType returns the type of the receiver, such as `email`.
+func ReceiverType(r Receiver) string {
+
+	switch r.(type) {
+	case *Email:
+		return email
+	case *Wechat:
+		return wechat
+	case *Slack:
+		return slack
+	case *Webhook:
+		return webhook
+	case *DingTalk:
+		return dingtalk
+	case *Elasticsearch:
+		return elasticsearch
+	case *Splunk:
+		return splunk
+	case *Grpc:
+		return grpc
+	case *Pushgateway:
+		return pushgateway
+	default:
+		return ""
+	}
+}
+
+// The common fields of the receiver embedding them.
+func (c *common) commonFields() *common {
+	return c
+}
+
+func (c *common) GetAlertStatus() string {
+	return c.alertStatus
+}
+
+func (c *common) SetAlertStatus(status string) {
+	c.alertStatus = status
+}
+
 type DingTalk struct {
-	DingTalkConfig *DingTalkConfig
+	DingTalkConfig    *DingTalkConfig
+	SourceLink        *bool
+	OptionalTemplates []string
+	AnnotationSection []v1alpha1.AnnotationField
+	AlertAccent       *v1alpha1.AlertAccent
 	*common
 }
 
@@ -161,6 +250,16 @@ func (d *DingTalk) GenerateReceiver(c *Config, obj interface{}) {
 		return
 	}
 
+	d.name = dr.Name
+	d.annotationMaxLength = dr.Spec.AnnotationMaxLength
+	d.maxInFlight = dr.Spec.MaxInFlight
+	d.SourceLink = dr.Spec.SourceLink
+	d.OptionalTemplates = dr.Spec.OptionalTemplates
+	d.alertStatus = dr.Spec.AlertStatus
+	d.firstNotificationDelay = dr.Spec.FirstNotificationDelay
+	d.AnnotationSection = dr.Spec.AnnotationSection
+	d.AlertAccent = dr.Spec.AlertAccent
+
 	dcList := v1alpha1.DingTalkConfigList{}
 	dcSel, _ := metav1.LabelSelectorAsSelector(dr.Spec.DingTalkConfigSelector)
 	if err := c.cache.List(c.ctx, &dcList, client.MatchingLabelsSelector{Selector: dcSel}); client.IgnoreNotFound(err) != nil {
@@ -169,7 +268,7 @@ func (d *DingTalk) GenerateReceiver(c *Config, obj interface{}) {
 	}
 
 	for _, dc := range dcList.Items {
-		if len(c.nmNamespaces) > 0 && !sliceIn(c.nmNamespaces, dc.Namespace) {
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, dc.Namespace) {
 			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", dc.Name, "namespace", dc.Namespace)
 			continue
 		}
@@ -182,9 +281,112 @@ func (d *DingTalk) GenerateReceiver(c *Config, obj interface{}) {
 	return
 }
 
+type Elasticsearch struct {
+	ElasticsearchConfig *ElasticsearchConfig
+	*common
+}
+
+type ElasticsearchConfig struct {
+	Hosts           []string
+	HttpConfig      *v1alpha1.HTTPClientConfig
+	Index           string
+	IndexDateLayout string
+}
+
+func NewElasticsearchReceiver() Receiver {
+	return &Elasticsearch{
+		common: &common{},
+	}
+}
+
+func (e *Elasticsearch) GetConfig() interface{} {
+	return e.ElasticsearchConfig
+}
+
+func (e *Elasticsearch) SetConfig(obj interface{}) error {
+
+	if obj == nil {
+		e.ElasticsearchConfig = nil
+		return nil
+	}
+
+	c, ok := obj.(*ElasticsearchConfig)
+	if !ok {
+		return errors.New("set elasticsearch config error, wrong config type")
+	}
+
+	e.ElasticsearchConfig = c
+	return nil
+}
+
+func (e *Elasticsearch) GenerateConfig(c *Config, obj interface{}) {
+
+	ec, ok := obj.(*v1alpha1.ElasticsearchConfig)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate elasticsearch config error, wrong config type")
+		return
+	}
+
+	if len(ec.Spec.Hosts) == 0 {
+		_ = level.Error(c.logger).Log("msg", "ignore elasticsearch config because of empty hosts", "name", ec.Name, "namespace", ec.Namespace)
+		return
+	}
+
+	e.ElasticsearchConfig = &ElasticsearchConfig{
+		Hosts:           ec.Spec.Hosts,
+		HttpConfig:      ec.Spec.HTTPConfig,
+		Index:           ec.Spec.Index,
+		IndexDateLayout: ec.Spec.IndexDateLayout,
+	}
+}
+
+func (e *Elasticsearch) GenerateReceiver(c *Config, obj interface{}) {
+
+	er, ok := obj.(*v1alpha1.ElasticsearchReceiver)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate elasticsearch receiver error, wrong receiver type")
+		return
+	}
+
+	e.name = er.Name
+	e.maxInFlight = er.Spec.MaxInFlight
+	e.alertStatus = er.Spec.AlertStatus
+	e.firstNotificationDelay = er.Spec.FirstNotificationDelay
+
+	ecList := v1alpha1.ElasticsearchConfigList{}
+	ecSel, _ := metav1.LabelSelectorAsSelector(er.Spec.ElasticsearchConfigSelector)
+	if err := c.cache.List(c.ctx, &ecList, client.MatchingLabelsSelector{Selector: ecSel}); client.IgnoreNotFound(err) != nil {
+		_ = level.Error(c.logger).Log("msg", "Unable to list ElasticsearchConfig", "err", err)
+		return
+	}
+
+	for _, ec := range ecList.Items {
+
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, ec.Namespace) {
+			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", ec.Name, "namespace", ec.Namespace)
+			continue
+		}
+
+		e.GenerateConfig(c, &ec)
+		if e.ElasticsearchConfig != nil {
+			break
+		}
+	}
+}
+
 type Email struct {
-	To          []string
-	EmailConfig *EmailConfig
+	To                      []string
+	SubjectLabels           []string
+	SourceLink              *bool
+	OptionalTemplates       []string
+	AnnotationSection       []v1alpha1.AnnotationField
+	SubjectIcons            *v1alpha1.SubjectIcons
+	CollapsibleAlerts       bool
+	MarkdownAnnotations     []string
+	ContentTransferEncoding string
+	EnvelopeFrom            string
+	Headers                 []v1alpha1.EmailHeader
+	EmailConfig             *EmailConfig
 	*common
 }
 
@@ -275,7 +477,23 @@ func (e *Email) GenerateReceiver(c *Config, obj interface{}) {
 		return
 	}
 
+	e.name = er.Name
+	e.annotationMaxLength = er.Spec.AnnotationMaxLength
+	e.maxInFlight = er.Spec.MaxInFlight
+
 	e.To = er.Spec.To
+	e.SubjectLabels = er.Spec.SubjectLabels
+	e.SourceLink = er.Spec.SourceLink
+	e.OptionalTemplates = er.Spec.OptionalTemplates
+	e.alertStatus = er.Spec.AlertStatus
+	e.firstNotificationDelay = er.Spec.FirstNotificationDelay
+	e.AnnotationSection = er.Spec.AnnotationSection
+	e.SubjectIcons = er.Spec.SubjectIcons
+	e.CollapsibleAlerts = er.Spec.CollapsibleAlerts
+	e.MarkdownAnnotations = er.Spec.MarkdownAnnotations
+	e.ContentTransferEncoding = er.Spec.ContentTransferEncoding
+	e.EnvelopeFrom = er.Spec.EnvelopeFrom
+	e.Headers = er.Spec.Headers
 
 	ecList := v1alpha1.EmailConfigList{}
 	ecSel, _ := metav1.LabelSelectorAsSelector(er.Spec.EmailConfigSelector)
@@ -286,7 +504,7 @@ func (e *Email) GenerateReceiver(c *Config, obj interface{}) {
 
 	for _, ec := range ecList.Items {
 
-		if len(c.nmNamespaces) > 0 && !sliceIn(c.nmNamespaces, ec.Namespace) {
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, ec.Namespace) {
 			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", ec.Name, "namespace", ec.Namespace)
 			continue
 		}
@@ -300,8 +518,13 @@ func (e *Email) GenerateReceiver(c *Config, obj interface{}) {
 
 type Slack struct {
 	// The channel or user to send notifications to.
-	Channel     string
-	SlackConfig *SlackConfig
+	Channel           string
+	SourceLink        *bool
+	OptionalTemplates []string
+	AnnotationSection []v1alpha1.AnnotationField
+	AlertAccent       *v1alpha1.AlertAccent
+	BlockKit          *v1alpha1.SlackBlockKit
+	SlackConfig       *SlackConfig
 	*common
 }
 
@@ -364,6 +587,10 @@ func (s *Slack) GenerateReceiver(c *Config, obj interface{}) {
 		return
 	}
 
+	s.name = sr.Name
+	s.annotationMaxLength = sr.Spec.AnnotationMaxLength
+	s.maxInFlight = sr.Spec.MaxInFlight
+
 	scList := v1alpha1.SlackConfigList{}
 	scSel, _ := metav1.LabelSelectorAsSelector(sr.Spec.SlackConfigSelector)
 	if err := c.cache.List(c.ctx, &scList, client.MatchingLabelsSelector{Selector: scSel}); client.IgnoreNotFound(err) != nil {
@@ -372,9 +599,16 @@ func (s *Slack) GenerateReceiver(c *Config, obj interface{}) {
 	}
 
 	s.Channel = sr.Spec.Channel
+	s.SourceLink = sr.Spec.SourceLink
+	s.OptionalTemplates = sr.Spec.OptionalTemplates
+	s.alertStatus = sr.Spec.AlertStatus
+	s.firstNotificationDelay = sr.Spec.FirstNotificationDelay
+	s.AnnotationSection = sr.Spec.AnnotationSection
+	s.AlertAccent = sr.Spec.AlertAccent
+	s.BlockKit = sr.Spec.BlockKit
 
 	for _, sc := range scList.Items {
-		if len(c.nmNamespaces) > 0 && !sliceIn(c.nmNamespaces, sc.Namespace) {
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, sc.Namespace) {
 			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", sc.Name, "namespace", sc.Namespace)
 			continue
 		}
@@ -388,15 +622,304 @@ func (s *Slack) GenerateReceiver(c *Config, obj interface{}) {
 	return
 }
 
+type Splunk struct {
+	SplunkConfig *SplunkConfig
+	*common
+}
+
+type SplunkConfig struct {
+	URL        string
+	Token      *v1.SecretKeySelector
+	Index      string
+	SourceType string
+	Source     string
+	TLSConfig  *v1alpha1.TLSConfig
+}
+
+func NewSplunkReceiver() Receiver {
+	return &Splunk{
+		common: &common{},
+	}
+}
+
+func (s *Splunk) GetConfig() interface{} {
+	return s.SplunkConfig
+}
+
+func (s *Splunk) SetConfig(obj interface{}) error {
+
+	if obj == nil {
+		s.SplunkConfig = nil
+		return nil
+	}
+
+	c, ok := obj.(*SplunkConfig)
+	if !ok {
+		return errors.New("set splunk config error, wrong config type")
+	}
+
+	s.SplunkConfig = c
+	return nil
+}
+
+func (s *Splunk) GenerateConfig(c *Config, obj interface{}) {
+
+	sc, ok := obj.(*v1alpha1.SplunkConfig)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate splunk config error, wrong config type")
+		return
+	}
+
+	if len(sc.Spec.URL) == 0 || sc.Spec.Token == nil {
+		_ = level.Error(c.logger).Log("msg", "ignore splunk config because of empty url or token", "name", sc.Name, "namespace", sc.Namespace)
+		return
+	}
+
+	s.SplunkConfig = &SplunkConfig{
+		URL:        sc.Spec.URL,
+		Token:      sc.Spec.Token,
+		Index:      sc.Spec.Index,
+		SourceType: sc.Spec.SourceType,
+		Source:     sc.Spec.Source,
+		TLSConfig:  sc.Spec.TLSConfig,
+	}
+}
+
+func (s *Splunk) GenerateReceiver(c *Config, obj interface{}) {
+
+	sr, ok := obj.(*v1alpha1.SplunkReceiver)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate splunk receiver error, wrong receiver type")
+		return
+	}
+
+	s.name = sr.Name
+	s.maxInFlight = sr.Spec.MaxInFlight
+	s.alertStatus = sr.Spec.AlertStatus
+	s.firstNotificationDelay = sr.Spec.FirstNotificationDelay
+
+	scList := v1alpha1.SplunkConfigList{}
+	scSel, _ := metav1.LabelSelectorAsSelector(sr.Spec.SplunkConfigSelector)
+	if err := c.cache.List(c.ctx, &scList, client.MatchingLabelsSelector{Selector: scSel}); client.IgnoreNotFound(err) != nil {
+		_ = level.Error(c.logger).Log("msg", "Unable to list SplunkConfig", "err", err)
+		return
+	}
+
+	for _, sc := range scList.Items {
+
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, sc.Namespace) {
+			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", sc.Name, "namespace", sc.Namespace)
+			continue
+		}
+
+		s.GenerateConfig(c, &sc)
+		if s.SplunkConfig != nil {
+			break
+		}
+	}
+}
+
+type Grpc struct {
+	GrpcConfig *GrpcConfig
+	*common
+}
+
+type GrpcConfig struct {
+	Target    string
+	Method    string
+	Token     *v1.SecretKeySelector
+	TLSConfig *v1alpha1.TLSConfig
+}
+
+func NewGrpcReceiver() Receiver {
+	return &Grpc{
+		common: &common{},
+	}
+}
+
+func (g *Grpc) GetConfig() interface{} {
+	return g.GrpcConfig
+}
+
+func (g *Grpc) SetConfig(obj interface{}) error {
+
+	if obj == nil {
+		g.GrpcConfig = nil
+		return nil
+	}
+
+	c, ok := obj.(*GrpcConfig)
+	if !ok {
+		return errors.New("set grpc config error, wrong config type")
+	}
+
+	g.GrpcConfig = c
+	return nil
+}
+
+func (g *Grpc) GenerateConfig(c *Config, obj interface{}) {
+
+	gc, ok := obj.(*v1alpha1.GrpcConfig)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate grpc config error, wrong config type")
+		return
+	}
+
+	if len(gc.Spec.Target) == 0 {
+		_ = level.Error(c.logger).Log("msg", "ignore grpc config because of empty target", "name", gc.Name, "namespace", gc.Namespace)
+		return
+	}
+
+	g.GrpcConfig = &GrpcConfig{
+		Target:    gc.Spec.Target,
+		Method:    gc.Spec.Method,
+		Token:     gc.Spec.Token,
+		TLSConfig: gc.Spec.TLSConfig,
+	}
+}
+
+func (g *Grpc) GenerateReceiver(c *Config, obj interface{}) {
+
+	gr, ok := obj.(*v1alpha1.GrpcReceiver)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate grpc receiver error, wrong receiver type")
+		return
+	}
+
+	g.name = gr.Name
+	g.maxInFlight = gr.Spec.MaxInFlight
+	g.alertStatus = gr.Spec.AlertStatus
+	g.firstNotificationDelay = gr.Spec.FirstNotificationDelay
+
+	gcList := v1alpha1.GrpcConfigList{}
+	gcSel, _ := metav1.LabelSelectorAsSelector(gr.Spec.GrpcConfigSelector)
+	if err := c.cache.List(c.ctx, &gcList, client.MatchingLabelsSelector{Selector: gcSel}); client.IgnoreNotFound(err) != nil {
+		_ = level.Error(c.logger).Log("msg", "Unable to list GrpcConfig", "err", err)
+		return
+	}
+
+	for _, gc := range gcList.Items {
+
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, gc.Namespace) {
+			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", gc.Name, "namespace", gc.Namespace)
+			continue
+		}
+
+		g.GenerateConfig(c, &gc)
+		if g.GrpcConfig != nil {
+			break
+		}
+	}
+}
+
+type Pushgateway struct {
+	Labels            []string
+	ResolvedAction    string
+	PushgatewayConfig *PushgatewayConfig
+	*common
+}
+
+type PushgatewayConfig struct {
+	URL        string
+	Job        string
+	HTTPConfig *v1alpha1.HTTPClientConfig
+}
+
+func NewPushgatewayReceiver() Receiver {
+	return &Pushgateway{
+		common: &common{},
+	}
+}
+
+func (p *Pushgateway) GetConfig() interface{} {
+	return p.PushgatewayConfig
+}
+
+func (p *Pushgateway) SetConfig(obj interface{}) error {
+
+	if obj == nil {
+		p.PushgatewayConfig = nil
+		return nil
+	}
+
+	c, ok := obj.(*PushgatewayConfig)
+	if !ok {
+		return errors.New("set pushgateway config error, wrong config type")
+	}
+
+	p.PushgatewayConfig = c
+	return nil
+}
+
+func (p *Pushgateway) GenerateConfig(c *Config, obj interface{}) {
+
+	pc, ok := obj.(*v1alpha1.PushgatewayConfig)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate pushgateway config error, wrong config type")
+		return
+	}
+
+	if len(pc.Spec.URL) == 0 {
+		_ = level.Error(c.logger).Log("msg", "ignore pushgateway config because of empty url", "name", pc.Name, "namespace", pc.Namespace)
+		return
+	}
+
+	p.PushgatewayConfig = &PushgatewayConfig{
+		URL:        pc.Spec.URL,
+		Job:        pc.Spec.Job,
+		HTTPConfig: pc.Spec.HTTPConfig,
+	}
+}
+
+func (p *Pushgateway) GenerateReceiver(c *Config, obj interface{}) {
+
+	pr, ok := obj.(*v1alpha1.PushgatewayReceiver)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "generate pushgateway receiver error, wrong receiver type")
+		return
+	}
+
+	p.name = pr.Name
+	p.maxInFlight = pr.Spec.MaxInFlight
+	p.alertStatus = pr.Spec.AlertStatus
+	p.Labels = pr.Spec.Labels
+	p.ResolvedAction = pr.Spec.ResolvedAction
+	p.firstNotificationDelay = pr.Spec.FirstNotificationDelay
+
+	pcList := v1alpha1.PushgatewayConfigList{}
+	pcSel, _ := metav1.LabelSelectorAsSelector(pr.Spec.PushgatewayConfigSelector)
+	if err := c.cache.List(c.ctx, &pcList, client.MatchingLabelsSelector{Selector: pcSel}); client.IgnoreNotFound(err) != nil {
+		_ = level.Error(c.logger).Log("msg", "Unable to list PushgatewayConfig", "err", err)
+		return
+	}
+
+	for _, pc := range pcList.Items {
+
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, pc.Namespace) {
+			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", pc.Name, "namespace", pc.Namespace)
+			continue
+		}
+
+		p.GenerateConfig(c, &pc)
+		if p.PushgatewayConfig != nil {
+			break
+		}
+	}
+}
+
 type Webhook struct {
-	WebhookConfig *WebhookConfig
+	OptionalTemplates []string
+	Redirect          *v1alpha1.WebhookRedirect
+	PayloadVersion    string
+	WebhookConfig     *WebhookConfig
 	*common
 }
 
 type WebhookConfig struct {
 	// `url` gives the location of the webhook, in standard URL form.
-	URL        string
-	HttpConfig *v1alpha1.HTTPClientConfig
+	URL             string
+	HttpConfig      *v1alpha1.HTTPClientConfig
+	SuccessCriteria *v1alpha1.WebhookSuccessCriteria
 }
 
 func NewWebhookReceiver() Receiver {
@@ -434,7 +957,8 @@ func (w *Webhook) GenerateConfig(c *Config, obj interface{}) {
 	}
 
 	webhookConfig := &WebhookConfig{
-		HttpConfig: wc.Spec.HTTPConfig,
+		HttpConfig:      wc.Spec.HTTPConfig,
+		SuccessCriteria: wc.Spec.SuccessCriteria,
 	}
 
 	if wc.Spec.URL != nil {
@@ -470,6 +994,15 @@ func (w *Webhook) GenerateReceiver(c *Config, obj interface{}) {
 		return
 	}
 
+	w.name = wr.Name
+	w.annotationMaxLength = wr.Spec.AnnotationMaxLength
+	w.maxInFlight = wr.Spec.MaxInFlight
+	w.OptionalTemplates = wr.Spec.OptionalTemplates
+	w.Redirect = wr.Spec.Redirect
+	w.PayloadVersion = wr.Spec.PayloadVersion
+	w.alertStatus = wr.Spec.AlertStatus
+	w.firstNotificationDelay = wr.Spec.FirstNotificationDelay
+
 	wcList := v1alpha1.WebhookConfigList{}
 	wcSel, _ := metav1.LabelSelectorAsSelector(wr.Spec.WebhookConfigSelector)
 	if err := c.cache.List(c.ctx, &wcList, client.MatchingLabelsSelector{Selector: wcSel}); client.IgnoreNotFound(err) != nil {
@@ -479,7 +1012,7 @@ func (w *Webhook) GenerateReceiver(c *Config, obj interface{}) {
 
 	for _, wc := range wcList.Items {
 
-		if len(c.nmNamespaces) > 0 && !sliceIn(c.nmNamespaces, wc.Namespace) {
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, wc.Namespace) {
 			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", wc.Name, "namespace", wc.Namespace)
 			continue
 		}
@@ -492,10 +1025,14 @@ func (w *Webhook) GenerateReceiver(c *Config, obj interface{}) {
 }
 
 type Wechat struct {
-	ToUser       string
-	ToParty      string
-	ToTag        string
-	WechatConfig *WechatConfig
+	ToUser            string
+	ToParty           string
+	ToTag             string
+	SourceLink        *bool
+	OptionalTemplates []string
+	AnnotationSection []v1alpha1.AnnotationField
+	AlertAccent       *v1alpha1.AlertAccent
+	WechatConfig      *WechatConfig
 	*common
 }
 
@@ -560,6 +1097,10 @@ func (w *Wechat) GenerateReceiver(c *Config, obj interface{}) {
 		return
 	}
 
+	w.name = wr.Name
+	w.annotationMaxLength = wr.Spec.AnnotationMaxLength
+	w.maxInFlight = wr.Spec.MaxInFlight
+
 	wcList := v1alpha1.WechatConfigList{}
 	wcSel, _ := metav1.LabelSelectorAsSelector(wr.Spec.WechatConfigSelector)
 	if err := c.cache.List(c.ctx, &wcList, client.MatchingLabelsSelector{Selector: wcSel}); client.IgnoreNotFound(err) != nil {
@@ -570,10 +1111,16 @@ func (w *Wechat) GenerateReceiver(c *Config, obj interface{}) {
 	w.ToUser = wr.Spec.ToUser
 	w.ToParty = wr.Spec.ToParty
 	w.ToTag = wr.Spec.ToTag
+	w.SourceLink = wr.Spec.SourceLink
+	w.OptionalTemplates = wr.Spec.OptionalTemplates
+	w.alertStatus = wr.Spec.AlertStatus
+	w.firstNotificationDelay = wr.Spec.FirstNotificationDelay
+	w.AnnotationSection = wr.Spec.AnnotationSection
+	w.AlertAccent = wr.Spec.AlertAccent
 
 	for _, wc := range wcList.Items {
 
-		if len(c.nmNamespaces) > 0 && !sliceIn(c.nmNamespaces, wc.Namespace) {
+		if len(c.nmNamespaces) > 0 && !StringIn(c.nmNamespaces, wc.Namespace) {
 			_ = level.Warn(c.logger).Log("msg", "don't need to be watched", "name", wc.Name, "namespace", wc.Namespace)
 			continue
 		}
@@ -597,13 +1144,18 @@ func (w *Wechat) Clone() *Wechat {
 			APIURL:    w.WechatConfig.APIURL,
 			AgentID:   w.WechatConfig.AgentID,
 		},
-		ToUser:  w.ToUser,
-		ToParty: w.ToParty,
-		ToTag:   w.ToTag,
+		ToUser:            w.ToUser,
+		ToParty:           w.ToParty,
+		ToTag:             w.ToTag,
+		SourceLink:        w.SourceLink,
+		OptionalTemplates: w.OptionalTemplates,
+		AnnotationSection: w.AnnotationSection,
+		AlertAccent:       w.AlertAccent,
 	}
 }
 
-func sliceIn(src []string, elem string) bool {
+// StringIn returns true if the elem is in the src.
+func StringIn(src []string, elem string) bool {
 	for _, s := range src {
 		if s == elem {
 			return true
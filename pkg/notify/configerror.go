@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultConfigErrorInterval = time.Hour
+	ConfigErrorAlertName       = "NotifierConfigError"
+	metaNotificationTimeout    = time.Minute
+)
+
+var (
+	configErrors = &metaNotificationStates{
+		notified: make(map[string]time.Time),
+	}
+	// Send the notifications about the notification manager itself, it is replaced in the tests.
+	sendMeta = sendMetaNotification
+)
+
+// The time of the last notification about each event, such as a config error.
+type metaNotificationStates struct {
+	notified map[string]time.Time
+	mutex    sync.Mutex
+}
+
+func init() {
+	notifier.OnConfigError(notifyConfigError)
+}
+
+// Send a notification about the config error to the receivers of the administrators, at most once in the interval
+// for the same error of a receiver. The errors of the receivers of the administrators are rate limited the same way,
+// so they do not cause a loop.
+func notifyConfigError(logger log.Logger, notifierCfg *config.Config, e *notifier.ConfigError) {
+
+	if notifierCfg == nil || notifierCfg.ReceiverOpts == nil || notifierCfg.ReceiverOpts.Global == nil {
+		return
+	}
+
+	opts := notifierCfg.ReceiverOpts.Global.ConfigErrorNotification
+	if opts == nil || len(opts.Receiver) == 0 {
+		return
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultConfigErrorInterval
+	}
+
+	if !configErrors.allow(e.Error(), interval, time.Now()) {
+		stats.GetCounters().Add("config_error_throttled", 1)
+		return
+	}
+
+	labels := template.KV{
+		model.AlertNameLabel: ConfigErrorAlertName,
+		"severity":           "warning",
+		"notifier":           e.Type,
+		"tenant":             e.TenantID,
+		"namespace":          e.Namespace,
+	}
+
+	if sendMeta(logger, notifierCfg, opts.Receiver, metaNotificationData(opts.Receiver, labels, e.Error())) {
+		stats.GetCounters().Add("config_error_notified", 1)
+	}
+}
+
+// Send a notification about the notification manager itself, such as a config error, to the receivers of the name.
+// The notifiers are created while creating a notification, the notification is sent in the background.
+// It returns false if there is no receiver of the name.
+func sendMetaNotification(logger log.Logger, notifierCfg *config.Config, receiver string, data template.Data) bool {
+
+	receivers := notifierCfg.RcvsFromName(receiver)
+	if len(receivers) == 0 {
+		_ = level.Warn(logger).Log("msg", "no receiver to send the notification", "receiver", receiver, "alert", data.GroupLabels[model.AlertNameLabel])
+		return false
+	}
+
+	n := &Notification{
+		Notifiers: newNotifiers(logger, receivers, notifierCfg),
+		Data:      data,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metaNotificationTimeout)
+		defer cancel()
+
+		for _, err := range n.Notify(ctx) {
+			if err != nil {
+				_ = level.Error(logger).Log("msg", "send the notification failed", "alert", data.GroupLabels[model.AlertNameLabel], "error", err.Error())
+			}
+		}
+	}()
+
+	return true
+}
+
+// The data of a firing alert with the labels and the message, the labels must have the alert name.
+func metaNotificationData(receiver string, labels template.KV, message string) template.Data {
+
+	return template.Data{
+		Receiver: receiver,
+		Status:   string(model.AlertFiring),
+		Alerts: template.Alerts{
+			{
+				Status: string(model.AlertFiring),
+				Labels: labels,
+				Annotations: template.KV{
+					"message": message,
+				},
+				StartsAt:    time.Now(),
+				Fingerprint: notifier.KvToLabelSet(labels).Fingerprint().String(),
+			},
+		},
+		GroupLabels:       template.KV{model.AlertNameLabel: labels[model.AlertNameLabel]},
+		CommonLabels:      labels,
+		CommonAnnotations: template.KV{"message": message},
+	}
+}
+
+// Whether the event can be notified now, the time is recorded if it can.
+func (c *metaNotificationStates) allow(key string, interval time.Duration, now time.Time) bool {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if t, ok := c.notified[key]; ok && now.Sub(t) < interval {
+		return false
+	}
+
+	for k, t := range c.notified {
+		if now.Sub(t) >= interval {
+			delete(c.notified, k)
+		}
+	}
+
+	c.notified[key] = now
+	return true
+}
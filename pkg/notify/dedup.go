@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var dedups = &dedupStates{
+	states: make(map[string]*dedupState),
+}
+
+// The content hash of the last notification of each alert group, used to suppress the duplicate notifications.
+type dedupStates struct {
+	states map[string]*dedupState
+	mutex  sync.Mutex
+}
+
+type dedupState struct {
+	hash string
+	sent time.Time
+}
+
+// Drop all the alerts if the notification is the same as the last one of the group except the ignored keys,
+// so nothing will be sent.
+func dropDuplicate(logger log.Logger, data template.Data, dedup *v1alpha1.Dedup) template.Data {
+
+	if len(data.Alerts) == 0 {
+		return data
+	}
+
+	hash, err := contentHash(data, dedup.IgnoredKeys)
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "compute the content hash error", "error", err.Error())
+		return data
+	}
+
+	key := groupKey(data)
+	if dedups.duplicate(key, hash, dedup.RepeatInterval, time.Now()) {
+		_ = level.Debug(logger).Log("msg", "drop the duplicate notification", "group", key)
+		stats.GetCounters().Add("deduplicated", 1)
+		traceDropped(data.Alerts, "duplicate of the last notification of the group")
+		data.Alerts = nil
+	}
+
+	return data
+}
+
+// Compare the hash with the last notification of the group, the state is updated if the notification will be sent.
+func (d *dedupStates) duplicate(key, hash string, repeatInterval time.Duration, now time.Time) bool {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for k, s := range d.states {
+		if now.Sub(s.sent) > groupStateExpires {
+			delete(d.states, k)
+		}
+	}
+
+	s, ok := d.states[key]
+	if ok && s.hash == hash && (repeatInterval <= 0 || now.Sub(s.sent) < repeatInterval) {
+		return true
+	}
+
+	d.states[key] = &dedupState{
+		hash: hash,
+		sent: now,
+	}
+
+	return false
+}
+
+// The hash of the status, labels and annotations of the alerts, the ignored keys are skipped.
+// The times of the alerts are skipped except the start time, as the end time of a firing alert changes at every evaluation.
+func contentHash(data template.Data, ignoredKeys []string) (string, error) {
+
+	ignored := make(map[string]bool)
+	for _, k := range ignoredKeys {
+		ignored[k] = true
+	}
+
+	pairs := func(kv template.KV) string {
+		var s []string
+		for _, p := range kv.SortedPairs() {
+			if !ignored[p.Name] {
+				s = append(s, fmt.Sprintf("%s=%s", p.Name, p.Value))
+			}
+		}
+		return strings.Join(s, ",")
+	}
+
+	var alerts []string
+	for _, alert := range data.Alerts {
+		alerts = append(alerts, fmt.Sprintf("%s/%s/%s/%d", alert.Status, pairs(alert.Labels), pairs(alert.Annotations), alert.StartsAt.Unix()))
+	}
+	sort.Strings(alerts)
+
+	return notifier.Md5key(alerts)
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestContentHash(t *testing.T) {
+
+	withValue := func(value string) template.Data {
+		a := testAlert("a", "firing")
+		a.Annotations["value"] = value
+		a.EndsAt = time.Now()
+		return testGroup("dedup", a, testAlert("b", "firing"))
+	}
+	reordered := withValue("1")
+	reordered.Alerts[0], reordered.Alerts[1] = reordered.Alerts[1], reordered.Alerts[0]
+	resolved := withValue("1")
+	resolved.Alerts[0].Status = "resolved"
+
+	tests := []struct {
+		name    string
+		data    template.Data
+		ignored []string
+		same    bool
+	}{
+		{name: "same content", data: withValue("1"), same: true},
+		{name: "alerts reordered", data: reordered, same: true},
+		{name: "value changed", data: withValue("2"), same: false},
+		{name: "value changed but ignored", data: withValue("2"), ignored: []string{"value"}, same: true},
+		{name: "status changed", data: resolved, same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := contentHash(withValue("1"), tt.ignored)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := contentHash(tt.data, tt.ignored)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == want) != tt.same {
+				t.Fatalf("same hash = %v, want %v", got == want, tt.same)
+			}
+		})
+	}
+}
+
+func TestDedupStatesDuplicate(t *testing.T) {
+
+	type step struct {
+		hash string
+		// The time since the last step.
+		after time.Duration
+		want  bool
+	}
+
+	tests := []struct {
+		name           string
+		repeatInterval time.Duration
+		steps          []step
+	}{
+		{
+			name:  "always suppressed",
+			steps: []step{{hash: "a"}, {hash: "a", after: 24 * time.Hour, want: true}, {hash: "b"}, {hash: "a"}},
+		},
+		{
+			name:           "repeated after the interval",
+			repeatInterval: time.Hour,
+			steps: []step{
+				{hash: "a"},
+				{hash: "a", after: time.Minute, want: true},
+				{hash: "a", after: time.Hour},
+				{hash: "a", after: time.Minute, want: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &dedupStates{states: make(map[string]*dedupState)}
+			now := time.Now()
+			for i, s := range tt.steps {
+				now = now.Add(s.after)
+				if got := d.duplicate("group", s.hash, tt.repeatInterval, now); got != s.want {
+					t.Fatalf("step %d: duplicate = %v, want %v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The annotation of the owner references of the object, in form of `Kind/name`, separated by comma.
+	OwnerAnnotation       = "owner"
+	DefaultEnrichCacheTTL = time.Minute
+	enrichLookupTimeout   = time.Second * 3
+	// The timeout of looking up the objects of all the alerts in a notification.
+	enrichTimeout          = time.Second * 10
+	enrichNamespaceLabel   = "namespace"
+	maxEnrichCachedObjects = 10000
+	EnrichFailOpen         = "open"
+	EnrichFailClosed       = "closed"
+)
+
+// The labels of the workloads, in the order they are looked up.
+var workloads = []struct {
+	label     string
+	newObject func() runtime.Object
+}{
+	{"pod", func() runtime.Object { return &v1.Pod{} }},
+	{"deployment", func() runtime.Object { return &appsv1.Deployment{} }},
+	{"statefulset", func() runtime.Object { return &appsv1.StatefulSet{} }},
+	{"daemonset", func() runtime.Object { return &appsv1.DaemonSet{} }},
+}
+
+var enrichCache = &objectCache{
+	objects: make(map[string]*cachedObject),
+}
+
+// The metadata of the objects looked up recently, so that the alerts of a workload do not hit the apiserver every time.
+type objectCache struct {
+	objects map[string]*cachedObject
+	mutex   sync.Mutex
+}
+
+type cachedObject struct {
+	// It is nil if the object is not found.
+	object  metav1.Object
+	expires time.Time
+}
+
+func (c *objectCache) get(key string, now time.Time) (*cachedObject, bool) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	o, ok := c.objects[key]
+	if !ok || now.After(o.expires) {
+		return nil, false
+	}
+
+	return o, true
+}
+
+// Cache the object, the expired objects are removed when the cache is full.
+func (c *objectCache) set(key string, object metav1.Object, now time.Time, ttl time.Duration) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.objects) >= maxEnrichCachedObjects {
+		for k, o := range c.objects {
+			if now.After(o.expires) {
+				delete(c.objects, k)
+			}
+		}
+	}
+
+	c.objects[key] = &cachedObject{
+		object:  object,
+		expires: now.Add(ttl),
+	}
+}
+
+// An enricher adding the metadata of a kind of objects to the alerts, with its own failure policy.
+type enricher struct {
+	name   string
+	config *v1alpha1.Enricher
+	lookup func(ctx context.Context, reader client.Reader, labels template.KV, ttl time.Duration) (metav1.Object, error)
+}
+
+// Add the metadata of the objects of each alert to the annotations of the alert by the enrichers in order,
+// the alerts whose objects are not found are kept as is. If an object can not be looked up, the alerts are sent
+// without the metadata of the enricher in `open` failure policy, and the error is returned in `closed` failure policy.
+func enrich(logger log.Logger, reader client.Reader, enrichment *v1alpha1.Enrichment, data template.Data) (template.Data, error) {
+
+	if reader == nil || enrichment == nil {
+		return data, nil
+	}
+
+	ttl := enrichment.CacheTTL
+	if ttl <= 0 {
+		ttl = DefaultEnrichCacheTTL
+	}
+
+	var enrichers []enricher
+	if enrichment.Workload != nil {
+		enrichers = append(enrichers, enricher{"workload", enrichment.Workload, lookupWorkload})
+	}
+	if enrichment.Namespace != nil {
+		enrichers = append(enrichers, enricher{"namespace", enrichment.Namespace, lookupNamespace})
+	}
+	if len(enrichers) == 0 {
+		return data, nil
+	}
+
+	// Both the failure policies respect the timeout, the objects not looked up in time are failures.
+	ctx, cancel := context.WithTimeout(context.Background(), enrichTimeout)
+	defer cancel()
+
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		for _, e := range enrichers {
+			object, err := e.lookup(ctx, reader, alert.Labels, ttl)
+			if err != nil {
+				if e.config.FailurePolicy == EnrichFailClosed {
+					_ = level.Error(logger).Log("msg", "look up the object of the alert error, hold the notification",
+						"enricher", e.name, "error", err.Error())
+					stats.GetCounters().Add("enrich_failed_closed", 1)
+					return data, err
+				}
+
+				_ = level.Warn(logger).Log("msg", "look up the object of the alert error, send the alert without the metadata",
+					"enricher", e.name, "error", err.Error())
+				stats.GetCounters().Add("enrich_failed_open", 1)
+				continue
+			}
+
+			if object != nil {
+				alert.Annotations = enrichAnnotations(alert.Annotations, object, e.config)
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	data.Alerts = alerts
+
+	return data, nil
+}
+
+// The workload of the alert, it is nil if the alert has no workload label or the workload is not found.
+func lookupWorkload(ctx context.Context, reader client.Reader, labels template.KV, ttl time.Duration) (metav1.Object, error) {
+
+	namespace := labels[enrichNamespaceLabel]
+	if len(namespace) == 0 {
+		return nil, nil
+	}
+
+	for _, w := range workloads {
+		name := labels[w.label]
+		if len(name) == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", w.label, namespace, name)
+		return lookupObject(ctx, reader, key, types.NamespacedName{Namespace: namespace, Name: name}, w.newObject(), ttl)
+	}
+
+	return nil, nil
+}
+
+// The namespace of the alert, it is nil if the alert has no namespace label or the namespace is not found.
+func lookupNamespace(ctx context.Context, reader client.Reader, labels template.KV, ttl time.Duration) (metav1.Object, error) {
+
+	namespace := labels[enrichNamespaceLabel]
+	if len(namespace) == 0 {
+		return nil, nil
+	}
+
+	key := fmt.Sprintf("%s/%s", enrichNamespaceLabel, namespace)
+	return lookupObject(ctx, reader, key, types.NamespacedName{Name: namespace}, &v1.Namespace{}, ttl)
+}
+
+// Get the metadata of the object from the cache, or from the apiserver if it is not cached, it is nil if the object
+// is not found.
+func lookupObject(ctx context.Context, reader client.Reader, key string, name types.NamespacedName, obj runtime.Object, ttl time.Duration) (metav1.Object, error) {
+
+	now := time.Now()
+	if o, ok := enrichCache.get(key, now); ok {
+		stats.GetCounters().Add("enrich_cache_hit", 1)
+		return o.object, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, enrichLookupTimeout)
+	defer cancel()
+
+	if err := reader.Get(ctx, name, obj); err != nil {
+		if errors.IsNotFound(err) {
+			// Cache the missing object too, so the alerts of a deleted object do not hit the apiserver every time.
+			enrichCache.set(key, nil, now, ttl)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get %s error, %s", key, err.Error())
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the metadata used is cached.
+	object := &metav1.ObjectMeta{
+		Labels:          accessor.GetLabels(),
+		Annotations:     accessor.GetAnnotations(),
+		OwnerReferences: accessor.GetOwnerReferences(),
+	}
+	enrichCache.set(key, object, now, ttl)
+	return object, nil
+}
+
+func enrichAnnotations(annotations template.KV, object metav1.Object, enrichment *v1alpha1.Enricher) template.KV {
+
+	kv := copyKV(annotations)
+	add := func(k, v string) {
+		if _, ok := kv[k]; !ok && len(v) > 0 {
+			kv[k] = v
+		}
+	}
+
+	if enrichment.OwnerReferences {
+		var owners []string
+		for _, r := range object.GetOwnerReferences() {
+			owners = append(owners, fmt.Sprintf("%s/%s", r.Kind, r.Name))
+		}
+		add(OwnerAnnotation, strings.Join(owners, ","))
+	}
+
+	for _, k := range enrichment.Labels {
+		add(k, object.GetLabels()[k])
+	}
+
+	for _, k := range enrichment.Annotations {
+		add(k, object.GetAnnotations()[k])
+	}
+
+	return kv
+}
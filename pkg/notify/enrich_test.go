@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeReader serves the pods and the namespaces with the annotation `team`, the pods or the namespaces fail if set.
+type fakeReader struct {
+	client.Reader
+	failPods       bool
+	failNamespaces bool
+}
+
+func (r *fakeReader) Get(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+
+	_, isNamespace := obj.(*v1.Namespace)
+	if (isNamespace && r.failNamespaces) || (!isNamespace && r.failPods) {
+		return fmt.Errorf("connection refused")
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if isNamespace {
+		accessor.SetAnnotations(map[string]string{"team": "namespace-team", "tier": "backend"})
+	} else {
+		accessor.SetAnnotations(map[string]string{"team": "pod-team"})
+	}
+
+	return nil
+}
+
+func TestEnrichFailurePolicy(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		reader     *fakeReader
+		enrichment *v1alpha1.Enrichment
+		want       template.KV
+		wantErr    bool
+	}{
+		{
+			// The metadata of the workload wins.
+			name:   "enriched",
+			reader: &fakeReader{},
+			enrichment: &v1alpha1.Enrichment{
+				Workload:  &v1alpha1.Enricher{Annotations: []string{"team"}},
+				Namespace: &v1alpha1.Enricher{Annotations: []string{"team", "tier"}},
+			},
+			want: template.KV{"team": "pod-team", "tier": "backend"},
+		},
+		{
+			// The alert is sent without the metadata of the failed workload enricher, the namespace enricher still applies.
+			name:   "fail open",
+			reader: &fakeReader{failPods: true},
+			enrichment: &v1alpha1.Enrichment{
+				Workload:  &v1alpha1.Enricher{Annotations: []string{"team"}},
+				Namespace: &v1alpha1.Enricher{Annotations: []string{"team", "tier"}},
+			},
+			want: template.KV{"team": "namespace-team", "tier": "backend"},
+		},
+		{
+			name:   "fail closed",
+			reader: &fakeReader{failPods: true},
+			enrichment: &v1alpha1.Enrichment{
+				Workload:  &v1alpha1.Enricher{Annotations: []string{"team"}, FailurePolicy: EnrichFailClosed},
+				Namespace: &v1alpha1.Enricher{Annotations: []string{"team", "tier"}},
+			},
+			wantErr: true,
+		},
+		{
+			// The failure policy of the namespace enricher does not apply to the failed workload enricher.
+			name:   "fail open with another enricher closed",
+			reader: &fakeReader{failPods: true},
+			enrichment: &v1alpha1.Enrichment{
+				Workload:  &v1alpha1.Enricher{Annotations: []string{"team"}},
+				Namespace: &v1alpha1.Enricher{Annotations: []string{"tier"}, FailurePolicy: EnrichFailClosed},
+			},
+			want: template.KV{"tier": "backend"},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The objects are cached, so each case looks up the objects of its own namespace.
+			namespace := fmt.Sprintf("enrich-%d", i)
+			data := testGroup("enrich-"+tt.name, testAlert("a", "firing", "namespace", namespace))
+			data.Alerts[0].Annotations = template.KV{}
+
+			got, err := enrich(log.NewNopLogger(), tt.reader, tt.enrichment, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("enrich() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			annotations := got.Alerts[0].Annotations
+			if len(annotations) != len(tt.want) {
+				t.Fatalf("annotations = %v, want %v", annotations, tt.want)
+			}
+			for k, v := range tt.want {
+				if annotations[k] != v {
+					t.Fatalf("annotations = %v, want %v", annotations, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	"strings"
+)
+
+// limitedNotifier is the notifier of a receiver limiting the notifications in flight, it waits for a slot
+// of the receiver before sending, so the backend of the receiver will not be overloaded by the groups
+// sent simultaneously, whatever the type of the receiver is.
+type limitedNotifier struct {
+	notifier.Notifier
+	logger log.Logger
+	key    string
+	limit  int
+}
+
+func (n *limitedNotifier) Notify(ctx context.Context, data template.Data) []error {
+
+	release, err := notifier.GetInFlightLimiter().Acquire(ctx, n.key, n.limit)
+	if err != nil {
+		_ = level.Error(n.logger).Log("msg", "wait for in flight notifications error", "receiver", n.key, "error", err.Error())
+		return []error{err}
+	}
+	defer release()
+
+	return n.Notifier.Notify(ctx, data)
+}
+
+// Create the notifier of a receiver, which truncates the annotations if the receiver limits their length.
+// It returns nil if there is no factory of the receiver type.
+func newReceiverNotifier(logger log.Logger, r config.Receiver, notifierCfg *config.Config) notifier.Notifier {
+
+	for name, f := range factories {
+		if f == nil || !strings.EqualFold(name, config.ReceiverType(r)) {
+			continue
+		}
+
+		nf := f(logger, []config.Receiver{r}, notifierCfg)
+		if nf != nil && r.GetAnnotationMaxLength() > 0 {
+			nf = &truncatedNotifier{Notifier: nf, maxLength: r.GetAnnotationMaxLength()}
+		}
+
+		return nf
+	}
+
+	return nil
+}
+
+// The key of the receiver in form of `type/namespace/name`, the same as the key of the receiver changes.
+func receiverKey(r config.Receiver) string {
+	return fmt.Sprintf("%s/%s/%s", config.ReceiverType(r), r.GetNamespace(), r.GetName())
+}
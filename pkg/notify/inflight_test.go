@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+)
+
+// inFlightServer counts the requests in flight of each path, the requests of `/limited` wait for the gate.
+type inFlightServer struct {
+	*httptest.Server
+	gate chan struct{}
+
+	mutex    sync.Mutex
+	inFlight map[string]int
+	max      map[string]int
+	total    map[string]int
+}
+
+func newInFlightServer(t *testing.T) *inFlightServer {
+
+	s := &inFlightServer{
+		gate:     make(chan struct{}),
+		inFlight: make(map[string]int),
+		max:      make(map[string]int),
+		total:    make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.inFlight[r.URL.Path]++
+		s.total[r.URL.Path]++
+		if s.inFlight[r.URL.Path] > s.max[r.URL.Path] {
+			s.max[r.URL.Path] = s.inFlight[r.URL.Path]
+		}
+		s.mutex.Unlock()
+
+		if r.URL.Path == "/limited" {
+			<-s.gate
+		}
+
+		s.mutex.Lock()
+		s.inFlight[r.URL.Path]--
+		s.mutex.Unlock()
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *inFlightServer) stats(path string) (max, total int) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.max[path], s.total[path]
+}
+
+func TestNotifyLimitsInFlightPerReceiver(t *testing.T) {
+
+	s := newInFlightServer(t)
+	limited := newTestWebhook("limited", s.URL+"/limited")
+	limited.SetMaxInFlight(2)
+	other := newTestWebhook("other", s.URL+"/other")
+	cfg := &config.Config{}
+
+	const groups = 6
+	var wg sync.WaitGroup
+	for i := 0; i < groups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := &Notification{
+				Notifiers: newNotifiers(log.NewNopLogger(), []config.Receiver{limited, other}, cfg),
+				Data:      testGroup("inflight", testAlert("a", "firing")),
+			}
+			for _, err := range n.Notify(context.Background()) {
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	// The other receiver gets all the groups while the limited one is blocked.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, others := s.stats("/other")
+		_, limits := s.stats("/limited")
+		if others == groups && limits == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expect the other receiver not blocked by the limited one")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if max, total := s.stats("/limited"); max != 2 || total != 2 {
+		t.Fatalf("expect 2 notifications in flight of the limited receiver, got max %d, total %d", max, total)
+	}
+
+	close(s.gate)
+	wg.Wait()
+
+	if max, total := s.stats("/limited"); max > 2 || total != groups {
+		t.Fatalf("expect all the groups sent with at most 2 in flight, got max %d, total %d", max, total)
+	}
+}
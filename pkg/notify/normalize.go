@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"sort"
+	"strings"
+)
+
+// NormalizeLabels normalizes the labels of the alerts, the group labels and the common labels by the options.
+// It is called when the alerts are received, before the namespace of the alerts is read and any selector is matched,
+// so the routing, the filtering and the rendering all see the canonical labels.
+func NormalizeLabels(opts *v1alpha1.Options, data template.Data) template.Data {
+
+	if opts == nil || opts.Global == nil || opts.Global.LabelNormalization == nil {
+		return data
+	}
+
+	n := opts.Global.LabelNormalization
+	if !n.LowercaseNames && !n.TrimSpace && len(n.LowercaseValues) == 0 {
+		return data
+	}
+
+	values := make(map[string]bool)
+	for _, name := range n.LowercaseValues {
+		values[normalizeName(n, name)] = true
+	}
+
+	changed := 0
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		labels := normalizeKV(n, values, alert.Labels)
+		if !equalKV(labels, alert.Labels) {
+			changed++
+		}
+		alert.Labels = labels
+		alerts = append(alerts, alert)
+	}
+	data.Alerts = alerts
+	data.GroupLabels = normalizeKV(n, values, data.GroupLabels)
+	data.CommonLabels = normalizeKV(n, values, data.CommonLabels)
+
+	if changed > 0 {
+		stats.GetCounters().Add("labels_normalized", changed)
+	}
+
+	return data
+}
+
+// Normalize the labels into a new kv, the labels already in the canonical form are put first,
+// so they win over the others normalized into the same name.
+func normalizeKV(n *v1alpha1.LabelNormalization, values map[string]bool, kv template.KV) template.KV {
+
+	if kv == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(kv))
+	for k := range kv {
+		names = append(names, k)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ci, cj := normalizeName(n, names[i]) == names[i], normalizeName(n, names[j]) == names[j]
+		if ci != cj {
+			return ci
+		}
+		return names[i] < names[j]
+	})
+
+	res := make(template.KV, len(kv))
+	for _, k := range names {
+		name := normalizeName(n, k)
+		if _, ok := res[name]; ok || len(name) == 0 {
+			continue
+		}
+
+		v := kv[k]
+		if n.TrimSpace {
+			v = strings.TrimSpace(v)
+		}
+		if values[name] {
+			v = strings.ToLower(v)
+		}
+		res[name] = v
+	}
+
+	return res
+}
+
+func normalizeName(n *v1alpha1.LabelNormalization, name string) string {
+
+	if n.TrimSpace {
+		name = strings.TrimSpace(name)
+	}
+	if n.LowercaseNames {
+		name = strings.ToLower(name)
+	}
+
+	return name
+}
+
+func equalKV(a, b template.KV) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+
+	return true
+}
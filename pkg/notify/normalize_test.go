@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestNormalizeKV(t *testing.T) {
+
+	all := &v1alpha1.LabelNormalization{LowercaseNames: true, LowercaseValues: []string{"Severity"}, TrimSpace: true}
+
+	tests := []struct {
+		name          string
+		normalization *v1alpha1.LabelNormalization
+		kv            template.KV
+		want          template.KV
+	}{
+		{
+			name:          "lowercase names",
+			normalization: &v1alpha1.LabelNormalization{LowercaseNames: true},
+			kv:            template.KV{"Severity": "Critical", "Namespace": "default"},
+			want:          template.KV{"severity": "Critical", "namespace": "default"},
+		},
+		{
+			// The names of the lowercased values are matched after normalized.
+			name:          "lowercase values",
+			normalization: all,
+			kv:            template.KV{"SEVERITY": " Critical ", "pod": "Web-0"},
+			want:          template.KV{"severity": "critical", "pod": "Web-0"},
+		},
+		{
+			name:          "trim space",
+			normalization: &v1alpha1.LabelNormalization{TrimSpace: true},
+			kv:            template.KV{" team ": " Ops ", "Severity": "critical"},
+			want:          template.KV{"team": "Ops", "Severity": "critical"},
+		},
+		{
+			name:          "canonical label wins",
+			normalization: all,
+			kv:            template.KV{"Severity": "warning", "severity": "CRITICAL", "SEVERITY": "info"},
+			want:          template.KV{"severity": "critical"},
+		},
+		{
+			name:          "empty name dropped",
+			normalization: &v1alpha1.LabelNormalization{TrimSpace: true},
+			kv:            template.KV{" ": "a", "b": "c"},
+			want:          template.KV{"b": "c"},
+		},
+		{
+			name:          "nil",
+			normalization: all,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make(map[string]bool)
+			for _, name := range tt.normalization.LowercaseValues {
+				values[normalizeName(tt.normalization, name)] = true
+			}
+
+			if got := normalizeKV(tt.normalization, values, tt.kv); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("normalizeKV(%v) = %v, want %v", tt.kv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLabels(t *testing.T) {
+
+	data := func() template.Data {
+		return template.Data{
+			GroupLabels:  template.KV{"Alertname": "NodeDown"},
+			CommonLabels: template.KV{"Alertname": "NodeDown", "Severity": "Critical"},
+			Alerts: template.Alerts{
+				{Status: "firing", Labels: template.KV{"Alertname": "NodeDown", "Severity": "Critical", "node": "n1"}},
+				{Status: "firing", Labels: template.KV{"alertname": "NodeDown", "severity": "critical", "node": "n2"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts *v1alpha1.Options
+		// The labels of the first alert, the group labels and the common labels.
+		wantAlert  template.KV
+		wantGroup  template.KV
+		wantCommon template.KV
+	}{
+		{
+			name:       "no options",
+			wantAlert:  template.KV{"Alertname": "NodeDown", "Severity": "Critical", "node": "n1"},
+			wantGroup:  template.KV{"Alertname": "NodeDown"},
+			wantCommon: template.KV{"Alertname": "NodeDown", "Severity": "Critical"},
+		},
+		{
+			name:       "nothing enabled",
+			opts:       &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{LabelNormalization: &v1alpha1.LabelNormalization{}}},
+			wantAlert:  template.KV{"Alertname": "NodeDown", "Severity": "Critical", "node": "n1"},
+			wantGroup:  template.KV{"Alertname": "NodeDown"},
+			wantCommon: template.KV{"Alertname": "NodeDown", "Severity": "Critical"},
+		},
+		{
+			name: "normalized",
+			opts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{LabelNormalization: &v1alpha1.LabelNormalization{
+				LowercaseNames:  true,
+				LowercaseValues: []string{"severity"},
+			}}},
+			wantAlert:  template.KV{"alertname": "NodeDown", "severity": "critical", "node": "n1"},
+			wantGroup:  template.KV{"alertname": "NodeDown"},
+			wantCommon: template.KV{"alertname": "NodeDown", "severity": "critical"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeLabels(tt.opts, data())
+			if !reflect.DeepEqual(got.Alerts[0].Labels, tt.wantAlert) {
+				t.Fatalf("alert labels = %v, want %v", got.Alerts[0].Labels, tt.wantAlert)
+			}
+			if !reflect.DeepEqual(got.GroupLabels, tt.wantGroup) {
+				t.Fatalf("group labels = %v, want %v", got.GroupLabels, tt.wantGroup)
+			}
+			if !reflect.DeepEqual(got.CommonLabels, tt.wantCommon) {
+				t.Fatalf("common labels = %v, want %v", got.CommonLabels, tt.wantCommon)
+			}
+			if len(got.Alerts) != 2 {
+				t.Fatalf("expect 2 alerts, got %d", len(got.Alerts))
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+	"hash/fnv"
+)
+
+// The default palette of the accents, the colors are distinguishable from each other, and the emojis are the squares
+// of the colors, so they look the same in the chats without colors.
+var DefaultAccentPalette = []v1alpha1.Accent{
+	{Color: "#E01E5A", Emoji: "🟥"},
+	{Color: "#F2952F", Emoji: "🟧"},
+	{Color: "#ECB22E", Emoji: "🟨"},
+	{Color: "#2EB67D", Emoji: "🟩"},
+	{Color: "#36C5F0", Emoji: "🟦"},
+	{Color: "#8E44AD", Emoji: "🟪"},
+	{Color: "#8B5A2B", Emoji: "🟫"},
+	{Color: "#4A4A4A", Emoji: "⬛"},
+}
+
+// AlertAccent returns the accent of the alert, the accent of the severity of the alert if it is configured,
+// otherwise the accent of the palette the fingerprint of the alert is hashed to.
+// It is empty if the accents are not enabled.
+func AlertAccent(opts *v1alpha1.AlertAccent, alert template.Alert) v1alpha1.Accent {
+
+	if opts == nil || !opts.Enabled {
+		return v1alpha1.Accent{}
+	}
+
+	if accent, ok := opts.Severities[alert.Labels["severity"]]; ok {
+		return accent
+	}
+
+	palette := opts.Palette
+	if len(palette) == 0 {
+		palette = DefaultAccentPalette
+	}
+
+	// The fingerprint is computed by the labels if alertmanager does not send it, so it is stable too.
+	fingerprint := alert.Fingerprint
+	if len(fingerprint) == 0 {
+		fingerprint = KvToLabelSet(alert.Labels).Fingerprint().String()
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fingerprint))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
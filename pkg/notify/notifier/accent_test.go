@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestAlertAccent(t *testing.T) {
+
+	red := v1alpha1.Accent{Color: "#E01E5A", Emoji: "🟥"}
+	blue := v1alpha1.Accent{Color: "#36C5F0", Emoji: "🟦"}
+	critical := template.Alert{Labels: template.KV{"alertname": "NodeDown", "severity": "critical"}, Fingerprint: "a1"}
+
+	tests := []struct {
+		name  string
+		opts  *v1alpha1.AlertAccent
+		alert template.Alert
+		want  v1alpha1.Accent
+	}{
+		{"no accent", nil, critical, v1alpha1.Accent{}},
+		{"disabled", &v1alpha1.AlertAccent{Palette: []v1alpha1.Accent{red}}, critical, v1alpha1.Accent{}},
+		{"palette", &v1alpha1.AlertAccent{Enabled: true, Palette: []v1alpha1.Accent{blue}}, critical, blue},
+		{
+			name:  "severity overrides palette",
+			opts:  &v1alpha1.AlertAccent{Enabled: true, Palette: []v1alpha1.Accent{blue}, Severities: map[string]v1alpha1.Accent{"critical": red}},
+			alert: critical,
+			want:  red,
+		},
+		{
+			name:  "other severity",
+			opts:  &v1alpha1.AlertAccent{Enabled: true, Palette: []v1alpha1.Accent{blue}, Severities: map[string]v1alpha1.Accent{"warning": red}},
+			alert: critical,
+			want:  blue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AlertAccent(tt.opts, tt.alert); got != tt.want {
+				t.Fatalf("AlertAccent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertAccentStable(t *testing.T) {
+
+	opts := &v1alpha1.AlertAccent{Enabled: true}
+	labels := template.KV{"alertname": "KubePodCrashLooping", "pod": "web-0"}
+
+	// The fingerprint is computed by the labels if it is not sent, so the accent is the same either way.
+	got := AlertAccent(opts, template.Alert{Labels: labels})
+	if want := AlertAccent(opts, template.Alert{Labels: labels, Fingerprint: KvToLabelSet(labels).Fingerprint().String()}); got != want {
+		t.Fatalf("accent = %v, want %v", got, want)
+	}
+
+	inPalette := false
+	for _, a := range DefaultAccentPalette {
+		inPalette = inPalette || a == got
+	}
+	if !inPalette {
+		t.Fatalf("expect an accent of the default palette, got %v", got)
+	}
+
+	// The alerts are spread over the palette.
+	seen := make(map[v1alpha1.Accent]bool)
+	for _, pod := range []string{"web-0", "web-1", "web-2", "web-3", "web-4", "web-5", "web-6", "web-7"} {
+		seen[AlertAccent(opts, template.Alert{Fingerprint: pod})] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("expect the alerts with different accents, got %v", seen)
+	}
+}
+
+func TestAccentInTemplate(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}{{ range .Alerts }}{{ with .Accent.Emoji }}{{ . }} {{ end }}{{ .Labels.alertname }};{{ end }}{{ end }}`)
+	accent := &v1alpha1.AlertAccent{Enabled: true, Severities: map[string]v1alpha1.Accent{"critical": {Color: "#E01E5A", Emoji: "🟥"}}}
+	data := template.Data{
+		Alerts: template.Alerts{
+			{Status: "firing", Labels: template.KV{"alertname": "NodeDown", "severity": "critical"}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		accent *v1alpha1.AlertAccent
+		want   string
+	}{
+		{"disabled", nil, "NodeDown;"},
+		{"enabled", accent, "🟥 NodeDown;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.WithAlertAccent(tt.accent).TempleText("msg", data, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if tmpl.WithAlertAccent(accent).CacheKey("msg") == tmpl.CacheKey("msg") {
+		t.Fatal("expect the templates rendering the accents cached separately")
+	}
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"strings"
+)
+
+const (
+	DefaultCardinalityShowValues = 5
+)
+
+// CollapseHighCardinality collapses the labels and the annotations which have more distinct values in the alerts
+// than the max values of the guard, such as a unique `pod` of each alert, for the chat notifiers.
+// The alerts which differ only in these keys are merged into one alert, whose value of such a key is
+// `N distinct values (showing first K): v1, v2, ...`, so the message is readable and does not exceed the limits.
+func CollapseHighCardinality(opts *v1alpha1.Options, data template.Data) template.Data {
+
+	if opts == nil || opts.Global == nil || opts.Global.CardinalityGuard == nil || opts.Global.CardinalityGuard.MaxValues <= 0 {
+		return data
+	}
+
+	guard := opts.Global.CardinalityGuard
+	show := guard.ShowValues
+	if show <= 0 {
+		show = DefaultCardinalityShowValues
+	}
+
+	labels := highCardinalityKeys(data.Alerts, guard.MaxValues, func(a template.Alert) template.KV { return a.Labels })
+	annotations := highCardinalityKeys(data.Alerts, guard.MaxValues, func(a template.Alert) template.KV { return a.Annotations })
+	if len(labels) == 0 && len(annotations) == 0 {
+		return data
+	}
+
+	var keys []string
+	groups := make(map[string]template.Alerts)
+	for _, a := range data.Alerts {
+		key := fmt.Sprintf("%s/%s/%s", a.Status, kvWithout(a.Labels, labels), kvWithout(a.Annotations, annotations))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], a)
+	}
+
+	var alerts template.Alerts
+	for _, key := range keys {
+		alerts = append(alerts, mergeAlerts(groups[key], labels, annotations, show))
+	}
+
+	stats.GetCounters().Add("cardinality_collapsed", len(data.Alerts)-len(alerts))
+	data.Alerts = alerts
+	return data
+}
+
+// The keys which have more distinct values than `max` in the alerts.
+func highCardinalityKeys(alerts template.Alerts, max int, kv func(a template.Alert) template.KV) map[string]bool {
+
+	values := make(map[string]map[string]bool)
+	for _, a := range alerts {
+		for k, v := range kv(a) {
+			if _, ok := values[k]; !ok {
+				values[k] = make(map[string]bool)
+			}
+			values[k][v] = true
+		}
+	}
+
+	keys := make(map[string]bool)
+	for k, vs := range values {
+		if len(vs) > max {
+			keys[k] = true
+		}
+	}
+
+	return keys
+}
+
+func kvWithout(kv template.KV, keys map[string]bool) string {
+
+	var s []string
+	for _, p := range kv.SortedPairs() {
+		if !keys[p.Name] {
+			s = append(s, fmt.Sprintf("%s=%s", p.Name, p.Value))
+		}
+	}
+
+	return strings.Join(s, ",")
+}
+
+// Merge the alerts into the first one, the values of the keys are collapsed in the order they appear.
+func mergeAlerts(alerts template.Alerts, labels, annotations map[string]bool, show int) template.Alert {
+
+	merged := alerts[0]
+	merged.Labels = collapseKV(alerts, labels, show, func(a template.Alert) template.KV { return a.Labels })
+	merged.Annotations = collapseKV(alerts, annotations, show, func(a template.Alert) template.KV { return a.Annotations })
+
+	for _, a := range alerts[1:] {
+		if a.StartsAt.Before(merged.StartsAt) {
+			merged.StartsAt = a.StartsAt
+		}
+		if a.EndsAt.After(merged.EndsAt) {
+			merged.EndsAt = a.EndsAt
+		}
+	}
+
+	return merged
+}
+
+func collapseKV(alerts template.Alerts, keys map[string]bool, show int, kv func(a template.Alert) template.KV) template.KV {
+
+	res := template.KV{}
+	for k, v := range kv(alerts[0]) {
+		res[k] = v
+	}
+
+	for k := range keys {
+		var values []string
+		seen := make(map[string]bool)
+		for _, a := range alerts {
+			if v, ok := kv(a)[k]; ok && !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+
+		switch {
+		case len(values) == 0:
+		case len(values) == 1:
+			res[k] = values[0]
+		case len(values) <= show:
+			res[k] = fmt.Sprintf("%d distinct values: %s", len(values), strings.Join(values, ", "))
+		default:
+			res[k] = fmt.Sprintf("%d distinct values (showing first %d): %s", len(values), show, strings.Join(values[:show], ", "))
+		}
+	}
+
+	return res
+}
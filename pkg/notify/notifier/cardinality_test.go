@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func podAlerts(n int) template.Alerts {
+
+	var alerts template.Alerts
+	for i := 0; i < n; i++ {
+		alerts = append(alerts, template.Alert{
+			Status:      "firing",
+			Labels:      template.KV{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": fmt.Sprintf("web-%d", i)},
+			Annotations: template.KV{"message": fmt.Sprintf("pod web-%d is crash looping", i), "runbook": "https://runbook"},
+			StartsAt:    time.Unix(int64(100-i), 0),
+			EndsAt:      time.Unix(int64(200+i), 0),
+		})
+	}
+
+	return alerts
+}
+
+func TestCollapseHighCardinality(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		guard  *v1alpha1.CardinalityGuard
+		alerts template.Alerts
+		// The labels and the annotations of the alerts after collapsing.
+		labels      []template.KV
+		annotations []template.KV
+	}{
+		{
+			name:        "no guard",
+			alerts:      podAlerts(2),
+			labels:      []template.KV{podAlerts(2)[0].Labels, podAlerts(2)[1].Labels},
+			annotations: []template.KV{podAlerts(2)[0].Annotations, podAlerts(2)[1].Annotations},
+		},
+		{
+			name:        "zero max values",
+			guard:       &v1alpha1.CardinalityGuard{},
+			alerts:      podAlerts(2),
+			labels:      []template.KV{podAlerts(2)[0].Labels, podAlerts(2)[1].Labels},
+			annotations: []template.KV{podAlerts(2)[0].Annotations, podAlerts(2)[1].Annotations},
+		},
+		{
+			name:        "at the cap",
+			guard:       &v1alpha1.CardinalityGuard{MaxValues: 2},
+			alerts:      podAlerts(2),
+			labels:      []template.KV{podAlerts(2)[0].Labels, podAlerts(2)[1].Labels},
+			annotations: []template.KV{podAlerts(2)[0].Annotations, podAlerts(2)[1].Annotations},
+		},
+		{
+			name:   "all values shown",
+			guard:  &v1alpha1.CardinalityGuard{MaxValues: 2},
+			alerts: podAlerts(3),
+			labels: []template.KV{
+				{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": "3 distinct values: web-0, web-1, web-2"},
+			},
+			annotations: []template.KV{{
+				"message": "3 distinct values: pod web-0 is crash looping, pod web-1 is crash looping, pod web-2 is crash looping",
+				"runbook": "https://runbook",
+			}},
+		},
+		{
+			name:   "first values shown",
+			guard:  &v1alpha1.CardinalityGuard{MaxValues: 1, ShowValues: 2},
+			alerts: podAlerts(4),
+			labels: []template.KV{
+				{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": "4 distinct values (showing first 2): web-0, web-1"},
+			},
+			annotations: []template.KV{{
+				"message": "4 distinct values (showing first 2): pod web-0 is crash looping, pod web-1 is crash looping",
+				"runbook": "https://runbook",
+			}},
+		},
+		{
+			name:   "default show values",
+			guard:  &v1alpha1.CardinalityGuard{MaxValues: 1},
+			alerts: podAlerts(6),
+			labels: []template.KV{
+				{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": "6 distinct values (showing first 5): web-0, web-1, web-2, web-3, web-4"},
+			},
+			annotations: []template.KV{{
+				"message": "6 distinct values (showing first 5): pod web-0 is crash looping, pod web-1 is crash looping, " +
+					"pod web-2 is crash looping, pod web-3 is crash looping, pod web-4 is crash looping",
+				"runbook": "https://runbook",
+			}},
+		},
+		{
+			name:  "alerts differing in other keys are not merged",
+			guard: &v1alpha1.CardinalityGuard{MaxValues: 2},
+			alerts: func() template.Alerts {
+				alerts := podAlerts(4)
+				alerts[3].Labels["namespace"] = "kube-system"
+				alerts[3].Status = "resolved"
+				return alerts
+			}(),
+			labels: []template.KV{
+				{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": "3 distinct values: web-0, web-1, web-2"},
+				{"alertname": "KubePodCrashLooping", "namespace": "kube-system", "pod": "web-3"},
+			},
+			annotations: []template.KV{
+				{
+					"message": "3 distinct values: pod web-0 is crash looping, pod web-1 is crash looping, pod web-2 is crash looping",
+					"runbook": "https://runbook",
+				},
+				{"message": "pod web-3 is crash looping", "runbook": "https://runbook"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{CardinalityGuard: tt.guard}}
+			data := CollapseHighCardinality(opts, template.Data{Alerts: tt.alerts})
+
+			if len(data.Alerts) != len(tt.labels) {
+				t.Fatalf("expect %d alerts, got %d", len(tt.labels), len(data.Alerts))
+			}
+			for i, a := range data.Alerts {
+				if !reflect.DeepEqual(a.Labels, tt.labels[i]) {
+					t.Errorf("labels of alert %d = %v, want %v", i, a.Labels, tt.labels[i])
+				}
+				if !reflect.DeepEqual(a.Annotations, tt.annotations[i]) {
+					t.Errorf("annotations of alert %d = %v, want %v", i, a.Annotations, tt.annotations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCollapseHighCardinalityTimes(t *testing.T) {
+
+	alerts := podAlerts(3)
+	opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{CardinalityGuard: &v1alpha1.CardinalityGuard{MaxValues: 1}}}
+	data := CollapseHighCardinality(opts, template.Data{Alerts: alerts})
+
+	if len(data.Alerts) != 1 {
+		t.Fatalf("expect 1 alert, got %d", len(data.Alerts))
+	}
+	// The merged alert starts at the earliest and ends at the latest.
+	if a := data.Alerts[0]; !a.StartsAt.Equal(time.Unix(98, 0)) || !a.EndsAt.Equal(time.Unix(202, 0)) {
+		t.Fatalf("merged alert is from %s to %s", a.StartsAt, a.EndsAt)
+	}
+	// The alerts of the caller are not changed.
+	if alerts[0].Labels["pod"] != "web-0" {
+		t.Fatalf("the labels of the alerts are changed to %v", alerts[0].Labels)
+	}
+}
@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"regexp"
+	"strings"
+)
+
+const (
+	maskedSecret = "******"
+)
+
+var (
+	configErrorHandler func(logger log.Logger, notifierCfg *config.Config, e *ConfigError)
+
+	// The password in the userinfo of a url, and the values of the query parameters which are probably secrets.
+	urlPasswordRegexp = regexp.MustCompile(`(://[^:/@\s]*:)[^@/\s]+@`)
+	secretParamRegexp = regexp.MustCompile(`(?i)((?:access_token|token|secret|password|passwd|key|apikey|api_key|sign|signature|corpsecret)=)[^&\s"']+`)
+)
+
+// ConfigError is an error which makes a notifier ignore a receiver, such as a receiver without a config.
+type ConfigError struct {
+	// The type of the notifier, such as `Email`.
+	Type      string
+	TenantID  string
+	Namespace string
+	// The message of the error, the secrets in it are masked.
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("the %s receiver of tenant %q in namespace %q is ignored: %s", e.Type, e.TenantID, e.Namespace, e.Message)
+}
+
+// OnConfigError sets the handler of the config errors reported by the notifiers, such as notifying the administrators.
+func OnConfigError(f func(logger log.Logger, notifierCfg *config.Config, e *ConfigError)) {
+	configErrorHandler = f
+}
+
+// ReportConfigError logs the config error of the receiver and passes it to the handler.
+// The notifiers are created for each notification, so the handler gets the same error repeatedly.
+func ReportConfigError(logger log.Logger, notifierCfg *config.Config, notifierType string, r config.Receiver, err error) {
+
+	e := &ConfigError{
+		Type:    notifierType,
+		Message: MaskSecrets(err.Error()),
+	}
+	if r != nil {
+		e.TenantID = r.GetTenantID()
+		if ns, ok := r.(interface{ GetNamespace() string }); ok {
+			e.Namespace = ns.GetNamespace()
+		}
+	}
+
+	_ = level.Warn(logger).Log("msg", "ignore receiver because of config error", "type", e.Type,
+		"tenant", e.TenantID, "namespace", e.Namespace, "error", e.Message)
+
+	if configErrorHandler != nil {
+		configErrorHandler(logger, notifierCfg, e)
+	}
+}
+
+// MaskSecrets masks the passwords in the urls and the values of the secret query parameters in s,
+// such as the access token in the webhook url of a DingTalk robot.
+func MaskSecrets(s string) string {
+
+	s = urlPasswordRegexp.ReplaceAllString(s, "${1}"+maskedSecret+"@")
+	return secretParamRegexp.ReplaceAllString(s, "${1}"+maskedSecret)
+}
+
+// ReportTemplateError reports the error of getting the template for each receiver of the notifier type,
+// all of them are ignored because the notifier can not be created.
+func ReportTemplateError(logger log.Logger, notifierCfg *config.Config, notifierType string, receivers []config.Receiver, err error) {
+
+	for _, r := range receivers {
+		if r == nil || !strings.EqualFold(config.ReceiverType(r), notifierType) {
+			continue
+		}
+
+		ReportConfigError(logger, notifierCfg, notifierType, r, fmt.Errorf("get template error, %s", err.Error()))
+	}
+}
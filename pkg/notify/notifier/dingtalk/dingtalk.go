@@ -13,6 +13,7 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/template"
 	"net/http"
 	"net/url"
@@ -52,6 +53,8 @@ type Notifier struct {
 	conversationThreshold      int
 	conversationUnit           time.Duration
 	conversationMaxWaitTime    time.Duration
+	// The mode to generate message with the status templates.
+	statusTemplateMode string
 }
 
 type dingtalkMessageContent struct {
@@ -81,7 +84,7 @@ func NewDingTalkNotifier(logger log.Logger, receivers []config.Receiver, notifie
 	}
 	tmpl, err := notifier.NewTemplate(path)
 	if err != nil {
-		_ = level.Error(logger).Log("msg", "DingTalkNotifier: get template error", "error", err.Error())
+		notifier.ReportTemplateError(logger, notifierCfg, "DingTalk", receivers, err)
 		return nil
 	}
 
@@ -159,6 +162,8 @@ func NewDingTalkNotifier(logger log.Logger, receivers []config.Receiver, notifie
 				n.conversationMaxWaitTime = t.MaxWaitTime
 			}
 		}
+
+		n.statusTemplateMode = d.StatusTemplateMode
 	}
 
 	for _, r := range receivers {
@@ -168,7 +173,7 @@ func NewDingTalkNotifier(logger log.Logger, receivers []config.Receiver, notifie
 		}
 
 		if receiver.DingTalkConfig == nil {
-			_ = level.Warn(logger).Log("msg", "DingTalkNotifier: ignore receiver because of empty config")
+			notifier.ReportConfigError(logger, notifierCfg, "DingTalk", receiver, fmt.Errorf("no config is found"))
 			continue
 		}
 
@@ -180,6 +185,8 @@ func NewDingTalkNotifier(logger log.Logger, receivers []config.Receiver, notifie
 
 func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 
+	data = notifier.CollapseHighCardinality(n.notifierCfg.ReceiverOpts, data)
+
 	group := async.NewGroup(ctx)
 	for _, dingtalk := range n.DingTalk {
 		d := dingtalk
@@ -210,11 +217,12 @@ func (n *Notifier) sendToChatBot(ctx context.Context, d *config.DingTalk, data t
 		return []error{err}
 	}
 
-	send := func(msg string) error {
+	send := func(msg string) (err error) {
 
 		start := time.Now()
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "DingTalkNotifier: send message to chatbot", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("DingTalk", time.Since(start), err)
 		}()
 
 		dm := dingtalkMessage{
@@ -256,7 +264,7 @@ func (n *Notifier) sendToChatBot(ctx context.Context, d *config.DingTalk, data t
 		}
 		request.Header.Set("Content-Type", "application/json")
 
-		body, err := notifier.DoHttpRequest(context.Background(), nil, request)
+		body, err := notifier.DoHttpRequest(context.Background(), notifier.GetDefaultClient("DingTalk"), request)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: do http error", "error", err)
 			return err
@@ -293,14 +301,20 @@ func (n *Notifier) sendToChatBot(ctx context.Context, d *config.DingTalk, data t
 		keywords = strings.TrimSuffix(keywords, ", ")
 	}
 
-	messages, err := n.template.Split(data, n.chatbotMessageMaxSize-len(keywords), n.templateName, n.logger)
+	links := notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, d.SourceLink).PlainText(data)
+	tmpl := n.template.WithAnnotationSection(d.AnnotationSection).WithOptionalTemplates(d.OptionalTemplates).WithAlertAccent(d.AlertAccent)
+	messages, err := tmpl.SplitByStatus(data, n.chatbotMessageMaxSize-len(keywords)-len(links), tmpl.SelectTemplate(data, "text", n.templateName, n.logger), n.statusTemplateMode, n.logger)
 	if err != nil {
 		_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: split message error", "error", err.Error())
 		return []error{err}
 	}
 
 	group := async.NewGroup(ctx)
-	for _, m := range messages {
+	for i, m := range messages {
+		// The source links are added to the last message.
+		if i == len(messages)-1 {
+			m += links
+		}
 		msg := fmt.Sprintf("%s%s", m, keywords)
 		group.Add(func(stopCh chan interface{}) {
 			n.throttle.TryAdd(webhook, n.chatbotThreshold, n.chatbotUnit, n.chatbotMaxWaitTime)
@@ -330,11 +344,12 @@ func (n *Notifier) sendToConversation(ctx context.Context, d *config.DingTalk, d
 		return []error{err}
 	}
 
-	send := func(msg string) error {
+	send := func(msg string) (err error) {
 
 		start := time.Now()
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "DingTalkNotifier: send message to conversation", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("DingTalk", time.Since(start), err)
 		}()
 
 		token, err := n.getToken(ctx, appkey, appsecret)
@@ -378,7 +393,7 @@ func (n *Notifier) sendToConversation(ctx context.Context, d *config.DingTalk, d
 		}
 		request.Header.Set("Content-Type", "application/json")
 
-		body, err := notifier.DoHttpRequest(context.Background(), nil, request)
+		body, err := notifier.DoHttpRequest(context.Background(), notifier.GetDefaultClient("DingTalk"), request)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: do http error", "error", err)
 			return err
@@ -400,15 +415,21 @@ func (n *Notifier) sendToConversation(ctx context.Context, d *config.DingTalk, d
 		return nil
 	}
 
-	messages, err := n.template.Split(data, n.conversationMessageMaxSize, n.templateName, n.logger)
+	links := notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, d.SourceLink).PlainText(data)
+	tmpl := n.template.WithAnnotationSection(d.AnnotationSection).WithOptionalTemplates(d.OptionalTemplates).WithAlertAccent(d.AlertAccent)
+	messages, err := tmpl.SplitByStatus(data, n.conversationMessageMaxSize-len(links), tmpl.SelectTemplate(data, "text", n.templateName, n.logger), n.statusTemplateMode, n.logger)
 	if err != nil {
 		_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: split message error", "error", err.Error())
 		return nil
 	}
 
 	group := async.NewGroup(ctx)
-	for _, m := range messages {
+	for i, m := range messages {
 		msg := m
+		// The source links are added to the last message.
+		if i == len(messages)-1 {
+			msg += links
+		}
 		group.Add(func(stopCh chan interface{}) {
 			n.throttle.TryAdd(appkey, n.conversationThreshold, n.conversationUnit, n.conversationMaxWaitTime)
 			if n.throttle.Allow(appkey, n.logger) {
@@ -423,6 +444,39 @@ func (n *Notifier) sendToConversation(ctx context.Context, d *config.DingTalk, d
 	return group.Wait()
 }
 
+// WarmUp fetches the access tokens of the conversations in advance, the tokens are cached by the access token service.
+// The chatbots use the webhook without token, nothing to do.
+func (n *Notifier) WarmUp(ctx context.Context) []error {
+
+	var errs []error
+	for _, d := range n.DingTalk {
+		if d.DingTalkConfig.Conversation == nil {
+			continue
+		}
+
+		appkey, err := n.notifierCfg.GetSecretData(d.GetNamespace(), d.DingTalkConfig.Conversation.AppKey)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: get appkey error", "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+
+		appsecret, err := n.notifierCfg.GetSecretData(d.GetNamespace(), d.DingTalkConfig.Conversation.AppSecret)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: get appsecret error", "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+
+		if _, err := n.getToken(ctx, appkey, appsecret); err != nil {
+			_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: warm up error", "error", err.Error())
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
 func (n *Notifier) getToken(ctx context.Context, appkey, appsecret string) (string, error) {
 
 	get := func(ctx context.Context) (string, time.Duration, error) {
@@ -446,7 +500,7 @@ func (n *Notifier) getToken(ctx context.Context, appkey, appsecret string) (stri
 		}
 		request.Header.Set("Content-Type", "application/json")
 
-		body, err := notifier.DoHttpRequest(context.Background(), nil, request)
+		body, err := notifier.DoHttpRequest(context.Background(), notifier.GetDefaultClient("DingTalk"), request)
 		if err != nil {
 			return "", 0, err
 		}
@@ -0,0 +1,310 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/async"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSendTimeout     = time.Second * 5
+	DefaultIndex           = "alerts"
+	DefaultIndexDateLayout = "2006.01"
+	NoIndexDate            = "-"
+	DocumentModeAlert      = "alert"
+	DocumentModeGroup      = "group"
+)
+
+// The http clients are reused across notifications, the key is the md5 of the config and the timeout.
+var clients = notifier.NewClientCache(notifier.DefaultClientCacheSize)
+
+// BulkError means some documents in a bulk request failed to be indexed.
+type BulkError struct {
+	Index   string
+	Failed  int
+	Total   int
+	Reasons []string
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("index %d of %d documents to %s failed, reasons: %s", e.Failed, e.Total, e.Index, strings.Join(e.Reasons, "; "))
+}
+
+type Notifier struct {
+	notifierCfg   *config.Config
+	elasticsearch []*config.Elasticsearch
+	timeout       time.Duration
+	logger        log.Logger
+	documentMode  string
+}
+
+type alertDocument struct {
+	Timestamp time.Time `json:"@timestamp"`
+	Receiver  string    `json:"receiver,omitempty"`
+	template.Alert
+}
+
+type groupDocument struct {
+	Timestamp time.Time `json:"@timestamp"`
+	template.Data
+}
+
+type bulkAction struct {
+	Index bulkActionMeta `json:"index"`
+}
+
+type bulkActionMeta struct {
+	Index string `json:"_index"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []struct {
+		Index *bulkResponseItem `json:"index,omitempty"`
+	} `json:"items"`
+}
+
+type bulkResponseItem struct {
+	Index  string `json:"_index"`
+	Status int    `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error,omitempty"`
+}
+
+func NewElasticsearchNotifier(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier {
+
+	n := &Notifier{
+		notifierCfg:  notifierCfg,
+		timeout:      DefaultSendTimeout,
+		logger:       logger,
+		documentMode: DocumentModeAlert,
+	}
+
+	opts := notifierCfg.ReceiverOpts
+	if opts != nil && opts.Elasticsearch != nil {
+
+		if opts.Elasticsearch.NotificationTimeout != nil {
+			n.timeout = time.Second * time.Duration(*opts.Elasticsearch.NotificationTimeout)
+		}
+
+		if opts.Elasticsearch.DocumentMode == DocumentModeGroup {
+			n.documentMode = DocumentModeGroup
+		}
+	}
+
+	for _, r := range receivers {
+		receiver, ok := r.(*config.Elasticsearch)
+		if !ok || receiver == nil {
+			continue
+		}
+
+		if receiver.ElasticsearchConfig == nil {
+			notifier.ReportConfigError(logger, notifierCfg, "Elasticsearch", receiver, fmt.Errorf("no config is found"))
+			continue
+		}
+
+		n.elasticsearch = append(n.elasticsearch, receiver)
+	}
+
+	return n
+}
+
+func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
+
+	send := func(e *config.Elasticsearch) (err error) {
+
+		start := time.Now()
+		defer func() {
+			_ = level.Debug(n.logger).Log("msg", "ElasticsearchNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Elasticsearch", time.Since(start), err)
+		}()
+
+		index := indexName(e.ElasticsearchConfig, start)
+		body, err := n.bulkBody(index, data, start)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "ElasticsearchNotifier: encode bulk request error", "error", err.Error())
+			return err
+		}
+
+		client, err := n.getClient(e)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "ElasticsearchNotifier: get client error", "error", err.Error())
+			return err
+		}
+
+		for _, host := range e.ElasticsearchConfig.Hosts {
+			var respBody []byte
+			respBody, err = n.doBulk(ctx, client, e, host, body)
+			if err != nil {
+				_ = level.Warn(n.logger).Log("msg", "ElasticsearchNotifier: do bulk request error", "host", host, "error", err.Error())
+				continue
+			}
+
+			if err = checkBulkResponse(index, respBody); err != nil {
+				_ = level.Error(n.logger).Log("msg", "ElasticsearchNotifier: check bulk response error", "host", host, "error", err.Error())
+				return err
+			}
+
+			_ = level.Debug(n.logger).Log("msg", "ElasticsearchNotifier: send message", "to", host, "index", index)
+			return nil
+		}
+
+		return err
+	}
+
+	group := async.NewGroup(ctx)
+	for _, elasticsearch := range n.elasticsearch {
+		e := elasticsearch
+		group.Add(func(stopCh chan interface{}) {
+			stopCh <- send(e)
+		})
+	}
+
+	return group.Wait()
+}
+
+func (n *Notifier) doBulk(ctx context.Context, client *http.Client, e *config.Elasticsearch, host string, body []byte) ([]byte, error) {
+
+	u, err := notifier.UrlWithPath(strings.TrimSuffix(host, "/"), "/_bulk")
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+
+	if err := notifier.SetAuthorization(n.notifierCfg, e.GetNamespace(), e.ElasticsearchConfig.HttpConfig, request); err != nil {
+		return nil, err
+	}
+
+	return notifier.DoHttpRequest(ctx, client, request)
+}
+
+// Generate the body of the bulk request, every document is preceded by an index action.
+func (n *Notifier) bulkBody(index string, data template.Data, timestamp time.Time) ([]byte, error) {
+
+	var documents []interface{}
+	if n.documentMode == DocumentModeGroup {
+		documents = append(documents, &groupDocument{
+			Timestamp: timestamp,
+			Data:      data,
+		})
+	} else {
+		for _, alert := range data.Alerts {
+			documents = append(documents, &alertDocument{
+				Timestamp: timestamp,
+				Receiver:  data.Receiver,
+				Alert:     alert,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, doc := range documents {
+		if err := encoder.Encode(&bulkAction{Index: bulkActionMeta{Index: index}}); err != nil {
+			return nil, err
+		}
+
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Get the http client of the receiver, the client will be reused if the config is not changed.
+func (n *Notifier) getClient(e *config.Elasticsearch) (*http.Client, error) {
+
+	key, err := notifier.Md5key(e.ElasticsearchConfig)
+	if err != nil {
+		return nil, err
+	}
+	key = fmt.Sprintf("%s/%s/%s", e.GetNamespace(), key, n.timeout)
+
+	return clients.Get(key, func() (*http.Client, error) {
+
+		transport, err := notifier.NewTransport(n.notifierCfg, "Elasticsearch", e.GetNamespace(), strings.Join(e.ElasticsearchConfig.Hosts, ","), e.ElasticsearchConfig.HttpConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Client{
+			Transport: transport,
+			Timeout:   n.timeout,
+		}, nil
+	})
+}
+
+func indexName(c *config.ElasticsearchConfig, t time.Time) string {
+
+	index := c.Index
+	if len(index) == 0 {
+		index = DefaultIndex
+	}
+
+	layout := c.IndexDateLayout
+	if len(layout) == 0 {
+		layout = DefaultIndexDateLayout
+	}
+
+	if layout == NoIndexDate {
+		return index
+	}
+
+	return fmt.Sprintf("%s-%s", index, t.Format(layout))
+}
+
+// Check the bulk response, the bulk request returns 200 even if some of the documents failed to be indexed,
+// so every item in the response need to be checked.
+func checkBulkResponse(index string, body []byte) error {
+
+	resp := &bulkResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return err
+	}
+
+	if !resp.Errors {
+		return nil
+	}
+
+	e := &BulkError{
+		Index: index,
+		Total: len(resp.Items),
+	}
+	for _, item := range resp.Items {
+		if item.Index == nil || (item.Index.Error == nil && item.Index.Status < http.StatusMultipleChoices) {
+			continue
+		}
+
+		e.Failed++
+		if item.Index.Error != nil {
+			e.Reasons = append(e.Reasons, fmt.Sprintf("%s: %s", item.Index.Error.Type, item.Index.Error.Reason))
+		} else {
+			e.Reasons = append(e.Reasons, fmt.Sprintf("status %d", item.Index.Status))
+		}
+	}
+
+	if e.Failed == 0 {
+		return nil
+	}
+
+	return e
+}
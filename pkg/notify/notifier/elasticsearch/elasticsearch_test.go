@@ -0,0 +1,239 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestIndexName(t *testing.T) {
+
+	now := time.Date(2024, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		config *config.ElasticsearchConfig
+		want   string
+	}{
+		{"default", &config.ElasticsearchConfig{}, "alerts-2024.01"},
+		{"custom index", &config.ElasticsearchConfig{Index: "history"}, "history-2024.01"},
+		{"daily", &config.ElasticsearchConfig{IndexDateLayout: "2006.01.02"}, "alerts-2024.01.02"},
+		{"yearly", &config.ElasticsearchConfig{Index: "history", IndexDateLayout: "2006"}, "history-2024"},
+		{"no date", &config.ElasticsearchConfig{Index: "history", IndexDateLayout: NoIndexDate}, "history"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := indexName(tt.config, now); got != tt.want {
+				t.Fatalf("indexName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckBulkResponse(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		body        string
+		wantFailed  int
+		wantTotal   int
+		wantReasons []string
+		wantErr     bool
+	}{
+		{
+			name: "all indexed",
+			body: `{"errors":false,"items":[{"index":{"_index":"alerts","status":201}},{"index":{"_index":"alerts","status":201}}]}`,
+		},
+		{
+			name: "partial failure",
+			body: `{"errors":true,"items":[` +
+				`{"index":{"_index":"alerts","status":201}},` +
+				`{"index":{"_index":"alerts","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field"}}},` +
+				`{"index":{"_index":"alerts","status":429}}]}`,
+			wantFailed:  2,
+			wantTotal:   3,
+			wantReasons: []string{"mapper_parsing_exception: failed to parse field", "status 429"},
+			wantErr:     true,
+		},
+		{
+			// The errors flag is set without failed items.
+			name: "errors without failed items",
+			body: `{"errors":true,"items":[{"index":{"_index":"alerts","status":201}}]}`,
+		},
+		{
+			name:    "invalid body",
+			body:    `<html>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBulkResponse("alerts", []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkBulkResponse() = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantFailed == 0 {
+				return
+			}
+
+			e, ok := err.(*BulkError)
+			if !ok {
+				t.Fatalf("expect a bulk error, got %v", err)
+			}
+			if e.Failed != tt.wantFailed || e.Total != tt.wantTotal || len(e.Reasons) != len(tt.wantReasons) {
+				t.Fatalf("unexpected bulk error %+v", e)
+			}
+			for i := range e.Reasons {
+				if e.Reasons[i] != tt.wantReasons[i] {
+					t.Fatalf("reasons = %q, want %q", e.Reasons, tt.wantReasons)
+				}
+			}
+		})
+	}
+}
+
+type bulkServer struct {
+	*httptest.Server
+	// The lines of the bulk requests received.
+	lines [][]string
+}
+
+func newBulkServer(t *testing.T, response string) *bulkServer {
+
+	s := &bulkServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var lines []string
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		s.lines = append(s.lines, lines)
+
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func newTestNotifier(mode string, hosts ...string) *Notifier {
+
+	e := config.NewElasticsearchReceiver().(*config.Elasticsearch)
+	e.SetNamespace("default")
+	e.ElasticsearchConfig = &config.ElasticsearchConfig{Hosts: hosts, Index: "history", IndexDateLayout: NoIndexDate}
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Elasticsearch: &v1alpha1.ElasticsearchOptions{DocumentMode: mode}}}
+	return NewElasticsearchNotifier(log.NewNopLogger(), []config.Receiver{e}, cfg).(*Notifier)
+}
+
+func testData() template.Data {
+
+	return template.Data{
+		Receiver: "prometheus",
+		Status:   "firing",
+		Alerts: template.Alerts{
+			{Status: "firing", Labels: template.KV{"alertname": "DiskFull"}},
+			{Status: "firing", Labels: template.KV{"alertname": "NodeDown"}},
+		},
+	}
+}
+
+func TestNotifyIndexesDocuments(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		mode      string
+		documents int
+	}{
+		{"alert documents", DocumentModeAlert, 2},
+		{"group document", DocumentModeGroup, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newBulkServer(t, `{"errors":false,"items":[]}`)
+			n := newTestNotifier(tt.mode, s.URL)
+
+			if errs := n.Notify(context.Background(), testData()); len(errs) != 0 {
+				t.Fatal(errs)
+			}
+			if len(s.lines) != 1 || len(s.lines[0]) != tt.documents*2 {
+				t.Fatalf("expect %d documents in one bulk request, got %v", tt.documents, s.lines)
+			}
+
+			for i := 0; i < len(s.lines[0]); i += 2 {
+				action := &bulkAction{}
+				if err := json.Unmarshal([]byte(s.lines[0][i]), action); err != nil {
+					t.Fatal(err)
+				}
+				if action.Index.Index != "history" {
+					t.Fatalf("unexpected index action %s", s.lines[0][i])
+				}
+
+				doc := make(map[string]interface{})
+				if err := json.Unmarshal([]byte(s.lines[0][i+1]), &doc); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := doc["@timestamp"]; !ok {
+					t.Fatalf("expect the timestamp in the document %s", s.lines[0][i+1])
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyPartialBulkFailure(t *testing.T) {
+
+	s := newBulkServer(t, `{"errors":true,"items":[`+
+		`{"index":{"_index":"history","status":201}},`+
+		`{"index":{"_index":"history","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`)
+	n := newTestNotifier(DocumentModeAlert, s.URL)
+
+	errs := n.Notify(context.Background(), testData())
+	if len(errs) != 1 {
+		t.Fatalf("expect 1 error, got %v", errs)
+	}
+	e, ok := errs[0].(*BulkError)
+	if !ok || e.Failed != 1 || e.Total != 2 || e.Index != "history" {
+		t.Fatalf("unexpected error %v", errs[0])
+	}
+}
+
+func TestNotifyFallsBackToNextHost(t *testing.T) {
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	s := newBulkServer(t, `{"errors":false,"items":[]}`)
+
+	n := newTestNotifier(DocumentModeGroup, down.URL, s.URL)
+	if errs := n.Notify(context.Background(), testData()); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if len(s.lines) != 1 {
+		t.Fatalf("expect the bulk request sent to the next host, got %d", len(s.lines))
+	}
+}
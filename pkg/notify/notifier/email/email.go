@@ -5,17 +5,24 @@ import (
 	"fmt"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
 	"github.com/kubesphere/notification-manager/pkg/async"
 	nmconfig "github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/notify"
-	"github.com/prometheus/alertmanager/notify/email"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/common/model"
 	"math"
+	"math/rand"
+	"net/mail"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,8 +32,20 @@ const (
 	DefaultSendTimeout      = time.Second * 3
 	DefaultTemplate         = `{{ template "nm.default.html" . }}`
 	DefaultTSubjectTemplate = `{{ template "nm.default.subject" . }}`
+	DefaultFiringIcon       = "🔥"
+	DefaultResolvedIcon     = "✅"
+	DefaultAckSubjectPrefix = "[ACKED]"
+	HeaderConflictSkip      = "skip"
+	HeaderConflictFirst     = "first"
+	HeaderConflictJoin      = "join"
 )
 
+// The headers which can not be set by the custom headers.
+var reservedHeaders = []string{"From", "To", "Cc", "Bcc", "Subject"}
+
+// A header name is printable ASCII characters except `:`.
+var headerNameRegex = regexp.MustCompile(`^[!-9;-~]+$`)
+
 type Notifier struct {
 	notifierCfg *nmconfig.Config
 	email       map[string]*nmconfig.Email
@@ -41,6 +60,21 @@ type Notifier struct {
 	delivery string
 	// The maximum size of receivers in one email.
 	maxEmailReceivers int
+	// The minimum interval between two sends to the same smart host.
+	sendInterval time.Duration
+	// The maximum number of emails sent through the same smart host and sender in a day.
+	dailyQuota    int
+	quotaLocation *time.Location
+}
+
+// QuotaExhaustedError means the daily quota of the smart host and sender is exhausted.
+type QuotaExhaustedError struct {
+	Key   string
+	Limit int
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("daily quota %d of %s is exhausted", e.Limit, e.Key)
 }
 
 func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifierCfg *nmconfig.Config) notifier.Notifier {
@@ -52,7 +86,7 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 	}
 	tmpl, err := notifier.NewTemplate(path)
 	if err != nil {
-		_ = level.Error(logger).Log("msg", "EmailNotifier: get template error", "error", err.Error())
+		notifier.ReportTemplateError(logger, notifierCfg, "Email", receivers, err)
 		return nil
 	}
 
@@ -90,6 +124,20 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 		if len(opts.Email.SubjectTemplate) > 0 {
 			n.subjectTemplateName = opts.Email.SubjectTemplate
 		}
+
+		n.sendInterval = opts.Email.SendInterval
+
+		if opts.Email.DailyQuota > 0 {
+			n.dailyQuota = opts.Email.DailyQuota
+			n.quotaLocation = time.Local
+			if len(opts.Email.QuotaTimeZone) > 0 {
+				if loc, err := time.LoadLocation(opts.Email.QuotaTimeZone); err != nil {
+					_ = level.Warn(logger).Log("msg", "EmailNotifier: load quota time zone error, use local time zone", "error", err.Error())
+				} else {
+					n.quotaLocation = loc
+				}
+			}
+		}
 	}
 
 	for _, r := range receivers {
@@ -99,12 +147,39 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 		}
 
 		if receiver.EmailConfig == nil {
-			_ = level.Warn(logger).Log("msg", "EmailNotifier: ignore receiver because of empty config")
+			notifier.ReportConfigError(logger, notifierCfg, "Email", receiver, fmt.Errorf("no config is found"))
 			continue
 		}
 
+		encoding := receiver.ContentTransferEncoding
+		if !ValidEncoding(encoding) {
+			_ = level.Warn(logger).Log("msg", "EmailNotifier: unsupported content transfer encoding, use auto", "encoding", encoding)
+			encoding = ""
+		}
+
+		envelopeFrom := receiver.EnvelopeFrom
+		if len(envelopeFrom) > 0 {
+			if addr, err := mail.ParseAddress(envelopeFrom); err != nil {
+				_ = level.Warn(logger).Log("msg", "EmailNotifier: invalid envelope-from, use the from address", "envelopeFrom", envelopeFrom, "error", err.Error())
+				envelopeFrom = ""
+			} else {
+				envelopeFrom = addr.Address
+			}
+		}
+
 		if n.delivery == Bulk {
-			c := n.clone(receiver.EmailConfig)
+			c := nmconfig.NewEmail(nil)
+			_ = c.SetConfig(n.clone(receiver.EmailConfig))
+			c.SubjectLabels = receiver.SubjectLabels
+			c.SourceLink = receiver.SourceLink
+			c.OptionalTemplates = receiver.OptionalTemplates
+			c.AnnotationSection = receiver.AnnotationSection
+			c.SubjectIcons = receiver.SubjectIcons
+			c.CollapsibleAlerts = receiver.CollapsibleAlerts
+			c.MarkdownAnnotations = receiver.MarkdownAnnotations
+			c.ContentTransferEncoding = encoding
+			c.EnvelopeFrom = envelopeFrom
+			c.Headers = receiver.Headers
 			key, err := notifier.Md5key(c)
 			if err != nil {
 				_ = level.Error(logger).Log("msg", "EmailNotifier: get notifier error", "error", err.Error())
@@ -113,8 +188,9 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 
 			e, ok := n.email[key]
 			if !ok {
-				e = nmconfig.NewEmail(nil)
-				_ = e.SetConfig(c)
+				e = c
+				// The merged receivers share the config, so the emails are retried with the config of the first one.
+				e.SetName(receiver.GetName())
 				e.SetNamespace(receiver.GetNamespace())
 			}
 
@@ -128,7 +204,18 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 			}
 
 			e := nmconfig.NewEmail(receiver.To)
+			e.SetName(receiver.GetName())
 			_ = e.SetConfig(n.clone(receiver.EmailConfig))
+			e.SubjectLabels = receiver.SubjectLabels
+			e.SourceLink = receiver.SourceLink
+			e.OptionalTemplates = receiver.OptionalTemplates
+			e.AnnotationSection = receiver.AnnotationSection
+			e.SubjectIcons = receiver.SubjectIcons
+			e.CollapsibleAlerts = receiver.CollapsibleAlerts
+			e.MarkdownAnnotations = receiver.MarkdownAnnotations
+			e.ContentTransferEncoding = encoding
+			e.EnvelopeFrom = envelopeFrom
+			e.Headers = receiver.Headers
 			e.SetNamespace(receiver.GetNamespace())
 			n.email[key] = e
 		}
@@ -139,6 +226,19 @@ func NewEmailNotifier(logger log.Logger, receivers []nmconfig.Receiver, notifier
 
 func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 
+	result, err := n.NotifyWithResult(ctx, data)
+	errs := result.Errors()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// NotifyWithResult sends the emails and reports the result of each recipient,
+// the recipients of an email sent to many recipients share the result.
+func (n *Notifier) NotifyWithResult(ctx context.Context, data template.Data) (notifier.Result, error) {
+
 	var as []*types.Alert
 	for _, a := range data.Alerts {
 		as = append(as, &types.Alert{
@@ -152,11 +252,47 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 		})
 	}
 
-	sendEmail := func(e *nmconfig.Email, to string) error {
+	var result notifier.Result
+	var resultMutex sync.Mutex
+	cache := notifier.NewRenderCache()
+	sendEmail := func(e *nmconfig.Email, to string) (err error) {
 
 		start := time.Now()
+		messageID, queueID := "", ""
+		var throttled map[string]error
+		// The error of the address rejected by the server.
+		rejected := func(string) error { return nil }
+		quarantined := make(map[string]error)
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "EmailNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Email", time.Since(start), err)
+
+			resultMutex.Lock()
+			defer resultMutex.Unlock()
+			for _, address := range strings.Split(to, ",") {
+				r := notifier.TargetResult{
+					Type:    "Email",
+					Target:  address,
+					Latency: time.Since(start),
+					Error:   err,
+				}
+				if e, ok := quarantined[address]; ok {
+					r.Error = e
+				} else if e, ok := throttled[address]; ok {
+					r.Error = e
+				} else if e := rejected(address); e != nil {
+					// Only the address rejected by the server is counted, the other errors are not caused by the address.
+					r.Error = e
+					notifier.GetQuarantine().Record(n.logger, n.notifierCfg, notifier.TargetKey("Email", address), e)
+				} else if err == nil {
+					r.MessageID = queueID
+					if len(messageID) > 0 {
+						r.Extra = map[string]string{"Message-Id": messageID}
+					}
+					notifier.GetQuarantine().Record(n.logger, n.notifierCfg, notifier.TargetKey("Email", address), nil)
+				}
+				result.Targets = append(result.Targets, r)
+			}
 		}()
 
 		emailConfig, err := n.getEmailConfig(e)
@@ -165,21 +301,103 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 			return err
 		}
 		emailConfig.To = to
-		emailConfig.HTML = n.templateName
-		emailConfig.Headers["Subject"] = n.subjectTemplateName
-		sender := email.New(emailConfig, n.template.Tmpl, n.logger)
 
+		// The message is rendered once and shared by the emails, alertmanager will render it again as a template,
+		// so it is quoted to keep it as is.
+		tmpl := n.template.WithAnnotationSection(e.AnnotationSection).WithOptionalTemplates(e.OptionalTemplates).
+			WithCollapsible(e.CollapsibleAlerts).WithMarkdownAnnotations(e.MarkdownAnnotations)
+		name := tmpl.SelectTemplate(data, "html", n.templateName, n.logger)
+		body, err := cache.Render("html:"+tmpl.CacheKey(name), data, func() (string, error) {
+			return tmpl.TempleHTML(name, data, n.logger)
+		})
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "EmailNotifier: generate message error", "error", err.Error())
+			return err
+		}
+		body = notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, e.SourceLink).AppendHTML(body, data)
+		emailConfig.HTML = fmt.Sprintf("{{ safeHtml %s }}", strconv.Quote(body))
+
+		var subject string
+		if len(e.SubjectLabels) > 0 {
+			subject = subjectWithLabels(data, e.SubjectLabels)
+		} else {
+			subject, err = cache.Render("text:"+tmpl.CacheKey(n.subjectTemplateName), data, func() (string, error) {
+				return tmpl.TempleText(n.subjectTemplateName, data, n.logger)
+			})
+			if err != nil {
+				_ = level.Error(n.logger).Log("msg", "EmailNotifier: generate subject error", "error", err.Error())
+				return err
+			}
+		}
+		subject = subjectWithAck(subject, data, n.notifierCfg.ReceiverOpts)
+		// The subject is encoded as a MIME encoded-word by alertmanager if it is not ASCII, such as with the icons.
+		subject = subjectWithIcons(subject, data, e.SubjectIcons)
+		emailConfig.Headers["Subject"] = fmt.Sprintf("{{ %s }}", strconv.Quote(subject))
+		if reason, ok := data.CommonAnnotations["notificationReason"]; ok {
+			emailConfig.Headers["X-Notification-Reason"] = reason
+		}
+		// The header values are rendered as templates by the sender, so they are quoted to keep them as is.
+		for name, value := range customHeaders(data, e.Headers, n.logger) {
+			emailConfig.Headers[name] = fmt.Sprintf("{{ %s }}", strconv.Quote(value))
+		}
+		// The Message-Id is generated here rather than by alertmanager, so it can be reported as the id of the message.
+		if _, ok := emailConfig.Headers["Message-Id"]; !ok {
+			messageID = newMessageID()
+			emailConfig.Headers["Message-Id"] = messageID
+		}
+		// The quarantined addresses are dropped first, so they neither wait for the throttle nor consume the quota.
+		emailConfig.To, err = n.dropQuarantined(emailConfig.To, quarantined)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "EmailNotifier: all the addresses are quarantined", "to", to, "error", err.Error())
+			return err
+		}
+
+		// The addresses are throttled before taking the quota, so the dropped sends do not consume it,
+		// and the throttled addresses are not in the To header.
+		emailConfig.To, throttled, err = n.throttle(ctx, emailConfig.To)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "EmailNotifier: all the addresses are throttled", "to", to, "error", err.Error())
+			return err
+		}
+
+		// The emails are sent by the sender of the notification manager rather than alertmanager, as alertmanager
+		// always encodes the body as quoted-printable. The body is in the encoding of the receiver, with the
+		// default `auto`, the UTF-8 body is sent as 8bit if the server advertises 8BITMIME.
+		// The envelope-from of the receiver is sent in the MAIL command if it is set.
+		sender := newSender(emailConfig, n.template.Tmpl(), e.ContentTransferEncoding, n.logger).withEnvelopeFrom(e.EnvelopeFrom)
+		rejected = sender.rejectedError
+
+		if n.dailyQuota > 0 {
+			key := fmt.Sprintf("%s/%s", emailConfig.Smarthost.String(), emailConfig.From)
+			if !stats.GetQuotaCounter().Take(key, n.dailyQuota, n.quotaLocation) {
+				err = &QuotaExhaustedError{Key: key, Limit: n.dailyQuota}
+				_ = level.Error(n.logger).Log("msg", "EmailNotifier: quota exhausted", "to", emailConfig.To, "error", err.Error())
+				return err
+			}
+		}
+
+		if err := GetPacer().Wait(ctx, emailConfig.Smarthost.String(), n.sendInterval); err != nil {
+			_ = level.Error(n.logger).Log("msg", "EmailNotifier: wait for send interval error", "to", emailConfig.To, "error", err.Error())
+			return err
+		}
+
+		retryCtx := ctx
 		ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
 		ctx = notify.WithGroupLabels(ctx, notifier.KvToLabelSet(data.GroupLabels))
 		ctx = notify.WithReceiverName(ctx, data.Receiver)
 		defer cancel()
 
-		_, err = sender.Notify(ctx, as...)
+		retry, err := sender.Notify(ctx, as...)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "EmailNotifier: notify error", "from", emailConfig.From, "to", emailConfig.To, "error", err.Error())
+			if retry {
+				n.enqueueRetry(retryCtx, e, emailConfig.To, data)
+			}
 			return err
 		}
-		_ = level.Debug(n.logger).Log("msg", "EmailNotifier: send message", "from", emailConfig.From, "to", emailConfig.To)
+		queueID = sender.queueID
+		_ = level.Debug(n.logger).Log("msg", "EmailNotifier: send message", "from", emailConfig.From, "to", emailConfig.To,
+			"queueID", queueID, "messageID", messageID)
 		return nil
 	}
 
@@ -207,20 +425,98 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 				}
 				to = strings.TrimSuffix(to, ",")
 				group.Add(func(stopCh chan interface{}) {
-					stopCh <- sendEmail(e, to)
+					_ = sendEmail(e, to)
+					stopCh <- nil
 				})
 			}
 		} else {
 			for _, t := range e.To {
 				to := t
 				group.Add(func(stopCh chan interface{}) {
-					stopCh <- sendEmail(e, to)
+					_ = sendEmail(e, to)
+					stopCh <- nil
 				})
 			}
 		}
 	}
 
-	return group.Wait()
+	// The errors of the sends are in the result, only the error of the group, such as timing out, is returned.
+	var err error
+	if errs := group.Wait(); len(errs) > 0 {
+		err = errs[0]
+	}
+
+	resultMutex.Lock()
+	defer resultMutex.Unlock()
+
+	return notifier.Result{Targets: append([]notifier.TargetResult(nil), result.Targets...)}, err
+}
+
+// Reserve the sends to the addresses with the flow control of the targets, the throttled addresses are removed
+// and returned with their errors. The error is returned if all the addresses are throttled.
+func (n *Notifier) throttle(ctx context.Context, to string) (string, map[string]error, error) {
+
+	t := notifier.TargetThrottle(n.notifierCfg.ReceiverOpts)
+	if t == nil {
+		return to, nil, nil
+	}
+
+	var addresses []string
+	throttled := make(map[string]error)
+	var err error
+	for _, address := range strings.Split(to, ",") {
+		if e := notifier.GetTargetLimiter().Wait(ctx, notifier.TargetKey("Email", address), t); e != nil {
+			_ = level.Warn(n.logger).Log("msg", "EmailNotifier: address dropped because of flow control", "to", address, "error", e.Error())
+			throttled[address] = e
+			err = e
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	if len(addresses) == 0 {
+		return "", throttled, err
+	}
+
+	return strings.Join(addresses, ","), throttled, nil
+}
+
+// Remove the quarantined addresses, they are added to the map with their errors.
+// The error is returned if all the addresses are quarantined.
+func (n *Notifier) dropQuarantined(to string, quarantined map[string]error) (string, error) {
+
+	if notifier.TargetQuarantine(n.notifierCfg.ReceiverOpts) == nil {
+		return to, nil
+	}
+
+	var addresses []string
+	var err error
+	for _, address := range strings.Split(to, ",") {
+		if e := notifier.GetQuarantine().Check(notifier.TargetKey("Email", address)); e != nil {
+			_ = level.Debug(n.logger).Log("msg", "EmailNotifier: address dropped because it is quarantined", "to", address, "error", e.Error())
+			quarantined[address] = e
+			err = e
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	if len(addresses) == 0 {
+		return "", err
+	}
+
+	return strings.Join(addresses, ","), nil
+}
+
+// The same form as alertmanager generates, `<nanoseconds.random@hostname>`.
+func newMessageID() string {
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	return fmt.Sprintf("<%d.%d@%s>", time.Now().UnixNano(), rand.Uint64(), hostname)
 }
 
 func (n *Notifier) clone(ec *nmconfig.EmailConfig) *nmconfig.EmailConfig {
@@ -278,3 +574,168 @@ func (n *Notifier) getEmailConfig(e *nmconfig.Email) (*config.EmailConfig, error
 
 	return ec, nil
 }
+
+// Generate the subject keyed on the `labels` in order, the values of a label which differ between alerts are joined with `,`.
+func subjectWithLabels(data template.Data, labels []string) string {
+
+	var identity []string
+	for _, name := range labels {
+		var values []string
+		for _, alert := range data.Alerts {
+			v, ok := alert.Labels[name]
+			if !ok || nmconfig.StringIn(values, v) {
+				continue
+			}
+			values = append(values, v)
+		}
+
+		if len(values) > 0 {
+			identity = append(identity, fmt.Sprintf("%s=%s", name, strings.Join(values, ",")))
+		}
+	}
+
+	subject := fmt.Sprintf("[FIRING:%d, RESOLVED:%d] %s", len(data.Alerts.Firing()), len(data.Alerts.Resolved()), strings.Join(identity, " "))
+	return strings.TrimSpace(subject)
+}
+
+// The values of the custom headers, the headers with invalid names or without value are skipped.
+func customHeaders(data template.Data, headers []v1alpha1.EmailHeader, logger log.Logger) map[string]string {
+
+	m := make(map[string]string)
+	for _, h := range headers {
+		if !headerNameRegex.MatchString(h.Name) || isReservedHeader(h.Name) {
+			_ = level.Warn(logger).Log("msg", "EmailNotifier: ignore the invalid custom header", "header", h.Name)
+			continue
+		}
+
+		value := h.Value
+		if len(h.Label) > 0 || len(h.Annotation) > 0 {
+			v, ok := headerValue(data, h)
+			if !ok {
+				continue
+			}
+			value = v
+		}
+
+		// Remove the line breaks, so the value can not inject the other headers.
+		value = strings.TrimSpace(strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value))
+		if len(value) > 0 {
+			m[h.Name] = value
+		}
+	}
+
+	return m
+}
+
+// The value of the label or the annotation of the alerts, the alerts without it are ignored.
+// It returns false if the alerts have different values and the header is skipped on conflict.
+func headerValue(data template.Data, h v1alpha1.EmailHeader) (string, bool) {
+
+	var values []string
+	for _, alert := range data.Alerts {
+		kv, key := alert.Annotations, h.Annotation
+		if len(h.Label) > 0 {
+			kv, key = alert.Labels, h.Label
+		}
+
+		v, ok := kv[key]
+		if !ok || sliceIn(values, v) {
+			continue
+		}
+		values = append(values, v)
+	}
+
+	if len(values) == 0 {
+		return "", false
+	}
+
+	if len(values) == 1 {
+		return values[0], true
+	}
+
+	switch h.OnConflict {
+	case HeaderConflictFirst:
+		return values[0], true
+	case HeaderConflictJoin:
+		return strings.Join(values, ", "), true
+	default:
+		return "", false
+	}
+}
+
+func isReservedHeader(name string) bool {
+
+	for _, h := range reservedHeaders {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Prepend the prefix to the subject if the group is acknowledged.
+func subjectWithAck(subject string, data template.Data, opts *v1alpha1.Options) string {
+
+	if _, ok := data.CommonAnnotations[notifier.AcknowledgedByAnnotation]; !ok {
+		return subject
+	}
+
+	prefix := DefaultAckSubjectPrefix
+	if opts != nil && opts.Global != nil && opts.Global.Acknowledgement != nil && len(opts.Global.Acknowledgement.SubjectPrefix) > 0 {
+		prefix = opts.Global.Acknowledgement.SubjectPrefix
+	}
+
+	return fmt.Sprintf("%s %s", prefix, subject)
+}
+
+// Prepend the icons of the statuses of the alerts to the subject, the group with both firing and resolved alerts
+// shows both icons.
+func subjectWithIcons(subject string, data template.Data, icons *v1alpha1.SubjectIcons) string {
+
+	if icons == nil || !icons.Enabled {
+		return subject
+	}
+
+	firing, resolved := DefaultFiringIcon, DefaultResolvedIcon
+	if len(icons.Firing) > 0 {
+		firing = icons.Firing
+	}
+	if len(icons.Resolved) > 0 {
+		resolved = icons.Resolved
+	}
+
+	prefix := ""
+	if len(data.Alerts.Firing()) > 0 {
+		prefix += firing
+	}
+	if len(data.Alerts.Resolved()) > 0 {
+		prefix += resolved
+	}
+
+	if len(prefix) == 0 {
+		return subject
+	}
+
+	return prefix + " " + subject
+}
+
+// Whether the address is the same as the address which may have a display name.
+func sameAddress(address, s string) bool {
+
+	if addr, err := mail.ParseAddress(s); err == nil {
+		s = addr.Address
+	}
+
+	return strings.EqualFold(address, strings.TrimSpace(s))
+}
+
+func sliceIn(src []string, elem string) bool {
+	for _, s := range src {
+		if s == elem {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,736 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	nmconfig "github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// fakeSMTP is an SMTP server recording the commands and the messages it receives.
+type fakeSMTP struct {
+	host string
+	port string
+	// The replies of the commands starting with the keys, such as `RCPT TO:<bad@x.io>`,
+	// the key `.` is the reply of the end of the message.
+	replies    map[string]string
+	extensions []string
+	// The connection is upgraded to TLS on STARTTLS if it is set.
+	tlsConfig *tls.Config
+
+	mutex    sync.Mutex
+	commands []string
+	messages []string
+}
+
+func newFakeSMTP(t *testing.T, replies map[string]string, extensions ...string) *fakeSMTP {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return startFakeSMTP(t, ln, &fakeSMTP{replies: replies, extensions: extensions})
+}
+
+// Serve the connections accepted by the listener until the test ends.
+func startFakeSMTP(t *testing.T, ln net.Listener, s *fakeSMTP) *fakeSMTP {
+
+	t.Cleanup(func() { _ = ln.Close() })
+	s.host, s.port, _ = net.SplitHostPort(ln.Addr().String())
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.TrimSpace(line)
+		s.record(&s.commands, cmd)
+
+		if v, ok := s.reply(cmd); ok {
+			reply(v)
+			continue
+		}
+
+		switch upper := strings.ToUpper(cmd); {
+		case strings.HasPrefix(upper, "EHLO"):
+			// The first line is the greeting, the extensions follow.
+			reply("250-fake")
+			for _, ext := range s.extensions {
+				reply("250-" + ext)
+			}
+			reply("250 HELP")
+		case upper == "STARTTLS" && s.tlsConfig != nil:
+			reply("220 ready to start TLS")
+			tc := tls.Server(conn, s.tlsConfig)
+			if err := tc.Handshake(); err != nil {
+				return
+			}
+			conn, r = tc, bufio.NewReader(tc)
+		case upper == "DATA":
+			reply("354 go ahead")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				sb.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.record(&s.messages, sb.String())
+			if v, ok := s.reply("."); ok {
+				reply(v)
+			} else {
+				reply("250 2.0.0 Ok: queued as 4F2K1")
+			}
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTP) reply(cmd string) (string, bool) {
+
+	for k, v := range s.replies {
+		if strings.HasPrefix(strings.ToUpper(cmd), strings.ToUpper(k)) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *fakeSMTP) record(to *[]string, v string) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	*to = append(*to, v)
+}
+
+// The commands received starting with the prefix.
+func (s *fakeSMTP) received(prefix string) []string {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var res []string
+	for _, c := range s.commands {
+		if strings.HasPrefix(strings.ToUpper(c), strings.ToUpper(prefix)) {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+func (s *fakeSMTP) receivedMessages() []string {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string(nil), s.messages...)
+}
+
+// A notifier sending the emails of the receiver to the server, with a template file defining the default templates.
+func newTestNotifier(t *testing.T, s *fakeSMTP, opts *v1alpha1.Options, receiver *nmconfig.Email) *Notifier {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(`{{ define "nm.default.html" }}{{ range .Alerts }}<p>{{ .Labels.alertname }}</p>{{ end }}{{ end }}`+
+		`{{ define "nm.default.subject" }}[{{ .Status }}] {{ .GroupLabels.alertname }}{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if opts == nil {
+		opts = &v1alpha1.Options{}
+	}
+	if opts.Global == nil {
+		opts.Global = &v1alpha1.GlobalOptions{}
+	}
+	opts.Global.TemplateFiles = []string{file}
+
+	receiver.EmailConfig = &nmconfig.EmailConfig{
+		From:      "alerts@example.com",
+		SmartHost: v1alpha1.HostPort{Host: s.host, Port: s.port},
+	}
+	receiver.SetNamespace("default")
+
+	n, ok := NewEmailNotifier(log.NewNopLogger(), []nmconfig.Receiver{receiver}, &nmconfig.Config{ReceiverOpts: opts}).(*Notifier)
+	if !ok || n == nil {
+		t.Fatal("create the email notifier failed")
+	}
+
+	return n
+}
+
+func testData() template.Data {
+
+	return template.Data{
+		Receiver:    "prometheus",
+		Status:      "firing",
+		GroupLabels: template.KV{"alertname": "KubePodCrashLooping"},
+		Alerts: template.Alerts{
+			{
+				Status:   "firing",
+				Labels:   template.KV{"alertname": "KubePodCrashLooping", "namespace": "default"},
+				StartsAt: time.Now(),
+			},
+		},
+	}
+}
+
+// The Subject header of the message.
+func subjectOf(message string) string {
+
+	for _, line := range strings.Split(message, "\r\n") {
+		if strings.HasPrefix(line, "Subject: ") {
+			return strings.TrimPrefix(line, "Subject: ")
+		}
+	}
+
+	return ""
+}
+
+func TestSubjectWithLabels(t *testing.T) {
+
+	alert := func(status string, labels ...string) template.Alert {
+		kv := template.KV{}
+		for i := 0; i+1 < len(labels); i += 2 {
+			kv[labels[i]] = labels[i+1]
+		}
+		return template.Alert{Status: status, Labels: kv}
+	}
+
+	tests := []struct {
+		name   string
+		alerts template.Alerts
+		labels []string
+		want   string
+	}{
+		{
+			name:   "labels in order",
+			alerts: template.Alerts{alert("firing", "alertname", "KubePodCrashLooping", "namespace", "default")},
+			labels: []string{"namespace", "alertname"},
+			want:   "[FIRING:1, RESOLVED:0] namespace=default alertname=KubePodCrashLooping",
+		},
+		{
+			name: "values joined across alerts",
+			alerts: template.Alerts{
+				alert("firing", "namespace", "default", "pod", "a"),
+				alert("resolved", "namespace", "default", "pod", "b"),
+				alert("firing", "namespace", "kube-system", "pod", "a"),
+			},
+			labels: []string{"namespace", "pod"},
+			want:   "[FIRING:2, RESOLVED:1] namespace=default,kube-system pod=a,b",
+		},
+		{
+			name:   "missing labels are skipped",
+			alerts: template.Alerts{alert("resolved", "alertname", "KubePodCrashLooping")},
+			labels: []string{"cluster", "alertname"},
+			want:   "[FIRING:0, RESOLVED:1] alertname=KubePodCrashLooping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subjectWithLabels(template.Data{Alerts: tt.alerts}, tt.labels); got != tt.want {
+				t.Fatalf("subject = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubjectWithIcons(t *testing.T) {
+
+	data := func(statuses ...string) template.Data {
+		d := template.Data{}
+		for _, status := range statuses {
+			d.Alerts = append(d.Alerts, template.Alert{Status: status})
+		}
+		return d
+	}
+
+	tests := []struct {
+		name  string
+		data  template.Data
+		icons *v1alpha1.SubjectIcons
+		want  string
+	}{
+		{name: "not set", data: data("firing"), want: "disk full"},
+		{name: "disabled", data: data("firing"), icons: &v1alpha1.SubjectIcons{Firing: "F"}, want: "disk full"},
+		{name: "firing", data: data("firing"), icons: &v1alpha1.SubjectIcons{Enabled: true}, want: DefaultFiringIcon + " disk full"},
+		{name: "resolved", data: data("resolved"), icons: &v1alpha1.SubjectIcons{Enabled: true}, want: DefaultResolvedIcon + " disk full"},
+		{
+			name:  "mixed",
+			data:  data("resolved", "firing"),
+			icons: &v1alpha1.SubjectIcons{Enabled: true},
+			want:  DefaultFiringIcon + DefaultResolvedIcon + " disk full",
+		},
+		{
+			name:  "custom icons",
+			data:  data("firing", "resolved"),
+			icons: &v1alpha1.SubjectIcons{Enabled: true, Firing: "[F]", Resolved: "[R]"},
+			want:  "[F][R] disk full",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subjectWithIcons("disk full", tt.data, tt.icons); got != tt.want {
+				t.Fatalf("subject = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifySubject(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   string
+	}{
+		{"default subject template", nil, "[firing] KubePodCrashLooping"},
+		{"subject labels", []string{"namespace", "alertname"}, "[FIRING:1, RESOLVED:0] namespace=default alertname=KubePodCrashLooping"},
+		// The label values are not rendered as a template.
+		{"template actions in labels", []string{"pod"}, `[FIRING:1, RESOLVED:0] pod={{ .Status }}"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, nil)
+			r := nmconfig.NewEmail([]string{"ops@example.com"})
+			r.SubjectLabels = tt.labels
+			n := newTestNotifier(t, s, nil, r)
+
+			data := testData()
+			data.Alerts[0].Labels["pod"] = `{{ .Status }}"`
+			if errs := n.Notify(context.Background(), data); len(errs) != 0 {
+				t.Fatal(errs)
+			}
+
+			messages := s.receivedMessages()
+			if len(messages) != 1 {
+				t.Fatalf("expect 1 email, got %d", len(messages))
+			}
+			if got := subjectOf(messages[0]); got != tt.want {
+				t.Fatalf("subject = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// The value of the header of the message.
+func headerOf(message, name string) string {
+
+	for _, line := range strings.Split(message, "\r\n") {
+		if strings.HasPrefix(line, name+": ") {
+			return strings.TrimPrefix(line, name+": ")
+		}
+	}
+
+	return ""
+}
+
+func TestCustomHeaders(t *testing.T) {
+
+	data := template.Data{
+		Alerts: template.Alerts{
+			{Labels: template.KV{"team": "ops", "namespace": "default"}, Annotations: template.KV{"owner": "alice"}},
+			{Labels: template.KV{"team": "dev", "namespace": "default"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		headers []v1alpha1.EmailHeader
+		want    map[string]string
+	}{
+		{"static value", []v1alpha1.EmailHeader{{Name: "X-Source", Value: "notification-manager"}}, map[string]string{"X-Source": "notification-manager"}},
+		{"label", []v1alpha1.EmailHeader{{Name: "X-Namespace", Label: "namespace"}}, map[string]string{"X-Namespace": "default"}},
+		{"annotation of some alerts", []v1alpha1.EmailHeader{{Name: "X-Owner", Annotation: "owner"}}, map[string]string{"X-Owner": "alice"}},
+		{"missing label", []v1alpha1.EmailHeader{{Name: "X-Cluster", Label: "cluster", Value: "host"}}, map[string]string{}},
+		{"conflict skipped", []v1alpha1.EmailHeader{{Name: "X-Team", Label: "team"}}, map[string]string{}},
+		{"conflict first", []v1alpha1.EmailHeader{{Name: "X-Team", Label: "team", OnConflict: HeaderConflictFirst}}, map[string]string{"X-Team": "ops"}},
+		{"conflict joined", []v1alpha1.EmailHeader{{Name: "X-Team", Label: "team", OnConflict: HeaderConflictJoin}}, map[string]string{"X-Team": "ops, dev"}},
+		{"reserved header", []v1alpha1.EmailHeader{{Name: "Subject", Value: "spoofed"}, {Name: "bcc", Value: "x@example.com"}}, map[string]string{}},
+		{"invalid name", []v1alpha1.EmailHeader{{Name: "X Team", Value: "ops"}, {Name: "X-Team:", Value: "ops"}}, map[string]string{}},
+		{"line breaks removed", []v1alpha1.EmailHeader{{Name: "X-Note", Value: "a\r\nBcc: x@example.com\nb"}}, map[string]string{"X-Note": "a Bcc: x@example.com b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := customHeaders(data, tt.headers, log.NewNopLogger())
+			if len(got) != len(tt.want) {
+				t.Fatalf("customHeaders() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Fatalf("customHeaders() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyCustomHeaders(t *testing.T) {
+
+	s := newFakeSMTP(t, nil)
+	r := nmconfig.NewEmail([]string{"ops@example.com"})
+	r.Headers = []v1alpha1.EmailHeader{{Name: "X-Team", Label: "team"}}
+	n := newTestNotifier(t, s, nil, r)
+
+	data := testData()
+	data.Alerts[0].Labels["team"] = `{{ .Status }}`
+	if errs := n.Notify(context.Background(), data); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	messages := s.receivedMessages()
+	if len(messages) != 1 {
+		t.Fatalf("expect 1 email, got %d", len(messages))
+	}
+	// The label value is not rendered as a template.
+	if got := headerOf(messages[0], "X-Team"); got != `{{ .Status }}` {
+		t.Fatalf("X-Team = %q, want %q", got, `{{ .Status }}`)
+	}
+}
+
+func TestNotifyEnvelopeFrom(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		envelopeFrom string
+		want         string
+	}{
+		{"default", "", "MAIL FROM:<alerts@example.com>"},
+		{"configured", "bounces@example.com", "MAIL FROM:<bounces@example.com>"},
+		{"with display name", "Bounces <bounces@example.com>", "MAIL FROM:<bounces@example.com>"},
+		{"invalid", "bounces", "MAIL FROM:<alerts@example.com>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, nil)
+			r := nmconfig.NewEmail([]string{"ops@example.com"})
+			r.EnvelopeFrom = tt.envelopeFrom
+			n := newTestNotifier(t, s, nil, r)
+
+			if errs := n.Notify(context.Background(), testData()); len(errs) != 0 {
+				t.Fatal(errs)
+			}
+
+			if mail := s.received("MAIL FROM"); len(mail) != 1 || mail[0] != tt.want {
+				t.Fatalf("expect %q, got %v", tt.want, mail)
+			}
+			// The From header is always the from address.
+			messages := s.receivedMessages()
+			if len(messages) != 1 {
+				t.Fatalf("expect 1 email, got %d", len(messages))
+			}
+			if got := headerOf(messages[0], "From"); got != "alerts@example.com" {
+				t.Fatalf("From = %q, want alerts@example.com", got)
+			}
+		})
+	}
+}
+
+func TestNotifyTargetThrottle(t *testing.T) {
+
+	throttle := &v1alpha1.Throttle{Threshold: 1, Unit: time.Hour}
+	// The address has reached the limit by the sends of another receiver.
+	if err := notifier.GetTargetLimiter().Wait(context.Background(), notifier.TargetKey("Email", "throttled@example.com"), throttle); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newFakeSMTP(t, nil)
+	r := nmconfig.NewEmail([]string{"throttled@example.com", "free@example.com"})
+	n := newTestNotifier(t, s, &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TargetThrottle: throttle}}, r)
+
+	// The addresses are sent in bulk, the throttled one is removed and reported.
+	if errs := n.Notify(context.Background(), testData()); len(errs) != 1 {
+		t.Fatalf("expect 1 error, got %v", errs)
+	} else if _, ok := errs[0].(*notifier.ThrottledError); !ok {
+		t.Fatalf("expect a throttled error, got %v", errs[0])
+	}
+
+	if rcpts := s.received("RCPT TO"); len(rcpts) != 1 || !strings.Contains(rcpts[0], "free@example.com") {
+		t.Fatalf("RCPT TO = %v, want free@example.com only", rcpts)
+	}
+	messages := s.receivedMessages()
+	if len(messages) != 1 || headerOf(messages[0], "To") != "free@example.com" {
+		t.Fatalf("expect 1 email to free@example.com, got %q", messages)
+	}
+
+	// All the addresses are throttled, each of them is reported.
+	errs := n.Notify(context.Background(), testData())
+	if len(errs) != 2 {
+		t.Fatalf("expect 2 errors, got %v", errs)
+	}
+	for _, err := range errs {
+		if _, ok := err.(*notifier.ThrottledError); !ok {
+			t.Fatalf("expect a throttled error, got %v", err)
+		}
+	}
+}
+
+func TestNotifyWithResult(t *testing.T) {
+
+	throttle := &v1alpha1.Throttle{Threshold: 1, Unit: time.Hour}
+	if err := notifier.GetTargetLimiter().Wait(context.Background(), notifier.TargetKey("Email", "result-throttled@example.com"), throttle); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newFakeSMTP(t, nil)
+	r := nmconfig.NewEmail([]string{"ops@example.com", "result-throttled@example.com"})
+	n := newTestNotifier(t, s, &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TargetThrottle: throttle}}, r)
+
+	result, err := n.NotifyWithResult(context.Background(), testData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := s.receivedMessages()
+	if len(messages) != 1 {
+		t.Fatalf("expect 1 email, got %d", len(messages))
+	}
+	if len(result.Targets) != 2 {
+		t.Fatalf("expect 2 targets, got %+v", result.Targets)
+	}
+
+	for _, target := range result.Targets {
+		if target.Type != "Email" {
+			t.Errorf("type of %s = %s, want Email", target.Target, target.Type)
+		}
+
+		switch target.Target {
+		case "ops@example.com":
+			if target.Error != nil {
+				t.Fatalf("expect the send to %s succeeded, got %s", target.Target, target.Error)
+			}
+			// The queue id is in the response of the fake server to the end of the message.
+			if target.MessageID != "4F2K1" {
+				t.Errorf("message id = %q, want the queue id 4F2K1", target.MessageID)
+			}
+			if id := target.Extra["Message-Id"]; len(id) == 0 || id != headerOf(messages[0], "Message-Id") {
+				t.Errorf("Message-Id = %q, want the header %q", id, headerOf(messages[0], "Message-Id"))
+			}
+		case "result-throttled@example.com":
+			if _, ok := target.Error.(*notifier.ThrottledError); !ok {
+				t.Errorf("expect a throttled error, got %v", target.Error)
+			}
+			if len(target.MessageID) > 0 {
+				t.Errorf("expect no message id of the throttled address, got %s", target.MessageID)
+			}
+		default:
+			t.Fatalf("unexpected target %s", target.Target)
+		}
+	}
+}
+
+func TestNotifyEnqueuesTransientFailure(t *testing.T) {
+
+	s := newFakeSMTP(t, map[string]string{"MAIL FROM": "451 4.3.0 try again later"})
+
+	q, err := NewRetryQueue(nil, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetRetryQueue(q)
+	defer SetRetryQueue(nil)
+
+	r := nmconfig.NewEmail([]string{"ops@example.com"})
+	r.SetName("ops")
+	n := newTestNotifier(t, s, nil, r)
+
+	if errs := n.Notify(context.Background(), testData()); len(errs) == 0 {
+		t.Fatal("expect the send to fail")
+	}
+
+	entries, err := q.Drain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 email to retry, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Receiver != "ops" || e.Namespace != "default" || e.To != "ops@example.com" || e.Attempts != 1 {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	if e.Data.GroupLabels["alertname"] != "KubePodCrashLooping" {
+		t.Fatalf("unexpected data %+v", e.Data)
+	}
+}
+
+func TestNotifyDoesNotEnqueuePermanentFailure(t *testing.T) {
+
+	s := newFakeSMTP(t, nil)
+
+	q, _ := NewRetryQueue(nil, 10, time.Hour)
+	SetRetryQueue(q)
+	defer SetRetryQueue(nil)
+
+	r := nmconfig.NewEmail([]string{"ops@example.com"})
+	r.SetName("ops")
+	// The email fails to render, it will never be sent however many times it is retried.
+	n := newTestNotifier(t, s, nil, r)
+	n.templateName = `{{ template "undefined" . }}`
+
+	if errs := n.Notify(context.Background(), testData()); len(errs) == 0 {
+		t.Fatal("expect the send to fail")
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expect no email to retry, got %d", q.Len())
+	}
+}
+
+func TestNotifySkipsRejectedRecipients(t *testing.T) {
+
+	s := newFakeSMTP(t, map[string]string{"RCPT TO:<bad@example.com>": "550 5.1.1 no such user"})
+
+	r := nmconfig.NewEmail([]string{"ops@example.com", "bad@example.com"})
+	r.SetName("ops")
+	n := newTestNotifier(t, s, nil, r)
+
+	result, err := n.NotifyWithResult(context.Background(), testData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.receivedMessages()); got != 1 {
+		t.Fatalf("expect the email sent to the accepted recipient, got %d messages", got)
+	}
+	if len(result.Targets) != 2 {
+		t.Fatalf("expect 2 targets, got %d", len(result.Targets))
+	}
+
+	for _, target := range result.Targets {
+		switch target.Target {
+		case "ops@example.com":
+			if target.Error != nil {
+				t.Fatalf("expect the accepted recipient succeeded, got %s", target.Error)
+			}
+		case "bad@example.com":
+			if _, ok := target.Error.(*RecipientError); !ok {
+				t.Fatalf("expect a recipient error of the rejected recipient, got %v", target.Error)
+			}
+		default:
+			t.Fatalf("unexpected target %s", target.Target)
+		}
+	}
+}
+
+func TestNotifyAllRecipientsRejected(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		reply string
+		// Whether the email is retried later.
+		retry bool
+	}{
+		{"permanent", "550 5.1.1 no such user", false},
+		{"transient", "452 4.2.2 mailbox full", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, map[string]string{"RCPT TO": tt.reply})
+
+			q, _ := NewRetryQueue(nil, 10, time.Hour)
+			SetRetryQueue(q)
+			defer SetRetryQueue(nil)
+
+			r := nmconfig.NewEmail([]string{"ops@example.com", "bad@example.com"})
+			r.SetName("ops")
+			n := newTestNotifier(t, s, nil, r)
+
+			errs := n.Notify(context.Background(), testData())
+			if len(errs) == 0 {
+				t.Fatal("expect the send to fail")
+			}
+			for _, err := range errs {
+				if _, ok := err.(*RecipientError); !ok {
+					t.Fatalf("expect a recipient error, got %v", err)
+				}
+			}
+			if got := len(s.receivedMessages()); got != 0 {
+				t.Fatalf("expect no email sent, got %d messages", got)
+			}
+			if retried := q.Len() > 0; retried != tt.retry {
+				t.Fatalf("expect the email retried %v, got %v", tt.retry, retried)
+			}
+		})
+	}
+}
+
+func TestNotifyQuarantinesRejectedRecipient(t *testing.T) {
+
+	s := newFakeSMTP(t, map[string]string{"RCPT TO:<gone@example.com>": "550 5.1.1 no such user"})
+
+	r := nmconfig.NewEmail([]string{"ops@example.com", "gone@example.com"})
+	r.SetName("ops")
+	n := newTestNotifier(t, s, &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		TargetQuarantine: &v1alpha1.TargetQuarantine{Threshold: 2, Cooldown: time.Hour},
+	}}, r)
+	for _, address := range r.To {
+		defer notifier.GetQuarantine().Release(notifier.TargetKey("Email", address))
+	}
+
+	var result notifier.Result
+	for i := 0; i < 3; i++ {
+		var err error
+		if result, err = n.NotifyWithResult(context.Background(), testData()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The address is not sent to once it is quarantined, the other address is never quarantined.
+	if got := len(s.received("RCPT TO:<gone@example.com>")); got != 2 {
+		t.Fatalf("expect the quarantined address sent to 2 times, got %d", got)
+	}
+	if got := len(s.received("RCPT TO:<ops@example.com>")); got != 3 {
+		t.Fatalf("expect the healthy address sent to 3 times, got %d", got)
+	}
+	for _, target := range result.Targets {
+		_, quarantined := target.Error.(*notifier.QuarantinedError)
+		if quarantined != (target.Target == "gone@example.com") {
+			t.Fatalf("unexpected result of %s: %v", target.Target, target.Error)
+		}
+	}
+}
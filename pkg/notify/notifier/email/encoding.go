@@ -0,0 +1,521 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/notify/email"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	commoncfg "github.com/prometheus/common/config"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	Encoding7Bit            = "7bit"
+	Encoding8Bit            = "8bit"
+	EncodingQuotedPrintable = "quoted-printable"
+	EncodingBase64          = "base64"
+	// The encoding is chosen by the body and the server, `7bit` if the body is ASCII,
+	// `8bit` if the server advertises 8BITMIME, otherwise `quoted-printable`.
+	EncodingAuto = "auto"
+
+	// The maximum length of a line in the 7bit and 8bit bodies, excluding the CRLF, by RFC 5322.
+	maxLineLength = 998
+	// The length of the lines of the base64 body, by RFC 2045.
+	base64LineLength = 76
+)
+
+var queueIDRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)queued as ([^\s;,]+)`),
+	regexp.MustCompile(`(?i)\bid=([^\s;,]+)`),
+	regexp.MustCompile(`^(?:\d\.\d{1,3}\.\d{1,3} )?(\S+) Message accepted for delivery`),
+}
+
+// RecipientError means the server rejects the address, such as an address not existing or with a full mailbox.
+type RecipientError struct {
+	Address string
+	Err     error
+}
+
+func (e *RecipientError) Error() string {
+	return fmt.Sprintf("send RCPT command to %s: %s", e.Address, e.Err.Error())
+}
+
+// The error is permanent if the reply code is 5xx, such as `550 5.1.1 no such user`.
+func (e *RecipientError) permanent() bool {
+
+	if te, ok := e.Err.(*textproto.Error); ok {
+		return te.Code >= 500 && te.Code < 600
+	}
+
+	return false
+}
+
+// sender sends the emails like the email notifier of alertmanager, but the body is in the Content-Transfer-Encoding
+// of the receiver, while alertmanager always encodes it as quoted-printable.
+// It also sends the envelope-from of the receiver in the MAIL command, while alertmanager sends the from address.
+type sender struct {
+	conf         *config.EmailConfig
+	tmpl         *template.Template
+	encoding     string
+	envelopeFrom string
+	logger       log.Logger
+	hostname     string
+	// The addresses rejected by the server in the last email, the email is still sent to the other addresses.
+	rejected []*RecipientError
+	// The id of the last email in the queue of the server, it is empty if the server does not give it.
+	queueID string
+}
+
+// ValidEncoding returns true if the encoding is supported, empty means the default `auto`.
+func ValidEncoding(encoding string) bool {
+
+	switch strings.ToLower(encoding) {
+	case "", EncodingAuto, Encoding7Bit, Encoding8Bit, EncodingQuotedPrintable, EncodingBase64:
+		return true
+	default:
+		return false
+	}
+}
+
+func newSender(c *config.EmailConfig, t *template.Template, encoding string, l log.Logger) *sender {
+
+	if _, ok := c.Headers["Subject"]; !ok {
+		c.Headers["Subject"] = config.DefaultEmailSubject
+	}
+	if _, ok := c.Headers["To"]; !ok {
+		c.Headers["To"] = c.To
+	}
+	if _, ok := c.Headers["From"]; !ok {
+		c.Headers["From"] = c.From
+	}
+
+	h, err := os.Hostname()
+	if err != nil {
+		h = "localhost.localdomain"
+	}
+
+	encoding = strings.ToLower(encoding)
+	if len(encoding) == 0 {
+		encoding = EncodingAuto
+	}
+
+	return &sender{conf: c, tmpl: t, encoding: encoding, logger: l, hostname: h}
+}
+
+// The address sent in the MAIL command instead of the from address, it is the Return-Path of the emails.
+func (s *sender) withEnvelopeFrom(addr string) *sender {
+
+	s.envelopeFrom = addr
+	return s
+}
+
+// The error of the address if it is rejected by the server in the last email.
+func (s *sender) rejectedError(address string) error {
+
+	for _, e := range s.rejected {
+		if sameAddress(e.Address, address) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Notify sends the email, it returns true if the error is retryable, as the email notifier of alertmanager does.
+func (s *sender) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+
+	c, err := s.dial(ctx)
+	if err != nil {
+		return true, err
+	}
+
+	success := false
+	defer func() {
+		// The connection is closed by QUIT, or closed directly if QUIT fails, such as after a broken DATA.
+		if err := c.Quit(); err != nil {
+			_ = c.Close()
+			if success {
+				_ = level.Warn(s.logger).Log("msg", "EmailNotifier: close SMTP connection error", "error", err.Error())
+			}
+		}
+	}()
+
+	if err := s.hello(c); err != nil {
+		return true, err
+	}
+
+	var tmplErr error
+	data := notify.GetTemplateData(ctx, s.tmpl, as, s.logger)
+	tmpl := notify.TmplText(s.tmpl, data, &tmplErr)
+	from := tmpl(s.conf.From)
+	to := tmpl(s.conf.To)
+	if tmplErr != nil {
+		return false, fmt.Errorf("execute 'from' or 'to' template: %s", tmplErr.Error())
+	}
+
+	addrs, err := mail.ParseAddressList(from)
+	if err != nil {
+		return false, fmt.Errorf("parse 'from' addresses: %s", err.Error())
+	}
+	if len(addrs) != 1 {
+		return false, fmt.Errorf("must be exactly one 'from' address (got: %d)", len(addrs))
+	}
+	envelopeFrom := addrs[0].Address
+	if len(s.envelopeFrom) > 0 {
+		envelopeFrom = s.envelopeFrom
+	}
+	// net/smtp adds `BODY=8BITMIME` to the MAIL command if the server supports it.
+	if err := c.Mail(envelopeFrom); err != nil {
+		return true, fmt.Errorf("send MAIL command: %s", err.Error())
+	}
+
+	if addrs, err = mail.ParseAddressList(to); err != nil {
+		return false, fmt.Errorf("parse 'to' addresses: %s", err.Error())
+	}
+	// The addresses rejected are skipped, the email fails only if all the addresses are rejected.
+	// It is not retried if all of them are rejected permanently.
+	s.rejected = nil
+	permanent := true
+	for _, addr := range addrs {
+		if err := c.Rcpt(addr.Address); err != nil {
+			_ = level.Warn(s.logger).Log("msg", "EmailNotifier: address rejected", "address", addr.Address, "error", err.Error())
+			e := &RecipientError{Address: addr.Address, Err: err}
+			permanent = permanent && e.permanent()
+			s.rejected = append(s.rejected, e)
+		}
+	}
+	if len(s.rejected) > 0 && len(s.rejected) == len(addrs) {
+		return !permanent, s.rejected[0]
+	}
+
+	supports8Bit, _ := c.Extension("8BITMIME")
+	message, err := s.message(data, supports8Bit)
+	if err != nil {
+		return false, err
+	}
+
+	// The DATA command is sent on the text connection rather than by net/smtp, which drops the response
+	// to the end of the message, so the queue id given by the server can be reported.
+	id, err := c.Text.Cmd("DATA")
+	if err != nil {
+		return true, fmt.Errorf("send DATA command: %s", err.Error())
+	}
+	c.Text.StartResponse(id)
+	_, _, err = c.Text.ReadResponse(354)
+	c.Text.EndResponse(id)
+	if err != nil {
+		return true, fmt.Errorf("send DATA command: %s", err.Error())
+	}
+
+	w := c.Text.DotWriter()
+	if _, err := w.Write(message); err != nil {
+		_ = w.Close()
+		return true, fmt.Errorf("write message: %s", err.Error())
+	}
+
+	if err := w.Close(); err != nil {
+		return true, fmt.Errorf("close message: %s", err.Error())
+	}
+
+	_, msg, err := c.Text.ReadResponse(250)
+	if err != nil {
+		return true, fmt.Errorf("close message: %s", err.Error())
+	}
+	s.queueID = parseQueueID(msg)
+
+	success = true
+	return false, nil
+}
+
+// The queue id in the response to the end of the message, such as `2.0.0 Ok: queued as 4F2K1` of Postfix,
+// `OK id=1kT9Qm-0003Xy-2B` of Exim and `2.0.0 09FEPaQk012345 Message accepted for delivery` of Sendmail.
+// It returns an empty string if the response has no queue id.
+func parseQueueID(msg string) string {
+
+	for _, r := range queueIDRegexps {
+		if sub := r.FindStringSubmatch(msg); sub != nil {
+			return sub[1]
+		}
+	}
+
+	return ""
+}
+
+// Connect to the server with TLS if the port is 465, otherwise with plain TCP.
+func (s *sender) dial(ctx context.Context) (*smtp.Client, error) {
+
+	var conn net.Conn
+	if s.conf.Smarthost.Port == "465" {
+		tlsConfig, err := s.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		d := tls.Dialer{Config: tlsConfig}
+		if conn, err = d.DialContext(ctx, "tcp", s.conf.Smarthost.String()); err != nil {
+			return nil, fmt.Errorf("establish TLS connection to server: %s", err.Error())
+		}
+		stats.GetTLSRecorder().Record("Email", conn.(*tls.Conn).ConnectionState().DidResume)
+	} else {
+		d := net.Dialer{}
+		var err error
+		if conn, err = d.DialContext(ctx, "tcp", s.conf.Smarthost.String()); err != nil {
+			return nil, fmt.Errorf("establish connection to server: %s", err.Error())
+		}
+	}
+
+	c, err := smtp.NewClient(conn, s.conf.Smarthost.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("create SMTP client: %s", err.Error())
+	}
+
+	return c, nil
+}
+
+// Send the EHLO, then STARTTLS if TLS is required, and authenticate.
+func (s *sender) hello(c *smtp.Client) error {
+
+	if s.conf.Hello != "" {
+		if err := c.Hello(s.conf.Hello); err != nil {
+			return fmt.Errorf("send EHLO command: %s", err.Error())
+		}
+	}
+
+	if s.conf.RequireTLS != nil && *s.conf.RequireTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("'require_tls' is true but %q does not advertise the STARTTLS extension", s.conf.Smarthost)
+		}
+
+		tlsConfig, err := s.tlsConfig()
+		if err != nil {
+			return err
+		}
+
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("send STARTTLS command: %s", err.Error())
+		}
+		if state, ok := c.TLSConnectionState(); ok {
+			stats.GetTLSRecorder().Record("Email", state.DidResume)
+		}
+	}
+
+	if ok, mechs := c.Extension("AUTH"); ok {
+		auth, err := s.auth(mechs)
+		if err != nil {
+			return fmt.Errorf("find auth mechanism: %s", err.Error())
+		}
+		if auth != nil {
+			if err := c.Auth(auth); err != nil {
+				return fmt.Errorf("%T auth: %s", auth, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *sender) tlsConfig() (*tls.Config, error) {
+
+	tlsConfig, err := commoncfg.NewTLSConfig(&s.conf.TLSConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse TLS configuration: %s", err.Error())
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = s.conf.Smarthost.Host
+	}
+	// The sessions are resumed on the later connections to the server, as the http transports of the notifiers do.
+	tlsConfig.ClientSessionCache = notifier.TLSSessionCache()
+
+	return tlsConfig, nil
+}
+
+// The first mechanism advertised by the server which has the credential, the same as alertmanager.
+func (s *sender) auth(mechs string) (smtp.Auth, error) {
+
+	if s.conf.AuthUsername == "" {
+		return nil, nil
+	}
+
+	for _, mech := range strings.Split(mechs, " ") {
+		switch mech {
+		case "CRAM-MD5":
+			if s.conf.AuthSecret != "" {
+				return smtp.CRAMMD5Auth(s.conf.AuthUsername, string(s.conf.AuthSecret)), nil
+			}
+		case "PLAIN":
+			if s.conf.AuthPassword != "" {
+				return smtp.PlainAuth(s.conf.AuthIdentity, s.conf.AuthUsername, string(s.conf.AuthPassword), s.conf.Smarthost.Host), nil
+			}
+		case "LOGIN":
+			if s.conf.AuthPassword != "" {
+				return email.LoginAuth(s.conf.AuthUsername, string(s.conf.AuthPassword)), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no credential for the auth mechanisms: %s", mechs)
+}
+
+// Build the message with the headers and the multipart body, the parts are in the encoding of the receiver.
+func (s *sender) message(data *template.Data, supports8Bit bool) ([]byte, error) {
+
+	buf := &bytes.Buffer{}
+	for header, t := range s.conf.Headers {
+		value, err := s.tmpl.ExecuteTextString(t, data)
+		if err != nil {
+			return nil, fmt.Errorf("execute %q header template: %s", header, err.Error())
+		}
+		_, _ = fmt.Fprintf(buf, "%s: %s\r\n", header, mime.QEncoding.Encode("utf-8", value))
+	}
+
+	if _, ok := s.conf.Headers["Message-Id"]; !ok {
+		_, _ = fmt.Fprintf(buf, "Message-Id: %s\r\n", fmt.Sprintf("<%d@%s>", time.Now().UnixNano(), s.hostname))
+	}
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	_, _ = fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	_, _ = fmt.Fprintf(buf, "Content-Type: multipart/alternative;  boundary=%s\r\n", mw.Boundary())
+	_, _ = fmt.Fprintf(buf, "MIME-Version: 1.0\r\n\r\n")
+
+	if len(s.conf.Text) > 0 {
+		text, err := s.tmpl.ExecuteTextString(s.conf.Text, data)
+		if err != nil {
+			return nil, fmt.Errorf("execute text template: %s", err.Error())
+		}
+		if err := s.writePart(mw, "text/plain; charset=UTF-8", text, supports8Bit); err != nil {
+			return nil, fmt.Errorf("write text part: %s", err.Error())
+		}
+	}
+
+	// The html part is the preferred alternative, so it is the last one, by RFC 2046.
+	if len(s.conf.HTML) > 0 {
+		html, err := s.tmpl.ExecuteHTMLString(s.conf.HTML, data)
+		if err != nil {
+			return nil, fmt.Errorf("execute html template: %s", err.Error())
+		}
+		if err := s.writePart(mw, "text/html; charset=UTF-8", html, supports8Bit); err != nil {
+			return nil, fmt.Errorf("write html part: %s", err.Error())
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %s", err.Error())
+	}
+
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func (s *sender) writePart(mw *multipart.Writer, contentType, body string, supports8Bit bool) error {
+
+	encoding := partEncoding(s.encoding, body, supports8Bit)
+	if encoding != s.encoding && s.encoding != EncodingAuto {
+		_ = level.Warn(s.logger).Log("msg", "EmailNotifier: the body can not be sent in the encoding, use quoted-printable",
+			"encoding", s.encoding, "8bitmime", supports8Bit)
+	}
+
+	w, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Transfer-Encoding": {encoding},
+		"Content-Type":              {contentType},
+	})
+	if err != nil {
+		return err
+	}
+
+	return encodeBody(w, encoding, body)
+}
+
+// The encoding of the body, it is quoted-printable if the body can not be sent in the encoding.
+// The 7bit body must be ASCII, the 8bit body needs the 8BITMIME of the server, and both must have short lines.
+func partEncoding(encoding, body string, supports8Bit bool) string {
+
+	switch encoding {
+	case EncodingAuto:
+		if e := partEncoding(Encoding7Bit, body, supports8Bit); e == Encoding7Bit {
+			return e
+		}
+		return partEncoding(Encoding8Bit, body, supports8Bit)
+	case Encoding7Bit, Encoding8Bit:
+		for _, line := range strings.Split(body, "\n") {
+			if len(line) > maxLineLength {
+				return EncodingQuotedPrintable
+			}
+		}
+		if encoding == Encoding7Bit && !isASCII(body) {
+			return EncodingQuotedPrintable
+		}
+		if encoding == Encoding8Bit && (!supports8Bit || !utf8.ValidString(body)) {
+			return EncodingQuotedPrintable
+		}
+		return encoding
+	case EncodingBase64:
+		return encoding
+	default:
+		return EncodingQuotedPrintable
+	}
+}
+
+func encodeBody(w io.Writer, encoding, body string) error {
+
+	switch encoding {
+	case Encoding7Bit, Encoding8Bit:
+		// The line breaks are CRLF in the message.
+		body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+		_, err := io.WriteString(w, body)
+		return err
+	case EncodingBase64:
+		encoded := base64.StdEncoding.EncodeToString([]byte(body))
+		for len(encoded) > 0 {
+			n := base64LineLength
+			if n > len(encoded) {
+				n = len(encoded)
+			}
+			if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+				return err
+			}
+			encoded = encoded[n:]
+		}
+		return nil
+	default:
+		qw := quotedprintable.NewWriter(w)
+		if _, err := qw.Write([]byte(body)); err != nil {
+			return err
+		}
+		return qw.Close()
+	}
+}
+
+func isASCII(s string) bool {
+
+	for i := 0; i < len(s); i++ {
+		if s[i] > 127 {
+			return false
+		}
+	}
+
+	return true
+}
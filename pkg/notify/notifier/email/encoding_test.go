@@ -0,0 +1,348 @@
+package email
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	nmconfig "github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/config"
+	commoncfg "github.com/prometheus/common/config"
+)
+
+func TestPartEncoding(t *testing.T) {
+
+	long := strings.Repeat("a", maxLineLength+1)
+	tests := []struct {
+		name         string
+		encoding     string
+		body         string
+		supports8Bit bool
+		want         string
+	}{
+		{"auto ascii", EncodingAuto, "disk full", false, Encoding7Bit},
+		{"auto utf-8 with 8bitmime", EncodingAuto, "磁盘已满", true, Encoding8Bit},
+		{"auto utf-8 without 8bitmime", EncodingAuto, "磁盘已满", false, EncodingQuotedPrintable},
+		{"auto long line", EncodingAuto, long, true, EncodingQuotedPrintable},
+		{"8bit without 8bitmime", Encoding8Bit, "磁盘已满", false, EncodingQuotedPrintable},
+		{"7bit utf-8", Encoding7Bit, "磁盘已满", true, EncodingQuotedPrintable},
+		{"base64", EncodingBase64, "磁盘已满", false, EncodingBase64},
+		{"default", "", "disk full", true, EncodingQuotedPrintable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := partEncoding(tt.encoding, tt.body, tt.supports8Bit); got != tt.want {
+				t.Errorf("partEncoding() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyDetects8BitMIME(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		extensions []string
+		want       string
+		mail       string
+	}{
+		{"advertised", []string{"8BITMIME"}, "Content-Transfer-Encoding: 8bit", "MAIL FROM:<alerts@example.com> BODY=8BITMIME"},
+		{"not advertised", nil, "Content-Transfer-Encoding: quoted-printable", "MAIL FROM:<alerts@example.com>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, nil, tt.extensions...)
+			n := newTestNotifier(t, s, nil, nmconfig.NewEmail([]string{"ops@example.com"}))
+
+			data := testData()
+			data.Alerts[0].Labels["alertname"] = "磁盘已满"
+			if errs := n.Notify(context.Background(), data); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			messages := s.receivedMessages()
+			if len(messages) != 1 {
+				t.Fatalf("expect 1 message, got %d", len(messages))
+			}
+			if !strings.Contains(messages[0], tt.want) {
+				t.Errorf("expect %q in the message:\n%s", tt.want, messages[0])
+			}
+			if tt.want == "Content-Transfer-Encoding: 8bit" && !strings.Contains(messages[0], "磁盘已满") {
+				t.Errorf("expect the body in UTF-8:\n%s", messages[0])
+			}
+			if mail := s.received("MAIL FROM"); len(mail) != 1 || mail[0] != tt.mail {
+				t.Errorf("expect %q, got %v", tt.mail, mail)
+			}
+		})
+	}
+}
+
+func TestNotifyLocalName(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		hello string
+		want  string
+	}{
+		{"default", "", "EHLO localhost"},
+		{"configured", "mail.example.com", "EHLO mail.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, nil)
+			n := newTestNotifier(t, s, nil, nmconfig.NewEmail([]string{"ops@example.com"}))
+			for _, e := range n.email {
+				e.EmailConfig.Hello = tt.hello
+			}
+
+			if errs := n.Notify(context.Background(), testData()); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			if ehlo := s.received("EHLO"); len(ehlo) != 1 || ehlo[0] != tt.want {
+				t.Errorf("expect %q, got %v", tt.want, ehlo)
+			}
+		})
+	}
+}
+
+func TestParseQueueID(t *testing.T) {
+
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"postfix", "2.0.0 Ok: queued as 4F2K1", "4F2K1"},
+		{"exim", "OK id=1kT9Qm-0003Xy-2B", "1kT9Qm-0003Xy-2B"},
+		{"sendmail", "2.0.0 09FEPaQk012345 Message accepted for delivery", "09FEPaQk012345"},
+		{"unknown", "2.0.0 OK", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseQueueID(tt.msg); got != tt.want {
+				t.Errorf("parseQueueID() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidEncoding(t *testing.T) {
+
+	tests := []struct {
+		encoding string
+		want     bool
+	}{
+		{"", true},
+		{"auto", true},
+		{"7bit", true},
+		{"8bit", true},
+		{"quoted-printable", true},
+		{"Base64", true},
+		{"binary", false},
+		{"utf-8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			if got := ValidEncoding(tt.encoding); got != tt.want {
+				t.Errorf("ValidEncoding(%q) = %v, want %v", tt.encoding, got, tt.want)
+			}
+		})
+	}
+}
+
+// The Content-Transfer-Encoding and the decoded content of the first part of the multipart message.
+func decodeFirstPart(t *testing.T, message string) (string, string) {
+
+	msg, err := mail.ReadMessage(strings.NewReader(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part, err := multipart.NewReader(msg.Body, params["boundary"]).NextRawPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoding := part.Header.Get("Content-Transfer-Encoding")
+	switch encoding {
+	case EncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\r", "", "\n", "").Replace(string(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = b
+	case EncodingQuotedPrintable:
+		p, err := multipart.NewReader(strings.NewReader("--b\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n"+
+			string(body)+"\r\n--b--\r\n"), "b").NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, err = ioutil.ReadAll(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return encoding, string(body)
+}
+
+func TestNotifyContentTransferEncoding(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		encoding string
+		want     string
+	}{
+		{"default", "", Encoding8Bit},
+		{"auto", "auto", Encoding8Bit},
+		{"8bit", "8bit", Encoding8Bit},
+		{"quoted-printable", "quoted-printable", EncodingQuotedPrintable},
+		{"base64", "BASE64", EncodingBase64},
+		{"7bit with utf-8", "7bit", EncodingQuotedPrintable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeSMTP(t, nil, "8BITMIME")
+			r := nmconfig.NewEmail([]string{"ops@example.com"})
+			r.ContentTransferEncoding = tt.encoding
+			n := newTestNotifier(t, s, nil, r)
+
+			data := testData()
+			data.Alerts[0].Labels["alertname"] = "磁盘已满"
+			if errs := n.Notify(context.Background(), data); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			messages := s.receivedMessages()
+			if len(messages) != 1 {
+				t.Fatalf("expect 1 message, got %d", len(messages))
+			}
+			encoding, body := decodeFirstPart(t, messages[0])
+			if encoding != tt.want {
+				t.Errorf("expect the body in %s, got %s", tt.want, encoding)
+			}
+			if !strings.Contains(body, "<p>磁盘已满</p>") {
+				t.Errorf("expect the body decoded to the content, got %q", body)
+			}
+		})
+	}
+}
+
+// A TLS config of the server with a self-signed certificate of 127.0.0.1.
+func newServerTLSConfig(t *testing.T) *tls.Config {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fake"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+func TestTLSSessionResumed(t *testing.T) {
+
+	tests := []struct {
+		name string
+		// The server is listening on the port 465 with TLS, otherwise it upgrades the connection with STARTTLS.
+		smtps bool
+	}{
+		{"smtps", true},
+		{"starttls", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig := newServerTLSConfig(t)
+
+			var s *fakeSMTP
+			if tt.smtps {
+				ln, err := tls.Listen("tcp", "127.0.0.1:465", tlsConfig)
+				if err != nil {
+					t.Skipf("listen on the SMTPS port: %s", err.Error())
+				}
+				s = startFakeSMTP(t, ln, &fakeSMTP{})
+			} else {
+				ln, err := net.Listen("tcp", "127.0.0.1:0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				s = startFakeSMTP(t, ln, &fakeSMTP{extensions: []string{"STARTTLS"}, tlsConfig: tlsConfig})
+			}
+
+			requireTLS := !tt.smtps
+			sender := newSender(&config.EmailConfig{
+				Smarthost:  config.HostPort{Host: s.host, Port: s.port},
+				Hello:      "localhost",
+				RequireTLS: &requireTLS,
+				TLSConfig:  commoncfg.TLSConfig{InsecureSkipVerify: true},
+				Headers:    map[string]string{},
+			}, nil, "", log.NewNopLogger())
+
+			connect := func() {
+				c, err := sender.dial(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer c.Close()
+				if err := sender.hello(c); err != nil {
+					t.Fatal(err)
+				}
+				// The session ticket is received after the handshake, it is read with the reply of NOOP.
+				if err := c.Noop(); err != nil {
+					t.Fatal(err)
+				}
+				_ = c.Quit()
+			}
+
+			// The session of the first connection is resumed by the second one.
+			connect()
+			before := stats.GetTLSRecorder().Summaries()["Email"]
+			connect()
+			after := stats.GetTLSRecorder().Summaries()["Email"]
+			if after.ResumedSessions != before.ResumedSessions+1 {
+				t.Fatalf("expect the session resumed, got %+v after %+v", after, before)
+			}
+		})
+	}
+}
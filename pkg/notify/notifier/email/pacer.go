@@ -0,0 +1,72 @@
+package email
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var pacer *Pacer
+
+// Pacer spaces out the sends to the same smart host, so that the emails are sent smoothly rather than bursted.
+// It is different from the rate limiting, there is no quota, only the minimum interval between sends.
+type Pacer struct {
+	// The slot reserved for the last send of each smart host.
+	last  map[string]slot
+	mutex sync.Mutex
+}
+
+type slot struct {
+	at       time.Time
+	interval time.Duration
+}
+
+func init() {
+	pacer = &Pacer{
+		last: make(map[string]slot),
+	}
+}
+
+func GetPacer() *Pacer {
+	return pacer
+}
+
+// Wait reserves a send slot for the `key` which is at least `interval` after the previous one, and waits until the slot arrives.
+// It returns the error of the context if the context is done before the slot.
+func (p *Pacer) Wait(ctx context.Context, key string, interval time.Duration) error {
+
+	if interval <= 0 {
+		return nil
+	}
+
+	p.mutex.Lock()
+	now := time.Now()
+	next := now
+	if last, ok := p.last[key]; ok && last.at.Add(interval).After(now) {
+		next = last.at.Add(interval)
+	}
+
+	// Remove the slots which have passed long enough, they have no effect on the next send.
+	for k, s := range p.last {
+		if now.Sub(s.at) > s.interval {
+			delete(p.last, k)
+		}
+	}
+	p.last[key] = slot{at: next, interval: interval}
+	p.mutex.Unlock()
+
+	wait := next.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
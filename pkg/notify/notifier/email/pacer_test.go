@@ -0,0 +1,59 @@
+package email
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPacerWait(t *testing.T) {
+
+	const interval = 50 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		keys     []string
+		interval time.Duration
+		// The minimum time used by all the sends.
+		want time.Duration
+	}{
+		{"first send is not delayed", []string{"a"}, interval, 0},
+		{"sends to the same host are spaced", []string{"a", "a", "a"}, interval, 2 * interval},
+		{"sends to different hosts are not spaced", []string{"a", "b", "c"}, interval, 0},
+		{"no interval", []string{"a", "a", "a"}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pacer{last: make(map[string]slot)}
+			start := time.Now()
+			for _, key := range tt.keys {
+				if err := p.Wait(context.Background(), key, tt.interval); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			used := time.Since(start)
+			if used < tt.want {
+				t.Fatalf("sends used %s, want at least %s", used, tt.want)
+			}
+			if tt.want == 0 && used >= interval {
+				t.Fatalf("sends used %s, want no wait", used)
+			}
+		})
+	}
+}
+
+func TestPacerWaitCanceled(t *testing.T) {
+
+	p := &Pacer{last: make(map[string]slot)}
+	if err := p.Wait(context.Background(), "a", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Wait(ctx, "a", time.Hour); err != context.DeadlineExceeded {
+		t.Fatalf("expect the wait canceled, got %v", err)
+	}
+}
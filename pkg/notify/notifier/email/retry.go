@@ -0,0 +1,131 @@
+package email
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	nmconfig "github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"strings"
+	"time"
+)
+
+var retryQueue *RetryQueue
+
+type retryEntryKey struct{}
+
+// SetRetryQueue sets the queue of the emails failed transiently, the failed emails are not retried if it is nil.
+func SetRetryQueue(q *RetryQueue) {
+	retryQueue = q
+}
+
+func GetRetryQueue() *RetryQueue {
+	return retryQueue
+}
+
+// Enqueue the email failed transiently, the email retried keeps its id and the time it is enqueued first,
+// so it is discarded when it is older than the max age.
+func (n *Notifier) enqueueRetry(ctx context.Context, e *nmconfig.Email, to string, data template.Data) {
+
+	q := GetRetryQueue()
+	if q == nil || len(e.GetName()) == 0 {
+		return
+	}
+
+	entry := &RetryEntry{
+		ID:        newMessageID(),
+		Namespace: e.GetNamespace(),
+		Receiver:  e.GetName(),
+		To:        to,
+		Data:      data,
+	}
+	if retried, ok := ctx.Value(retryEntryKey{}).(*RetryEntry); ok {
+		entry.ID = retried.ID
+		entry.Enqueued = retried.Enqueued
+		entry.Attempts = retried.Attempts
+	}
+	entry.Attempts++
+
+	if err := q.Enqueue(entry); err != nil {
+		_ = level.Error(n.logger).Log("msg", "EmailNotifier: enqueue the email to retry error", "to", to, "error", err.Error())
+		return
+	}
+	stats.GetCounters().Add("email_retry_enqueued", 1)
+}
+
+// RunRetryQueue retries the emails in the queue every interval until the context is done.
+// The queue is drained at once when starting, so the emails persisted before the restart are resumed.
+func RunRetryQueue(ctx context.Context, logger log.Logger, notifierCfg *nmconfig.Config, interval time.Duration) {
+
+	if GetRetryQueue() == nil || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		retryEmails(ctx, logger, notifierCfg)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send the emails in the queue again with the current config of their receivers, the ones failing transiently
+// are enqueued again by the notifier. The emails whose receiver is not found are kept, as the receivers
+// may not be synced yet when starting.
+func retryEmails(ctx context.Context, logger log.Logger, notifierCfg *nmconfig.Config) {
+
+	q := GetRetryQueue()
+	entries, err := q.Drain()
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "EmailNotifier: drain the retry queue error", "error", err.Error())
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			_ = q.Enqueue(entry)
+			continue
+		}
+
+		r := findReceiver(notifierCfg, entry.Namespace, entry.Receiver)
+		if r == nil {
+			_ = level.Debug(logger).Log("msg", "EmailNotifier: receiver of the email to retry not found", "receiver", entry.Receiver, "namespace", entry.Namespace)
+			if err := q.Enqueue(entry); err != nil {
+				_ = level.Error(logger).Log("msg", "EmailNotifier: enqueue the email to retry error", "to", entry.To, "error", err.Error())
+			}
+			continue
+		}
+
+		// Only the addresses failed are retried.
+		receiver := *r
+		receiver.To = strings.Split(entry.To, ",")
+		n, ok := NewEmailNotifier(logger, []nmconfig.Receiver{&receiver}, notifierCfg).(*Notifier)
+		if !ok || n == nil {
+			_ = q.Enqueue(entry)
+			continue
+		}
+
+		result, err := n.NotifyWithResult(context.WithValue(ctx, retryEntryKey{}, entry), entry.Data)
+		if err == nil && len(result.Errors()) == 0 {
+			stats.GetCounters().Add("email_retry_succeeded", 1)
+			_ = level.Info(logger).Log("msg", "EmailNotifier: email retried", "id", entry.ID, "to", entry.To, "attempts", entry.Attempts+1)
+		}
+	}
+}
+
+func findReceiver(notifierCfg *nmconfig.Config, namespace, name string) *nmconfig.Email {
+
+	for _, r := range notifierCfg.RcvsFromName(name) {
+		if e, ok := r.(*nmconfig.Email); ok && e != nil && e.GetNamespace() == namespace {
+			return e
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package email
+
+import (
+	json "github.com/json-iterator/go"
+	"github.com/prometheus/alertmanager/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRetryQueueSize   = 1000
+	DefaultRetryQueueMaxAge = time.Hour
+)
+
+// RetryEntry is an email waiting to be retried.
+type RetryEntry struct {
+	ID        string        `json:"id"`
+	Namespace string        `json:"namespace,omitempty"`
+	Receiver  string        `json:"receiver,omitempty"`
+	To        string        `json:"to"`
+	Data      template.Data `json:"data"`
+	Enqueued  time.Time     `json:"enqueued"`
+	Attempts  int           `json:"attempts"`
+}
+
+// RetryStore persists the entries of the retry queue, so they survive the restarts.
+type RetryStore interface {
+	// Save replaces the persisted entries with `entries`.
+	Save(entries []*RetryEntry) error
+	Load() ([]*RetryEntry, error)
+}
+
+// RetryQueue holds the emails which failed transiently. The oldest entry is evicted when the queue is full,
+// and the entries older than the max age are discarded, including the ones loaded from the store.
+type RetryQueue struct {
+	entries []*RetryEntry
+	store   RetryStore
+	maxSize int
+	maxAge  time.Duration
+	mutex   sync.Mutex
+}
+
+// NewRetryQueue creates the queue with the entries in the store, the store is in memory if it is nil.
+func NewRetryQueue(store RetryStore, maxSize int, maxAge time.Duration) (*RetryQueue, error) {
+
+	if store == nil {
+		store = &memoryRetryStore{}
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultRetryQueueSize
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultRetryQueueMaxAge
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	q := &RetryQueue{
+		entries: entries,
+		store:   store,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+	}
+
+	sort.SliceStable(q.entries, func(i, j int) bool {
+		return q.entries[i].Enqueued.Before(q.entries[j].Enqueued)
+	})
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.evict(time.Now()) {
+		if err := q.store.Save(q.entries); err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+// Enqueue adds the entry to the queue and persists the queue.
+func (q *RetryQueue) Enqueue(e *RetryEntry) error {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if e.Enqueued.IsZero() {
+		e.Enqueued = time.Now()
+	}
+
+	q.entries = append(q.entries, e)
+	q.evict(time.Now())
+
+	return q.store.Save(q.entries)
+}
+
+// Drain removes all the entries which are not expired from the queue and returns them in the order they are enqueued.
+// The entries failing again should be enqueued again.
+func (q *RetryQueue) Drain() ([]*RetryEntry, error) {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.evict(time.Now())
+	entries := q.entries
+	q.entries = nil
+
+	return entries, q.store.Save(q.entries)
+}
+
+func (q *RetryQueue) Len() int {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.entries)
+}
+
+// Remove the expired entries and the oldest entries over the size, it returns true if any entry is removed.
+func (q *RetryQueue) evict(now time.Time) bool {
+
+	var entries []*RetryEntry
+	for _, e := range q.entries {
+		if now.Sub(e.Enqueued) < q.maxAge {
+			entries = append(entries, e)
+		}
+	}
+
+	if len(entries) > q.maxSize {
+		entries = entries[len(entries)-q.maxSize:]
+	}
+
+	evicted := len(entries) != len(q.entries)
+	q.entries = entries
+	return evicted
+}
+
+// The default store, the entries are lost when restarting.
+type memoryRetryStore struct{}
+
+func (s *memoryRetryStore) Save(_ []*RetryEntry) error {
+	return nil
+}
+
+func (s *memoryRetryStore) Load() ([]*RetryEntry, error) {
+	return nil, nil
+}
+
+// FileRetryStore persists the entries in a json file, such as a file in a persistent volume.
+// The file is replaced atomically, so it is not corrupted if the process is killed while saving.
+type FileRetryStore struct {
+	Path string
+}
+
+func NewFileRetryStore(path string) *FileRetryStore {
+	return &FileRetryStore{Path: path}
+}
+
+func (s *FileRetryStore) Save(entries []*RetryEntry) error {
+
+	if entries == nil {
+		entries = []*RetryEntry{}
+	}
+
+	bs, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// Load returns nothing if the file does not exist, such as at the first start.
+func (s *FileRetryStore) Load() ([]*RetryEntry, error) {
+
+	bs, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []*RetryEntry
+	if err := json.Unmarshal(bs, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
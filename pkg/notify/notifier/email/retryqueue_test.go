@@ -0,0 +1,96 @@
+package email
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestRetryQueuePersistReloadDrain(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "retry.json")
+
+	q, err := NewRetryQueue(NewFileRetryStore(path), 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := q.Enqueue(&RetryEntry{ID: id, To: id + "@example.com", Data: template.Data{Status: "firing"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new queue of the same file, such as after restarting.
+	q, err = NewRetryQueue(NewFileRetryStore(path), 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 2 {
+		t.Fatalf("expect 2 entries reloaded, got %d", q.Len())
+	}
+
+	entries, err := q.Drain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "a" || entries[1].ID != "b" || entries[0].Data.Status != "firing" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	// The drained entries are removed from the file.
+	q, err = NewRetryQueue(NewFileRetryStore(path), 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expect no entry after drained, got %d", q.Len())
+	}
+}
+
+func TestRetryQueueMaxAge(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "retry.json")
+	store := NewFileRetryStore(path)
+	now := time.Now()
+	if err := store.Save([]*RetryEntry{
+		{ID: "expired", Enqueued: now.Add(-2 * time.Hour)},
+		{ID: "fresh", Enqueued: now.Add(-time.Minute)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The expired entries are discarded when loaded.
+	q, err := NewRetryQueue(store, 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expect 1 entry after loaded, got %d", q.Len())
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != "fresh" {
+		t.Fatalf("expect the expired entry removed from the file, got %+v", entries)
+	}
+}
+
+func TestRetryQueueMaxSize(t *testing.T) {
+
+	q, err := NewRetryQueue(nil, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		_ = q.Enqueue(&RetryEntry{ID: id})
+	}
+
+	entries, _ := q.Drain()
+	if len(entries) != 2 || entries[0].ID != "b" || entries[1].ID != "c" {
+		t.Fatalf("expect the oldest entry evicted, got %+v", entries)
+	}
+}
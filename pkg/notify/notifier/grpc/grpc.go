@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/async"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSendTimeout = time.Second * 5
+	DefaultMethod      = "/notification.NotificationService/Notify"
+	// The maximum number of the retries of the retryable errors, the retries stop when the timeout is reached.
+	MaxRetries = 3
+	// The interval of retrying, it is doubled after every retry.
+	RetryInterval = time.Millisecond * 500
+)
+
+var (
+	// The connections are reused across notifications, the key is the md5 of the config.
+	conns = make(map[string]*ggrpc.ClientConn)
+	mutex sync.Mutex
+)
+
+// GRPCError is the error returned by the gRPC server, `Retryable` means the error is transient,
+// such as the server is unavailable or overloaded, and the request may succeed later.
+type GRPCError struct {
+	Code      codes.Code
+	Message   string
+	Retryable bool
+}
+
+func (e *GRPCError) Error() string {
+	return fmt.Sprintf("grpc error, code: %s, message: %s, retryable: %t", e.Code, e.Message, e.Retryable)
+}
+
+type Notifier struct {
+	notifierCfg *config.Config
+	grpc        []*config.Grpc
+	timeout     time.Duration
+	logger      log.Logger
+}
+
+func NewGrpcNotifier(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier {
+
+	n := &Notifier{
+		notifierCfg: notifierCfg,
+		timeout:     DefaultSendTimeout,
+		logger:      logger,
+	}
+
+	opts := notifierCfg.ReceiverOpts
+	if opts != nil && opts.Grpc != nil && opts.Grpc.NotificationTimeout != nil {
+		n.timeout = time.Second * time.Duration(*opts.Grpc.NotificationTimeout)
+	}
+
+	for _, r := range receivers {
+		receiver, ok := r.(*config.Grpc)
+		if !ok || receiver == nil {
+			continue
+		}
+
+		if receiver.GrpcConfig == nil {
+			notifier.ReportConfigError(logger, notifierCfg, "GRPC", receiver, fmt.Errorf("no config is found"))
+			continue
+		}
+
+		n.grpc = append(n.grpc, receiver)
+	}
+
+	return n
+}
+
+func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
+
+	send := func(g *config.Grpc) (err error) {
+
+		start := time.Now()
+		defer func() {
+			_ = level.Debug(n.logger).Log("msg", "GrpcNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("GRPC", time.Since(start), err)
+		}()
+
+		conn, err := n.getConn(g)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "GrpcNotifier: get connection error", "error", err.Error())
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+
+		if g.GrpcConfig.Token != nil {
+			token, err := n.notifierCfg.GetSecretData(g.GetNamespace(), g.GrpcConfig.Token)
+			if err != nil {
+				_ = level.Error(n.logger).Log("msg", "GrpcNotifier: get token error", "error", err.Error())
+				return err
+			}
+
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+
+		method := g.GrpcConfig.Method
+		if len(method) == 0 {
+			method = DefaultMethod
+		}
+
+		if err := invoke(ctx, conn, method, newNotifyRequest(data)); err != nil {
+			_ = level.Error(n.logger).Log("msg", "GrpcNotifier: send notification error", "target", g.GrpcConfig.Target, "error", err.Error())
+			return err
+		}
+
+		_ = level.Debug(n.logger).Log("msg", "GrpcNotifier: send message", "to", g.GrpcConfig.Target, "alerts", len(data.Alerts))
+		return nil
+	}
+
+	group := async.NewGroup(ctx)
+	for _, grpc := range n.grpc {
+		g := grpc
+		group.Add(func(stopCh chan interface{}) {
+			stopCh <- send(g)
+		})
+	}
+
+	return group.Wait()
+}
+
+// Call the method, the retryable errors are retried with backoff until the retries or the context are exhausted.
+func invoke(ctx context.Context, conn *ggrpc.ClientConn, method string, req *NotifyRequest) error {
+
+	interval := RetryInterval
+	for i := 0; ; i++ {
+		err := conn.Invoke(ctx, method, req, &NotifyResponse{})
+		if err == nil {
+			return nil
+		}
+
+		e := classify(err)
+		if !e.Retryable || i >= MaxRetries {
+			return e
+		}
+
+		select {
+		case <-ctx.Done():
+			return e
+		case <-time.After(interval):
+			interval *= 2
+		}
+	}
+}
+
+func classify(err error) *GRPCError {
+
+	s := status.Convert(err)
+	e := &GRPCError{
+		Code:    s.Code(),
+		Message: s.Message(),
+	}
+
+	switch s.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		e.Retryable = true
+	}
+
+	return e
+}
+
+// Get the connection of the receiver, the connection will be reused if the config is not changed.
+// The connection is established in the background, and it reconnects automatically when broken.
+func (n *Notifier) getConn(g *config.Grpc) (*ggrpc.ClientConn, error) {
+
+	key, err := notifier.Md5key(g.GrpcConfig)
+	if err != nil {
+		return nil, err
+	}
+	key = fmt.Sprintf("%s/%s", g.GetNamespace(), key)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if conn, ok := conns[key]; ok {
+		return conn, nil
+	}
+
+	opt := ggrpc.WithInsecure()
+	if g.GrpcConfig.TLSConfig != nil {
+		tlsConfig, err := notifier.NewTLSConfig(n.notifierCfg, g.GetNamespace(), g.GrpcConfig.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		opt = ggrpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	conn, err := ggrpc.Dial(g.GrpcConfig.Target, opt)
+	if err != nil {
+		return nil, err
+	}
+	conns[key] = conn
+
+	return conn, nil
+}
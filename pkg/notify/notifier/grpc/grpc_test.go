@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServer is a gRPC server implementing the notification service, it fails the first calls with the codes.
+type fakeServer struct {
+	target string
+
+	mutex    sync.Mutex
+	failures []codes.Code
+	requests []*NotifyRequest
+}
+
+func newFakeServer(t *testing.T, failures ...codes.Code) *fakeServer {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeServer{target: ln.Addr().String(), failures: failures}
+	srv := ggrpc.NewServer()
+	srv.RegisterService(&ggrpc.ServiceDesc{
+		ServiceName: "notification.NotificationService",
+		HandlerType: (*interface{})(nil),
+		Methods: []ggrpc.MethodDesc{{
+			MethodName: "Notify",
+			Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ ggrpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &NotifyRequest{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				return s.notify(req)
+			},
+		}},
+	}, s)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	return s
+}
+
+func (s *fakeServer) notify(req *NotifyRequest) (*NotifyResponse, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, req)
+	if len(s.failures) > 0 {
+		code := s.failures[0]
+		s.failures = s.failures[1:]
+		return nil, status.Error(code, "fake failure")
+	}
+
+	return &NotifyResponse{}, nil
+}
+
+func (s *fakeServer) received() []*NotifyRequest {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]*NotifyRequest(nil), s.requests...)
+}
+
+func testData() template.Data {
+
+	return template.Data{
+		Receiver:    "prometheus",
+		Status:      "firing",
+		GroupLabels: template.KV{"alertname": "KubePodCrashLooping"},
+		Alerts: template.Alerts{{
+			Status:      "firing",
+			Labels:      template.KV{"alertname": "KubePodCrashLooping", "namespace": "default"},
+			Annotations: template.KV{"message": "pod is crash looping"},
+			StartsAt:    time.Unix(1600000000, 0),
+			Fingerprint: "f1",
+		}},
+	}
+}
+
+// A receiver sending to the target without TLS.
+func newTestReceiver(target string) *config.Grpc {
+
+	r := config.NewGrpcReceiver().(*config.Grpc)
+	r.SetNamespace("default")
+	r.GrpcConfig = &config.GrpcConfig{Target: target}
+	return r
+}
+
+func TestNotify(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		failures []codes.Code
+		// The number of the calls expected, including the retries.
+		wantCalls int
+		wantCode  codes.Code
+		wantRetry bool
+	}{
+		{name: "sent", wantCalls: 1, wantCode: codes.OK},
+		{name: "transient error retried", failures: []codes.Code{codes.Unavailable}, wantCalls: 2, wantCode: codes.OK},
+		{name: "permanent error not retried", failures: []codes.Code{codes.InvalidArgument}, wantCalls: 1, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFakeServer(t, tt.failures...)
+			r := newTestReceiver(s.target)
+			n := NewGrpcNotifier(log.NewNopLogger(), []config.Receiver{r}, &config.Config{})
+
+			errs := n.Notify(context.Background(), testData())
+			if tt.wantCode == codes.OK && len(errs) != 0 {
+				t.Fatal(errs)
+			}
+			if tt.wantCode != codes.OK {
+				if len(errs) != 1 {
+					t.Fatalf("expect 1 error, got %v", errs)
+				}
+				e, ok := errs[0].(*GRPCError)
+				if !ok || e.Code != tt.wantCode || e.Retryable != tt.wantRetry {
+					t.Fatalf("unexpected error %v", errs[0])
+				}
+			}
+
+			reqs := s.received()
+			if len(reqs) != tt.wantCalls {
+				t.Fatalf("expect %d calls, got %d", tt.wantCalls, len(reqs))
+			}
+			req := reqs[len(reqs)-1]
+			if req.Receiver != "prometheus" || req.GroupLabels["alertname"] != "KubePodCrashLooping" || len(req.Alerts) != 1 {
+				t.Fatalf("unexpected request %v", req)
+			}
+			if a := req.Alerts[0]; a.Annotations["message"] != "pod is crash looping" || a.StartsAt != 1600000000000 || a.Fingerprint != "f1" {
+				t.Fatalf("unexpected alert %v", a)
+			}
+		})
+	}
+}
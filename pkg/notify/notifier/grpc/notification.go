@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/alertmanager/template"
+	"time"
+)
+
+// The messages defined in notification.proto, they are written by hand so that no code generator is needed.
+// Keep them consistent with notification.proto.
+
+type NotifyRequest struct {
+	Receiver          string            `protobuf:"bytes,1,opt,name=receiver,proto3" json:"receiver,omitempty"`
+	Status            string            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	GroupLabels       map[string]string `protobuf:"bytes,3,rep,name=group_labels,json=groupLabels,proto3" json:"group_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CommonLabels      map[string]string `protobuf:"bytes,4,rep,name=common_labels,json=commonLabels,proto3" json:"common_labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CommonAnnotations map[string]string `protobuf:"bytes,5,rep,name=common_annotations,json=commonAnnotations,proto3" json:"common_annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ExternalURL       string            `protobuf:"bytes,6,opt,name=external_url,json=externalUrl,proto3" json:"external_url,omitempty"`
+	Alerts            []*Alert          `protobuf:"bytes,7,rep,name=alerts,proto3" json:"alerts,omitempty"`
+}
+
+func (m *NotifyRequest) Reset()         { *m = NotifyRequest{} }
+func (m *NotifyRequest) String() string { return proto.CompactTextString(m) }
+func (*NotifyRequest) ProtoMessage()    {}
+
+type Alert struct {
+	Status       string            `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Labels       map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations  map[string]string `protobuf:"bytes,3,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StartsAt     int64             `protobuf:"varint,4,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt       int64             `protobuf:"varint,5,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	GeneratorURL string            `protobuf:"bytes,6,opt,name=generator_url,json=generatorUrl,proto3" json:"generator_url,omitempty"`
+	Fingerprint  string            `protobuf:"bytes,7,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+}
+
+func (m *Alert) Reset()         { *m = Alert{} }
+func (m *Alert) String() string { return proto.CompactTextString(m) }
+func (*Alert) ProtoMessage()    {}
+
+type NotifyResponse struct {
+}
+
+func (m *NotifyResponse) Reset()         { *m = NotifyResponse{} }
+func (m *NotifyResponse) String() string { return proto.CompactTextString(m) }
+func (*NotifyResponse) ProtoMessage()    {}
+
+func newNotifyRequest(data template.Data) *NotifyRequest {
+
+	req := &NotifyRequest{
+		Receiver:          data.Receiver,
+		Status:            data.Status,
+		GroupLabels:       data.GroupLabels,
+		CommonLabels:      data.CommonLabels,
+		CommonAnnotations: data.CommonAnnotations,
+		ExternalURL:       data.ExternalURL,
+	}
+
+	for _, a := range data.Alerts {
+		req.Alerts = append(req.Alerts, &Alert{
+			Status:       a.Status,
+			Labels:       a.Labels,
+			Annotations:  a.Annotations,
+			StartsAt:     unixMilli(a.StartsAt),
+			EndsAt:       unixMilli(a.EndsAt),
+			GeneratorURL: a.GeneratorURL,
+			Fingerprint:  a.Fingerprint,
+		})
+	}
+
+	return req
+}
+
+func unixMilli(t time.Time) int64 {
+
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano() / int64(time.Millisecond)
+}
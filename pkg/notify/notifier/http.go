@@ -0,0 +1,314 @@
+package notifier
+
+import (
+	"container/list"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/mwitkow/go-conntrack"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTLSSessionCacheSize = 64
+	TLSHandshakeTimeout        = time.Second * 10
+)
+
+var (
+	// The TLS sessions are shared by all transports, so that the connections to the same endpoint can resume the session.
+	tlsSessionCache = tls.NewLRUClientSessionCache(DefaultTLSSessionCacheSize)
+	defaultClients  = make(map[string]*http.Client)
+	httpMutex       sync.Mutex
+)
+
+// SetTLSSessionCacheSize resets the size of the TLS session cache, it should be called before any transport is created.
+func SetTLSSessionCacheSize(size int) {
+
+	httpMutex.Lock()
+	defer httpMutex.Unlock()
+
+	if size <= 0 {
+		size = DefaultTLSSessionCacheSize
+	}
+
+	tlsSessionCache = tls.NewLRUClientSessionCache(size)
+	defaultClients = make(map[string]*http.Client)
+}
+
+// TLSSessionCache returns the TLS session cache shared by the notifiers, for the TLS connections which are not
+// made by the http transports, such as the SMTP connections.
+func TLSSessionCache() tls.ClientSessionCache {
+
+	httpMutex.Lock()
+	defer httpMutex.Unlock()
+
+	return tlsSessionCache
+}
+
+// GetDefaultClient returns the http client shared by the notifiers of `notifierType` which do not need a custom HTTPClientConfig.
+func GetDefaultClient(notifierType string) *http.Client {
+
+	httpMutex.Lock()
+	defer httpMutex.Unlock()
+
+	if client, ok := defaultClients[notifierType]; ok {
+		return client
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   TLSHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{ClientSessionCache: tlsSessionCache},
+	}
+	transport.DialTLSContext = dialTLS(notifierType, transport.DialContext, transport.TLSClientConfig)
+
+	client := &http.Client{Transport: transport}
+	defaultClients[notifierType] = client
+	return client
+}
+
+const DefaultClientCacheSize = 100
+
+// ClientCache is a bounded cache of the http clients reused across the notifications. The least recently used client
+// is evicted when the cache is full, so the clients of the stale configs will not pile up.
+type ClientCache struct {
+	size    int
+	clients map[string]*list.Element
+	lru     *list.List
+	mutex   sync.Mutex
+}
+
+type cachedClient struct {
+	key    string
+	client *http.Client
+}
+
+func NewClientCache(size int) *ClientCache {
+
+	if size <= 0 {
+		size = DefaultClientCacheSize
+	}
+
+	return &ClientCache{
+		size:    size,
+		clients: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the client of the key, the client will be created by `newClient` if it is not cached.
+func (c *ClientCache) Get(key string, newClient func() (*http.Client, error)) (*http.Client, error) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.clients[key]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*cachedClient).client, nil
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.clients[key] = c.lru.PushFront(&cachedClient{key: key, client: client})
+	for c.lru.Len() > c.size {
+		e := c.lru.Back()
+		cc := c.lru.Remove(e).(*cachedClient)
+		delete(c.clients, cc.key)
+		cc.client.CloseIdleConnections()
+	}
+
+	return client, nil
+}
+
+// Len returns the number of the clients cached.
+func (c *ClientCache) Len() int {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+// NewTransport creates a http transport with the HTTPClientConfig, the secrets are read from the `namespace`.
+func NewTransport(notifierCfg *config.Config, notifierType, namespace, name string, c *v1alpha1.HTTPClientConfig) (*http.Transport, error) {
+
+	var tc *v1alpha1.TLSConfig
+	if c != nil {
+		tc = c.TLSConfig
+	}
+
+	tlsConfig, err := NewTLSConfig(notifierCfg, namespace, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		DisableKeepAlives:  false,
+		DisableCompression: true,
+		DialContext: conntrack.NewDialContextFunc(
+			conntrack.DialWithTracing(),
+			conntrack.DialWithName(name),
+		),
+		TLSClientConfig: tlsConfig,
+	}
+	transport.DialTLSContext = dialTLS(notifierType, transport.DialContext, tlsConfig)
+
+	if c == nil {
+		return transport, nil
+	}
+
+	if len(c.ProxyURL) > 0 {
+		var proxy func(*http.Request) (*url.URL, error)
+		if u, err := url.Parse(c.ProxyURL); err != nil {
+			return nil, err
+		} else {
+			proxy = http.ProxyURL(u)
+		}
+
+		transport.Proxy = proxy
+	}
+
+	return transport, nil
+}
+
+// NewTLSConfig creates a tls config with the TLSConfig, the secrets are read from the `namespace`.
+// The tls config shares the session cache with the transports.
+func NewTLSConfig(notifierCfg *config.Config, namespace string, c *v1alpha1.TLSConfig) (*tls.Config, error) {
+
+	httpMutex.Lock()
+	tlsConfig := &tls.Config{ClientSessionCache: tlsSessionCache}
+	httpMutex.Unlock()
+
+	if c == nil {
+		return tlsConfig, nil
+	}
+
+	tlsConfig.InsecureSkipVerify = c.InsecureSkipVerify
+
+	// If a CA cert is provided then let's read it in so we can validate the
+	// scrape target's certificate properly.
+	if c.RootCA != nil {
+		if ca, err := notifierCfg.GetSecretData(namespace, c.RootCA); err != nil {
+			return nil, err
+		} else {
+			caCertPool := x509.NewCertPool()
+			if !caCertPool.AppendCertsFromPEM([]byte(ca)) {
+				return nil, fmt.Errorf("no valid root ca found")
+			}
+			tlsConfig.RootCAs = caCertPool
+		}
+	}
+
+	if len(c.ServerName) > 0 {
+		tlsConfig.ServerName = c.ServerName
+	}
+
+	// If a client cert & key is provided then configure TLS config accordingly.
+	if c.ClientCertificate != nil {
+		if c.Cert != nil && c.Key == nil {
+			return nil, fmt.Errorf("client cert file specified without client key file")
+		} else if c.Cert == nil && c.Key != nil {
+			return nil, fmt.Errorf("client key file specified without client cert file")
+		} else if c.Cert != nil && c.Key != nil {
+			key, err := notifierCfg.GetSecretData(namespace, c.Key)
+			if err != nil {
+				return nil, err
+			}
+
+			cert, err := notifierCfg.GetSecretData(namespace, c.Cert)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsCert, err := tls.X509KeyPair([]byte(cert), []byte(key))
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{tlsCert}
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// SetAuthorization sets the bearer token or the basic auth in the HTTPClientConfig to the request.
+func SetAuthorization(notifierCfg *config.Config, namespace string, c *v1alpha1.HTTPClientConfig, request *http.Request) error {
+
+	if c == nil {
+		return nil
+	}
+
+	if c.BearerToken != nil {
+		bearer, err := notifierCfg.GetSecretData(namespace, c.BearerToken)
+		if err != nil {
+			return err
+		}
+
+		request.Header.Set("Authorization", bearer)
+	} else if c.BasicAuth != nil {
+		pass := ""
+		if c.BasicAuth.Password != nil {
+			p, err := notifierCfg.GetSecretData(namespace, c.BasicAuth.Password)
+			if err != nil {
+				return err
+			}
+
+			pass = p
+		}
+		request.SetBasicAuth(c.BasicAuth.Username, pass)
+	}
+
+	return nil
+}
+
+// Dial the TLS connection with the session cache in `tlsConfig`, and record whether the session is resumed.
+// The tls config may be modified after the transport is created, so it is read when dialing.
+// The dial and the handshake are canceled with the context of the request.
+func dialTLS(notifierType string, dial func(ctx context.Context, network, addr string) (net.Conn, error), tlsConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		c := tlsConfig.Clone()
+		if len(c.ServerName) == 0 {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			c.ServerName = host
+		}
+
+		tlsConn := tls.Client(conn, c)
+		ctx, cancel := context.WithTimeout(ctx, TLSHandshakeTimeout)
+		defer cancel()
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		stats.GetTLSRecorder().Record(notifierType, tlsConn.ConnectionState().DidResume)
+		return tlsConn, nil
+	}
+}
@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+)
+
+func TestClientCache(t *testing.T) {
+
+	c := NewClientCache(2)
+	created := 0
+	get := func(key string) *http.Client {
+		client, err := c.Get(key, func() (*http.Client, error) {
+			created++
+			return &http.Client{}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	a := get("a")
+	if get("a") != a || created != 1 {
+		t.Fatal("expect the client reused")
+	}
+
+	// b is the least recently used one when c is added.
+	get("b")
+	get("a")
+	get("c")
+	if c.Len() != 2 {
+		t.Fatalf("expect 2 clients cached, got %d", c.Len())
+	}
+	if get("a") != a || created != 3 {
+		t.Fatal("expect the recently used client kept")
+	}
+	get("b")
+	if created != 4 {
+		t.Fatal("expect the least recently used client evicted")
+	}
+
+	// The client failing to be created is not cached.
+	if _, err := c.Get("d", func() (*http.Client, error) {
+		return nil, fmt.Errorf("invalid config")
+	}); err == nil || c.Len() != 2 {
+		t.Fatal("expect the error returned and nothing cached")
+	}
+}
+
+func TestTransportResumesTLSSession(t *testing.T) {
+
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	transport, err := NewTransport(&config.Config{}, "ResumeTest", "default", "test",
+		&v1alpha1.HTTPClientConfig{TLSConfig: &v1alpha1.TLSConfig{InsecureSkipVerify: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(s.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		// The next request dials a new connection.
+		transport.CloseIdleConnections()
+	}
+
+	got := stats.GetTLSRecorder().Summaries()["ResumeTest"]
+	if got.FullHandshakes != 1 || got.ResumedSessions != 1 {
+		t.Fatalf("expect the second connection resumed, got %+v", got)
+	}
+}
+
+func TestDialTLSCanceledWithRequest(t *testing.T) {
+
+	// The server accepts the connections but never completes the handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	dial := dialTLS("Webhook", (&net.Dialer{}).DialContext, &tls.Config{})
+	start := time.Now()
+	if _, err := dial(ctx, "tcp", ln.Addr().String()); err == nil {
+		t.Fatal("expect the handshake to fail")
+	}
+	if used := time.Since(start); used >= TLSHandshakeTimeout {
+		t.Fatalf("expect the handshake canceled with the request, used %s", used)
+	}
+}
@@ -0,0 +1,84 @@
+package notifier
+
+import (
+	"context"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"sync"
+	"time"
+)
+
+var inFlightLimiter *InFlightLimiter
+
+// InFlightLimiter limits the number of the sends in flight of each receiver,
+// so that the backend of a receiver will not be overloaded by the notifications sent simultaneously.
+type InFlightLimiter struct {
+	semaphores map[string]*semaphore
+	mutex      sync.Mutex
+}
+
+type semaphore struct {
+	limit int
+	ch    chan struct{}
+}
+
+func init() {
+	inFlightLimiter = NewInFlightLimiter()
+}
+
+func GetInFlightLimiter() *InFlightLimiter {
+	return inFlightLimiter
+}
+
+func NewInFlightLimiter() *InFlightLimiter {
+	return &InFlightLimiter{
+		semaphores: make(map[string]*semaphore),
+	}
+}
+
+// Acquire waits until the number of the sends in flight of `key` is less than `limit`, 0 means no limit.
+// The returned function must be called to release the slot after sending.
+// The sends which have to wait are counted by the counters `inflight_queued` and `inflight_wait_ms`.
+func (l *InFlightLimiter) Acquire(ctx context.Context, key string, limit int) (func(), error) {
+
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	s := l.get(key, limit)
+	select {
+	case s.ch <- struct{}{}:
+		return func() { <-s.ch }, nil
+	default:
+	}
+
+	start := time.Now()
+	defer func() {
+		stats.GetCounters().Add("inflight_queued", 1)
+		stats.GetCounters().Add("inflight_wait_ms", int(time.Since(start)/time.Millisecond))
+	}()
+
+	select {
+	case s.ch <- struct{}{}:
+		return func() { <-s.ch }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// The semaphore will be recreated if the limit is changed, the sends in flight release the old one.
+func (l *InFlightLimiter) get(key string, limit int) *semaphore {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	s, ok := l.semaphores[key]
+	if !ok || s.limit != limit {
+		s = &semaphore{
+			limit: limit,
+			ch:    make(chan struct{}, limit),
+		}
+		l.semaphores[key] = s
+	}
+
+	return s
+}
@@ -3,8 +3,71 @@ package notifier
 import (
 	"context"
 	"github.com/prometheus/alertmanager/template"
+	"reflect"
+	"time"
 )
 
 type Notifier interface {
 	Notify(ctx context.Context, data template.Data) []error
 }
+
+// WarmUpper is implemented by the notifiers which can prepare for sending in advance, such as fetching the access token.
+type WarmUpper interface {
+	WarmUp(ctx context.Context) []error
+}
+
+// ResultNotifier is implemented by the notifiers which report the outcome of the send to each target,
+// such as for the audit and the test notifications. Their Notify returns the errors of the result.
+type ResultNotifier interface {
+	Notifier
+	// The error is returned if the notification fails as a whole, such as timing out,
+	// the failures of the targets are in the result.
+	NotifyWithResult(ctx context.Context, data template.Data) (Result, error)
+}
+
+// Result is the outcome of a notification, one for each target, such as each recipient of an email.
+type Result struct {
+	Targets []TargetResult
+}
+
+type TargetResult struct {
+	// The type of the notifier, such as `Email`.
+	Type   string
+	Target string
+	// The id of the message given by the provider, such as the queue id given by the SMTP server.
+	MessageID string
+	// The other ids of the message, such as the Message-Id header of an email.
+	Extra   map[string]string
+	Latency time.Duration
+	// It is nil if the send to the target succeeded.
+	Error error
+}
+
+func (r TargetResult) Success() bool {
+	return r.Error == nil
+}
+
+// Errors returns the errors of the targets which failed, the error shared by the targets sent together is returned once.
+func (r Result) Errors() []error {
+
+	var errs []error
+	seen := make(map[error]bool)
+	for _, t := range r.Targets {
+		if t.Error == nil {
+			continue
+		}
+
+		// The errors which are not comparable can not be the keys.
+		if !reflect.TypeOf(t.Error).Comparable() {
+			errs = append(errs, t.Error)
+			continue
+		}
+
+		if !seen[t.Error] {
+			seen[t.Error] = true
+			errs = append(errs, t.Error)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"bytes"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/yuin/goldmark"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	tmplhtml "html/template"
+	"net/url"
+	"strings"
+)
+
+var (
+	markdown = goldmark.New()
+
+	// The elements kept by the sanitizer, the others are removed but their text is kept.
+	allowedElements = map[atom.Atom]bool{
+		atom.P: true, atom.Br: true, atom.Hr: true, atom.A: true, atom.Strong: true, atom.Em: true, atom.Del: true,
+		atom.Code: true, atom.Pre: true, atom.Blockquote: true, atom.Ul: true, atom.Ol: true, atom.Li: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	}
+	// The elements removed with their content.
+	droppedElements = map[atom.Atom]bool{
+		atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true, atom.Embed: true,
+		atom.Noscript: true, atom.Template: true, atom.Svg: true, atom.Math: true,
+	}
+	// The attributes kept by the sanitizer for each element, the event handlers such as `onclick` are never kept.
+	allowedAttributes = map[atom.Atom][]string{
+		atom.A:  {"href", "title"},
+		atom.Ol: {"start"},
+	}
+	allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+func init() {
+	template.DefaultFuncs["markdownToHTML"] = MarkdownToHTML
+}
+
+// MarkdownToHTML converts the Markdown to html, and sanitizes it so only the formatting elements and the links
+// are kept, such as `{{ markdownToHTML .Annotations.description }}` in the html templates.
+// The raw html in the Markdown is removed, the scripts, the event handlers and the links of unsafe schemes are stripped.
+func MarkdownToHTML(s string) tmplhtml.HTML {
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(s), &buf); err != nil {
+		return tmplhtml.HTML(tmplhtml.HTMLEscapeString(s))
+	}
+
+	return tmplhtml.HTML(SanitizeHTML(buf.String()))
+}
+
+// SanitizeHTML keeps the allowed elements and attributes in the html, the text is escaped.
+func SanitizeHTML(s string) string {
+
+	var buf bytes.Buffer
+	// The depth of the dropped elements the tokenizer is in.
+	dropped := 0
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return buf.String()
+		}
+
+		t := z.Token()
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedElements[t.DataAtom] {
+				if tt == html.StartTagToken {
+					dropped++
+				}
+				continue
+			}
+			if dropped == 0 && allowedElements[t.DataAtom] {
+				t.Attr = sanitizeAttributes(t.DataAtom, t.Attr)
+				buf.WriteString(t.String())
+			}
+		case html.EndTagToken:
+			if droppedElements[t.DataAtom] {
+				if dropped > 0 {
+					dropped--
+				}
+				continue
+			}
+			if dropped == 0 && allowedElements[t.DataAtom] {
+				buf.WriteString(t.String())
+			}
+		case html.TextToken:
+			if dropped == 0 {
+				buf.WriteString(html.EscapeString(t.Data))
+			}
+		}
+	}
+}
+
+func sanitizeAttributes(a atom.Atom, attrs []html.Attribute) []html.Attribute {
+
+	var res []html.Attribute
+	for _, attr := range attrs {
+		allowed := false
+		for _, name := range allowedAttributes[a] {
+			if len(attr.Namespace) == 0 && strings.ToLower(attr.Key) == name {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed || (attr.Key == "href" && !safeURL(attr.Val)) {
+			continue
+		}
+
+		res = append(res, attr)
+	}
+
+	return res
+}
+
+// The relative urls and the urls of the allowed schemes are safe, `javascript:` and `data:` are not.
+func safeURL(s string) bool {
+
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+
+	return len(u.Scheme) == 0 || allowedSchemes[strings.ToLower(u.Scheme)]
+}
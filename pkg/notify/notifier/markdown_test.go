@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"emphasis", "**disk** is *full*", "<p><strong>disk</strong> is <em>full</em></p>\n"},
+		{"link", "see [runbook](https://runbook/disk)", `<p>see <a href="https://runbook/disk">runbook</a></p>` + "\n"},
+		{"relative link", "[runbook](/runbook/disk)", `<p><a href="/runbook/disk">runbook</a></p>` + "\n"},
+		{"javascript link", "[click](javascript:alert(1))", `<p><a href="">click</a></p>` + "\n"},
+		{"list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"code", "`rm -rf /`", "<p><code>rm -rf /</code></p>\n"},
+		{"escaped text", "a < b & c", "<p>a &lt; b &amp; c</p>\n"},
+		{"raw html", `<script>alert(1)</script>`, "\n"},
+		{"inline raw html", `disk <img src=x onerror=alert(1)> full`, "<p>disk  full</p>\n"},
+		{"image", "![logo](https://example.com/logo.png)", "<p></p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(MarkdownToHTML(tt.markdown)); got != tt.want {
+				t.Fatalf("MarkdownToHTML(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"allowed elements", "<p><strong>a</strong><br/></p>", "<p><strong>a</strong><br/></p>"},
+		{"event handlers", `<p onclick="alert(1)">a</p>`, "<p>a</p>"},
+		{"disallowed element keeps text", `<div><span>a</span></div>`, "a"},
+		{"script dropped with content", `a<script>alert("b")</script>c`, "ac"},
+		{"nested dropped elements", `a<svg><script>b</script><style>c</style></svg>d`, "ad"},
+		{"unsafe href", `<a href="data:text/html;base64,PHNjcmlwdD4=" title="t">a</a>`, `<a title="t">a</a>`},
+		{"safe href", `<a href="mailto:ops@example.com" target="_blank">a</a>`, `<a href="mailto:ops@example.com">a</a>`},
+		{"ordered list start", `<ol start="3" style="color: red"><li>a</li></ol>`, `<ol start="3"><li>a</li></ol>`},
+		{"comment", "a<!-- b -->c", "ac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.html); got != tt.want {
+				t.Fatalf("SanitizeHTML(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotationHTML(t *testing.T) {
+
+	data := template.Data{
+		Alerts: template.Alerts{{
+			Status:      "firing",
+			Labels:      template.KV{"alertname": "DiskFull"},
+			Annotations: template.KV{"description": "**disk** <b>full</b>", "summary": "**disk** <b>full</b>"},
+		}},
+	}
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}{{ range .Alerts }}{{ range .Annotations.SortedPairs }}`+
+		`{{ .Name }}={{ $.AnnotationHTML .Name .Value }};{{ end }}{{ end }}{{ end }}`+
+		`{{ define "section" }}{{ range .Alerts }}{{ range .AnnotationSection }}`+
+		`{{ .Name }}={{ $.AnnotationHTML .Name .Value }};{{ end }}{{ end }}{{ end }}`)
+
+	tests := []struct {
+		name     string
+		template string
+		markdown []string
+		fields   []v1alpha1.AnnotationField
+		want     string
+	}{
+		{
+			name:     "no markdown",
+			template: "msg",
+			want:     "description=**disk** &lt;b&gt;full&lt;/b&gt;;summary=**disk** &lt;b&gt;full&lt;/b&gt;;",
+		},
+		{
+			name:     "markdown annotation",
+			template: "msg",
+			markdown: []string{"description"},
+			want:     "description=<p><strong>disk</strong> full</p>\n;summary=**disk** &lt;b&gt;full&lt;/b&gt;;",
+		},
+		{
+			name:     "display name in the annotation section",
+			template: "section",
+			markdown: []string{"description"},
+			fields:   []v1alpha1.AnnotationField{{Name: "description", DisplayName: "Details"}},
+			want:     "Details=<p><strong>disk</strong> full</p>\n;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.WithAnnotationSection(tt.fields).WithMarkdownAnnotations(tt.markdown).
+				TempleHTML(tt.template, data, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownCacheKey(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}{{ end }}`)
+	plain := tmpl.CacheKey("msg")
+	md := tmpl.WithMarkdownAnnotations([]string{"description"})
+
+	if md.CacheKey("msg") == plain {
+		t.Fatal("expect the templates rendering markdown cached separately")
+	}
+	// The other settings are kept by the derived templates.
+	if got := md.WithCollapsible(true).CacheKey("msg"); got != "msg/collapsible/markdown[description]" {
+		t.Fatalf("cache key = %s", got)
+	}
+}
@@ -0,0 +1,255 @@
+package pushgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/async"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSendTimeout = time.Second * 5
+	DefaultJob         = "notification-manager"
+	ResolvedActionDel  = "delete"
+	ResolvedActionZero = "zero"
+	MetricName         = "nm_alert"
+	contentType        = "text/plain; version=0.0.4"
+)
+
+var (
+	// The labels pushed as the grouping key if the receiver does not specify the labels.
+	DefaultLabels = []string{"alertname", "severity", "namespace"}
+	// The http clients are reused across notifications, the key is the md5 of the config and the timeout.
+	clients = notifier.NewClientCache(notifier.DefaultClientCacheSize)
+)
+
+type Notifier struct {
+	notifierCfg *config.Config
+	pushgateway []*config.Pushgateway
+	timeout     time.Duration
+	logger      log.Logger
+}
+
+func NewPushgatewayNotifier(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier {
+
+	n := &Notifier{
+		notifierCfg: notifierCfg,
+		timeout:     DefaultSendTimeout,
+		logger:      logger,
+	}
+
+	opts := notifierCfg.ReceiverOpts
+	if opts != nil && opts.Pushgateway != nil && opts.Pushgateway.NotificationTimeout != nil {
+		n.timeout = time.Second * time.Duration(*opts.Pushgateway.NotificationTimeout)
+	}
+
+	for _, r := range receivers {
+		receiver, ok := r.(*config.Pushgateway)
+		if !ok || receiver == nil {
+			continue
+		}
+
+		if receiver.PushgatewayConfig == nil {
+			notifier.ReportConfigError(logger, notifierCfg, "Pushgateway", receiver, fmt.Errorf("no config is found"))
+			continue
+		}
+
+		n.pushgateway = append(n.pushgateway, receiver)
+	}
+
+	return n
+}
+
+func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
+
+	send := func(p *config.Pushgateway) (err error) {
+
+		start := time.Now()
+		defer func() {
+			_ = level.Debug(n.logger).Log("msg", "PushgatewayNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Pushgateway", time.Since(start), err)
+		}()
+
+		client, err := n.getClient(p)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "PushgatewayNotifier: get client error", "error", err.Error())
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+
+		for _, alert := range data.Alerts {
+			u, err := groupingKeyURL(p.PushgatewayConfig, labelsOf(p, alert))
+			if err != nil {
+				_ = level.Error(n.logger).Log("msg", "PushgatewayNotifier: generate url error", "error", err.Error())
+				return err
+			}
+
+			method, value := http.MethodPut, 1
+			if alert.Status == string(model.AlertResolved) {
+				method, value = http.MethodDelete, 0
+				if p.ResolvedAction == ResolvedActionZero {
+					method = http.MethodPut
+				}
+			}
+
+			if err := n.send(ctx, client, p, method, u, value); err != nil {
+				_ = level.Error(n.logger).Log("msg", "PushgatewayNotifier: push metric error", "url", u, "method", method, "error", err.Error())
+				return err
+			}
+		}
+
+		_ = level.Debug(n.logger).Log("msg", "PushgatewayNotifier: send message", "to", p.PushgatewayConfig.URL, "alerts", len(data.Alerts))
+		return nil
+	}
+
+	group := async.NewGroup(ctx)
+	for _, pushgateway := range n.pushgateway {
+		p := pushgateway
+		group.Add(func(stopCh chan interface{}) {
+			stopCh <- send(p)
+		})
+	}
+
+	return group.Wait()
+}
+
+// Push the metric with PUT so it replaces the metrics of the same grouping key, or delete the grouping key with DELETE.
+func (n *Notifier) send(ctx context.Context, client *http.Client, p *config.Pushgateway, method, u string, value int) error {
+
+	var body io.Reader
+	if method != http.MethodDelete {
+		body = bytes.NewBufferString(fmt.Sprintf("# TYPE %s gauge\n%s %d\n", MetricName, MetricName, value))
+	}
+
+	request, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	if err := notifier.SetAuthorization(n.notifierCfg, p.GetNamespace(), p.PushgatewayConfig.HTTPConfig, request); err != nil {
+		return err
+	}
+
+	resp, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("pushgateway error, code: %d, message: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
+
+// The labels of the alert in the allowlist of the receiver, `job` is renamed to `exported_job`
+// because it is the job of the grouping key.
+func labelsOf(p *config.Pushgateway, alert template.Alert) [][2]string {
+
+	names := p.Labels
+	if len(names) == 0 {
+		names = DefaultLabels
+	}
+
+	var labels [][2]string
+	for _, name := range names {
+		value, ok := alert.Labels[name]
+		if !ok || !model.LabelName(name).IsValid() {
+			continue
+		}
+
+		if name == "job" {
+			name = "exported_job"
+		}
+		labels = append(labels, [2]string{name, value})
+	}
+
+	return labels
+}
+
+// The url of the grouping key, such as `/metrics/job/<job>/alertname/<alertname>`.
+// The values which are empty or contain `/` are encoded in base64.
+func groupingKeyURL(c *config.PushgatewayConfig, labels [][2]string) (string, error) {
+
+	job := c.Job
+	if len(job) == 0 {
+		job = DefaultJob
+	}
+
+	segment := func(name, value string) string {
+		if len(value) == 0 || strings.Contains(value, "/") {
+			return fmt.Sprintf("/%s@base64/%s", name, encodeValue(value))
+		}
+		return fmt.Sprintf("/%s/%s", name, url.PathEscape(value))
+	}
+
+	path := "/metrics" + segment("job", job)
+	for _, l := range labels {
+		path += segment(l[0], l[1])
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(u.String(), "/") + path, nil
+}
+
+// The empty value is encoded as `=`, since the Pushgateway does not accept an empty path segment.
+func encodeValue(value string) string {
+
+	if len(value) == 0 {
+		return "="
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// Get the http client of the receiver, the client will be reused if the config is not changed.
+func (n *Notifier) getClient(p *config.Pushgateway) (*http.Client, error) {
+
+	key, err := notifier.Md5key(p.PushgatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+	key = fmt.Sprintf("%s/%s/%s", p.GetNamespace(), key, n.timeout)
+
+	return clients.Get(key, func() (*http.Client, error) {
+
+		transport, err := notifier.NewTransport(n.notifierCfg, "Pushgateway", p.GetNamespace(), p.PushgatewayConfig.URL, p.PushgatewayConfig.HTTPConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Client{
+			Transport: transport,
+			Timeout:   n.timeout,
+		}, nil
+	})
+}
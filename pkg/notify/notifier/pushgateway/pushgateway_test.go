@@ -0,0 +1,207 @@
+package pushgateway
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+type request struct {
+	method string
+	path   string
+	body   string
+}
+
+// A fake Pushgateway replying the status, the requests are recorded.
+func pushgatewayServer(t *testing.T, status int) (*httptest.Server, func() []request) {
+
+	var mutex sync.Mutex
+	var requests []request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		mutex.Lock()
+		requests = append(requests, request{method: r.Method, path: r.URL.EscapedPath(), body: string(bs)})
+		mutex.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s, func() []request {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]request(nil), requests...)
+	}
+}
+
+func newTestReceiver(url, resolvedAction string, labels ...string) *config.Pushgateway {
+
+	r := config.NewPushgatewayReceiver().(*config.Pushgateway)
+	r.SetNamespace("default")
+	r.Labels = labels
+	r.ResolvedAction = resolvedAction
+	r.PushgatewayConfig = &config.PushgatewayConfig{URL: url}
+	return r
+}
+
+func testAlert(status string) template.Alert {
+
+	a := template.Alert{
+		Status:   status,
+		Labels:   template.KV{"alertname": "KubePodCrashLooping", "namespace": "default", "job": "kubelet"},
+		StartsAt: time.Unix(1600000000, 0),
+	}
+	if status == "resolved" {
+		a.EndsAt = time.Unix(1600003600, 0)
+	}
+
+	return a
+}
+
+func TestGroupingKeyURL(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		url    string
+		job    string
+		labels [][2]string
+		want   string
+	}{
+		{
+			name: "default job",
+			url:  "http://pushgateway:9091",
+			want: "http://pushgateway:9091/metrics/job/notification-manager",
+		},
+		{
+			name:   "plain values",
+			url:    "http://pushgateway:9091/",
+			job:    "alerts",
+			labels: [][2]string{{"alertname", "Watchdog"}, {"namespace", "default"}},
+			want:   "http://pushgateway:9091/metrics/job/alerts/alertname/Watchdog/namespace/default",
+		},
+		{
+			name:   "value with slash",
+			url:    "http://pushgateway:9091",
+			labels: [][2]string{{"path", "/var/lib"}},
+			want:   "http://pushgateway:9091/metrics/job/notification-manager/path@base64/L3Zhci9saWI",
+		},
+		{
+			name: "job with slash",
+			url:  "http://pushgateway:9091",
+			job:  "a/b",
+			want: "http://pushgateway:9091/metrics/job@base64/YS9i",
+		},
+		{
+			name:   "empty value",
+			url:    "http://pushgateway:9091",
+			labels: [][2]string{{"severity", ""}},
+			want:   "http://pushgateway:9091/metrics/job/notification-manager/severity@base64/=",
+		},
+		{
+			name:   "value escaped",
+			url:    "http://pushgateway:9091",
+			labels: [][2]string{{"alertname", "Pod Down?"}},
+			want:   "http://pushgateway:9091/metrics/job/notification-manager/alertname/Pod%20Down%3F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := groupingKeyURL(&config.PushgatewayConfig{URL: tt.url, Job: tt.job}, tt.labels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("groupingKeyURL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelsOf(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   [][2]string
+	}{
+		{"default labels", nil, [][2]string{{"alertname", "KubePodCrashLooping"}, {"namespace", "default"}}},
+		{"job renamed", []string{"job", "alertname"}, [][2]string{{"exported_job", "kubelet"}, {"alertname", "KubePodCrashLooping"}}},
+		{"missing and invalid labels skipped", []string{"pod", "alert-name", "namespace"}, [][2]string{{"namespace", "default"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := testAlert("firing")
+			alert.Labels["alert-name"] = "invalid"
+			got := labelsOf(newTestReceiver("", "", tt.labels...), alert)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("labelsOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+
+	const path = "/metrics/job/notification-manager/alertname/KubePodCrashLooping/exported_job/kubelet"
+
+	tests := []struct {
+		name           string
+		status         string
+		resolvedAction string
+		want           request
+	}{
+		{"firing pushed", "firing", "", request{http.MethodPut, path, "# TYPE nm_alert gauge\nnm_alert 1\n"}},
+		{"resolved deleted", "resolved", ResolvedActionDel, request{http.MethodDelete, path, ""}},
+		{"resolved deleted by default", "resolved", "", request{http.MethodDelete, path, ""}},
+		{"resolved set to zero", "resolved", ResolvedActionZero, request{http.MethodPut, path, "# TYPE nm_alert gauge\nnm_alert 0\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, received := pushgatewayServer(t, http.StatusOK)
+			r := newTestReceiver(s.URL, tt.resolvedAction, "alertname", "job")
+			n := NewPushgatewayNotifier(log.NewNopLogger(), []config.Receiver{r}, &config.Config{})
+
+			data := template.Data{Status: tt.status, Alerts: template.Alerts{testAlert(tt.status)}}
+			if errs := n.Notify(context.Background(), data); len(errs) != 0 {
+				t.Fatal(errs)
+			}
+
+			reqs := received()
+			if len(reqs) != 1 || reqs[0] != tt.want {
+				t.Fatalf("requests = %q, want %q", reqs, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyError(t *testing.T) {
+
+	s, received := pushgatewayServer(t, http.StatusBadRequest)
+	r := newTestReceiver(s.URL, "")
+	n := NewPushgatewayNotifier(log.NewNopLogger(), []config.Receiver{r}, &config.Config{})
+
+	data := template.Data{Status: "firing", Alerts: template.Alerts{testAlert("firing"), testAlert("firing")}}
+	if errs := n.Notify(context.Background(), data); len(errs) != 1 {
+		t.Fatalf("expect 1 error, got %v", errs)
+	}
+	// The remaining alerts are not pushed after a failure.
+	if reqs := received(); len(reqs) != 1 {
+		t.Fatalf("expect 1 request, got %d", len(reqs))
+	}
+}
@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultQuarantineCooldown = time.Hour
+	// The targets are cleaned up when there are more than this many.
+	maxQuarantineTargets = 10000
+)
+
+var (
+	quarantine        *Quarantine
+	quarantineHandler func(logger log.Logger, notifierCfg *config.Config, e *QuarantinedError)
+)
+
+// Quarantine tracks the consecutive failures of each target, such as an email address, the target is not sent to
+// for the cooldown after the failures reach the threshold. The failures of all the receivers sending to the target are counted.
+type Quarantine struct {
+	targets map[string]*quarantineState
+	mutex   sync.Mutex
+}
+
+type quarantineState struct {
+	failures int
+	// The last error of the target.
+	reason string
+	// The end of the quarantine, it is zero if the target is not quarantined.
+	until time.Time
+}
+
+// QuarantinedError means the send to the target is dropped because the target is quarantined.
+type QuarantinedError struct {
+	Target   string
+	Failures int
+	Reason   string
+	Until    time.Time
+}
+
+func (e *QuarantinedError) Error() string {
+	return fmt.Sprintf("target %s is quarantined until %s after %d consecutive failures, the last error: %s",
+		e.Target, e.Until.Format(time.RFC3339), e.Failures, e.Reason)
+}
+
+func init() {
+	quarantine = NewQuarantine()
+}
+
+func GetQuarantine() *Quarantine {
+	return quarantine
+}
+
+func NewQuarantine() *Quarantine {
+	return &Quarantine{
+		targets: make(map[string]*quarantineState),
+	}
+}
+
+// OnTargetQuarantined sets the handler of the targets quarantined, such as notifying the administrators.
+func OnTargetQuarantined(f func(logger log.Logger, notifierCfg *config.Config, e *QuarantinedError)) {
+	quarantineHandler = f
+}
+
+// TargetQuarantine returns the config of quarantining the targets, it is nil if it is disabled.
+func TargetQuarantine(opts *v1alpha1.Options) *v1alpha1.TargetQuarantine {
+
+	if opts == nil || opts.Global == nil || opts.Global.TargetQuarantine == nil {
+		return nil
+	}
+
+	q := opts.Global.TargetQuarantine
+	if q.Threshold <= 0 {
+		return nil
+	}
+
+	return q
+}
+
+// Check returns a QuarantinedError if the target of the key is quarantined,
+// the target is released if the cooldown is over, and its failures are counted from 0 again.
+func (q *Quarantine) Check(key string) error {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	s, ok := q.targets[key]
+	if !ok || s.until.IsZero() {
+		return nil
+	}
+
+	if !time.Now().Before(s.until) {
+		delete(q.targets, key)
+		return nil
+	}
+
+	stats.GetCounters().Add("target_quarantine_dropped", 1)
+	return &QuarantinedError{Target: key, Failures: s.failures, Reason: s.reason, Until: s.until}
+}
+
+// Record the result of a send to the target of the key, a success resets the failures of the target.
+// The target is quarantined when its consecutive failures reach the threshold, and the handler is called.
+func (q *Quarantine) Record(logger log.Logger, notifierCfg *config.Config, key string, err error) {
+
+	opts := TargetQuarantine(notifierCfg.ReceiverOpts)
+	if opts == nil {
+		return
+	}
+
+	e := q.record(key, err, opts, time.Now())
+	if e == nil {
+		return
+	}
+
+	stats.GetCounters().Add("target_quarantined", 1)
+	_ = level.Warn(logger).Log("msg", "quarantine target because of consecutive failures", "target", e.Target,
+		"failures", e.Failures, "until", e.Until, "error", e.Reason)
+
+	if quarantineHandler != nil {
+		quarantineHandler(logger, notifierCfg, e)
+	}
+}
+
+// It returns the error of the quarantine if the target is quarantined by this failure.
+func (q *Quarantine) record(key string, err error, opts *v1alpha1.TargetQuarantine, now time.Time) *QuarantinedError {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if err == nil {
+		delete(q.targets, key)
+		return nil
+	}
+
+	s, ok := q.targets[key]
+	if !ok {
+		if len(q.targets) >= maxQuarantineTargets {
+			q.cleanup(now)
+		}
+		s = &quarantineState{}
+		q.targets[key] = s
+	}
+
+	// The sends started before the target is quarantined may still fail.
+	if !s.until.IsZero() {
+		return nil
+	}
+
+	s.failures++
+	s.reason = MaskSecrets(err.Error())
+	if s.failures < opts.Threshold {
+		return nil
+	}
+
+	cooldown := opts.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultQuarantineCooldown
+	}
+	s.until = now.Add(cooldown)
+
+	return &QuarantinedError{Target: key, Failures: s.failures, Reason: s.reason, Until: s.until}
+}
+
+// Release the target of the key, such as when the receiver sending to it is changed.
+func (q *Quarantine) Release(key string) {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	delete(q.targets, key)
+}
+
+// Must be called with the mutex held, the targets not quarantined and the ones whose cooldown is over are removed.
+func (q *Quarantine) cleanup(now time.Time) {
+
+	for k, s := range q.targets {
+		if s.until.IsZero() || !now.Before(s.until) {
+			delete(q.targets, k)
+		}
+	}
+}
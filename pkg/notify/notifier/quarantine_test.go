@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+)
+
+func TestQuarantineRecord(t *testing.T) {
+
+	opts := &v1alpha1.TargetQuarantine{Threshold: 3, Cooldown: time.Hour}
+	failed := fmt.Errorf("550 5.2.2 mailbox full")
+
+	tests := []struct {
+		name string
+		// The results of the sends in order, true means succeeded.
+		results []bool
+		// Whether the target is quarantined by the last send.
+		want bool
+	}{
+		{"reach the threshold", []bool{false, false, false}, true},
+		{"below the threshold", []bool{false, false}, false},
+		{"success resets the failures", []bool{false, false, true, false, false}, false},
+		{"healthy", []bool{true, true, true, true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuarantine()
+			now := time.Now()
+			var e *QuarantinedError
+			for i, ok := range tt.results {
+				err := failed
+				if ok {
+					err = nil
+				}
+				if e = q.record("Email/ops@example.com", err, opts, now); e != nil && i != len(tt.results)-1 {
+					t.Fatalf("expect the target quarantined by the last send, got it by send %d", i)
+				}
+			}
+
+			if (e != nil) != tt.want {
+				t.Fatalf("quarantined = %v, want %v", e != nil, tt.want)
+			}
+			if got := q.Check("Email/ops@example.com") != nil; got != tt.want {
+				t.Fatalf("Check() quarantined = %v, want %v", got, tt.want)
+			}
+			if tt.want && (e.Failures != opts.Threshold || e.Reason != failed.Error() || !e.Until.Equal(now.Add(opts.Cooldown))) {
+				t.Fatalf("unexpected quarantine %+v", e)
+			}
+		})
+	}
+}
+
+func TestQuarantineCooldown(t *testing.T) {
+
+	q := NewQuarantine()
+	opts := &v1alpha1.TargetQuarantine{Threshold: 1, Cooldown: time.Hour}
+	key := "Email/ops@example.com"
+
+	// Quarantined 2 hours ago, the cooldown is over.
+	if e := q.record(key, fmt.Errorf("550 5.1.1 no such user"), opts, time.Now().Add(-2*time.Hour)); e == nil {
+		t.Fatal("expect the target quarantined")
+	}
+	if err := q.Check(key); err != nil {
+		t.Fatalf("expect the target released after the cooldown, got %s", err)
+	}
+
+	// The failures are counted from 0 again.
+	if e := q.record(key, fmt.Errorf("550 5.1.1 no such user"), opts, time.Now()); e == nil {
+		t.Fatal("expect the target quarantined again")
+	}
+	if err := q.Check(key); err == nil {
+		t.Fatal("expect the target quarantined in the cooldown")
+	}
+
+	q.Release(key)
+	if err := q.Check(key); err != nil {
+		t.Fatalf("expect the target released, got %s", err)
+	}
+}
@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"github.com/prometheus/alertmanager/template"
+	"sync"
+)
+
+// RenderCache caches the rendered messages in a dispatch, so that the receivers sharing the same template
+// and data render the message only once. It should not be reused across dispatches.
+type RenderCache struct {
+	messages map[string]*renderEntry
+	mutex    sync.Mutex
+}
+
+// The message of a template and data, it is done when the first caller finishes rendering it.
+type renderEntry struct {
+	done chan struct{}
+	msg  string
+	err  error
+}
+
+func NewRenderCache() *RenderCache {
+	return &RenderCache{
+		messages: make(map[string]*renderEntry),
+	}
+}
+
+// Render returns the cached message of the template and the data, or calls `render` and caches the result if not cached.
+// The callers of the message being rendered wait for it, the messages of the other templates and data are rendered
+// concurrently. The error is returned to the waiting callers but not cached.
+func (c *RenderCache) Render(name string, data template.Data, render func() (string, error)) (string, error) {
+
+	hash, err := Md5key(data)
+	if err != nil {
+		return render()
+	}
+	key := name + "/" + hash
+
+	c.mutex.Lock()
+	e, ok := c.messages[key]
+	if !ok {
+		e = &renderEntry{done: make(chan struct{})}
+		c.messages[key] = e
+	}
+	c.mutex.Unlock()
+
+	if ok {
+		<-e.done
+		return e.msg, e.err
+	}
+
+	e.msg, e.err = render()
+	if e.err != nil {
+		c.mutex.Lock()
+		delete(c.messages, key)
+		c.mutex.Unlock()
+	}
+	close(e.done)
+
+	return e.msg, e.err
+}
@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestRenderCacheRendersOnce(t *testing.T) {
+
+	c := NewRenderCache()
+	data := template.Data{Status: "firing"}
+
+	var calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg, err := c.Render("tmpl", data, func() (string, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return "message", nil
+			})
+			if err != nil || msg != "message" {
+				t.Errorf("Render() = %q, %v", msg, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expect rendered once, got %d", calls)
+	}
+}
+
+func TestRenderCacheRendersKeysConcurrently(t *testing.T) {
+
+	c := NewRenderCache()
+	rendered := make(chan struct{})
+
+	// The render of the first message waits for the second one, it deadlocks if the cache is locked while rendering.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Render("first", template.Data{}, func() (string, error) {
+			<-rendered
+			return "first", nil
+		})
+	}()
+
+	go func() {
+		_, _ = c.Render("second", template.Data{}, func() (string, error) {
+			close(rendered)
+			return "second", nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the messages are not rendered concurrently")
+	}
+}
+
+func TestRenderCacheDoesNotCacheError(t *testing.T) {
+
+	c := NewRenderCache()
+	data := template.Data{}
+
+	if _, err := c.Render("tmpl", data, func() (string, error) { return "", errors.New("render error") }); err == nil {
+		t.Fatal("expect the error returned")
+	}
+
+	msg, err := c.Render("tmpl", data, func() (string, error) { return "message", nil })
+	if err != nil || msg != "message" {
+		t.Fatalf("Render() = %q, %v, want the message rendered again", msg, err)
+	}
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var renderPool *RenderPool
+
+// RenderPool limits the number of the templates rendered concurrently, so a storm of large notifications
+// does not starve the sends of CPU. It only bounds the rendering, the sends are limited separately.
+type RenderPool struct {
+	limit  int
+	active int
+	mutex  sync.Mutex
+	cond   *sync.Cond
+}
+
+func init() {
+	renderPool = NewRenderPool(0)
+}
+
+func GetRenderPool() *RenderPool {
+	return renderPool
+}
+
+// NewRenderPool creates a pool rendering at most `limit` templates concurrently, default is the number of CPUs.
+func NewRenderPool(limit int) *RenderPool {
+
+	p := &RenderPool{}
+	p.cond = sync.NewCond(&p.mutex)
+	p.SetLimit(limit)
+	return p
+}
+
+// SetLimit changes the limit, the renders waiting are woken up if the limit is increased.
+func (p *RenderPool) SetLimit(limit int) {
+
+	if limit <= 0 {
+		limit = runtime.NumCPU()
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.limit != limit {
+		p.limit = limit
+		p.cond.Broadcast()
+	}
+}
+
+// Acquire waits until the number of the renders in progress is less than the limit.
+// The returned function must be called to release the slot after rendering.
+// The renders which have to wait are counted by the counters `render_queued` and `render_wait_ms`.
+func (p *RenderPool) Acquire() func() {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.active >= p.limit {
+		start := time.Now()
+		for p.active >= p.limit {
+			p.cond.Wait()
+		}
+		stats.GetCounters().Add("render_queued", 1)
+		stats.GetCounters().Add("render_wait_ms", int(time.Since(start)/time.Millisecond))
+	}
+
+	p.active++
+	return p.release
+}
+
+func (p *RenderPool) release() {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.active--
+	p.cond.Signal()
+}
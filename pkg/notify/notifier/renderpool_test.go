@@ -0,0 +1,138 @@
+package notifier
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// Acquire the pool from n goroutines holding the slot for a while, it returns the maximum number of
+// the slots held at the same time.
+func acquireConcurrently(p *RenderPool, n int, hold time.Duration) int32 {
+
+	var active, max int32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := p.Acquire()
+			defer release()
+
+			cur := atomic.AddInt32(&active, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if cur <= m || atomic.CompareAndSwapInt32(&max, m, cur) {
+					break
+				}
+			}
+			time.Sleep(hold)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	return max
+}
+
+func TestRenderPoolBound(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"limit 1", 1, 1},
+		{"limit 3", 3, 3},
+		{"default limit", 0, runtime.NumCPU()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewRenderPool(tt.limit)
+			if p.limit != tt.want {
+				t.Fatalf("limit = %d, want %d", p.limit, tt.want)
+			}
+
+			if max := acquireConcurrently(p, tt.want*4, 20*time.Millisecond); int(max) > tt.want {
+				t.Fatalf("expect at most %d renders at the same time, got %d", tt.want, max)
+			}
+			if p.active != 0 {
+				t.Fatalf("expect all the slots released, %d active", p.active)
+			}
+		})
+	}
+}
+
+func TestRenderPoolSetLimit(t *testing.T) {
+
+	p := NewRenderPool(1)
+	release := p.Acquire()
+
+	acquired := make(chan func(), 2)
+	for i := 0; i < 2; i++ {
+		go func() { acquired <- p.Acquire() }()
+	}
+
+	select {
+	case <-acquired:
+		t.Fatal("expect the render waiting for the slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The waiting renders are woken up when the limit is increased.
+	p.SetLimit(3)
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-acquired:
+			r()
+		case <-time.After(time.Second):
+			t.Fatal("expect the render acquired the slot after the limit increased")
+		}
+	}
+	release()
+}
+
+func TestRenderInPool(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}rendered{{ end }}`)
+	defer GetRenderPool().SetLimit(0)
+	GetRenderPool().SetLimit(1)
+
+	// The slot is released after rendering, so the renders one by one do not block.
+	for i := 0; i < 3; i++ {
+		got, err := tmpl.TempleText("msg", template.Data{}, log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "rendered" {
+			t.Fatalf("got %q, want rendered", got)
+		}
+	}
+
+	// A render waits while the only slot is held.
+	release := GetRenderPool().Acquire()
+	done := make(chan struct{})
+	go func() {
+		_, _ = tmpl.TempleText("msg", template.Data{}, log.NewNopLogger())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expect the render waiting for the slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expect the render finished after the slot released")
+	}
+}
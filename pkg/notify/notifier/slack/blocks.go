@@ -0,0 +1,349 @@
+package slack
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/go-kit/kit/log/level"
+	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"net/url"
+	"strings"
+	tmpltext "text/template"
+	"unicode/utf8"
+)
+
+const (
+	// The maximum number of the blocks in a message.
+	MaxBlocks = 50
+	// The maximum length of the text of a section or a context block.
+	maxTextLength = 3000
+	// The maximum length of the text of a header block.
+	maxHeaderLength = 150
+	// The maximum number of the elements in an actions block.
+	maxButtons = 25
+	// The maximum length of the text of a button.
+	maxButtonTextLength = 75
+)
+
+type block struct {
+	Type     string        `json:"type"`
+	Text     *textObject   `json:"text,omitempty"`
+	Elements []interface{} `json:"elements,omitempty"`
+}
+
+type textObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// The attachment holding the blocks of an alert, to show the accent color of the alert.
+type attachment struct {
+	Color  string            `json:"color"`
+	Blocks []json.RawMessage `json:"blocks"`
+}
+
+type button struct {
+	Type  string      `json:"type"`
+	Text  *textObject `json:"text"`
+	URL   string      `json:"url"`
+	Style string      `json:"style,omitempty"`
+}
+
+// The data of the url templates of the buttons.
+type buttonData struct {
+	template.Alert
+	// The url of alertmanager.
+	ExternalURL string
+}
+
+// SilenceURL returns the url of alertmanager to create a silence matching the labels of the alert,
+// it is empty if the url of alertmanager is unknown.
+func (d buttonData) SilenceURL() string {
+
+	if len(d.ExternalURL) == 0 {
+		return ""
+	}
+
+	var matchers []string
+	for _, p := range d.Labels.SortedPairs() {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", p.Name, p.Value))
+	}
+
+	return fmt.Sprintf("%s/#/silences/new?filter=%s", strings.TrimRight(d.ExternalURL, "/"),
+		url.QueryEscape("{"+strings.Join(matchers, ",")+"}"))
+}
+
+type buttonTemplate struct {
+	text  string
+	url   *tmpltext.Template
+	style string
+}
+
+// The Block Kit messages of the data, every message has at most `MaxBlocks` blocks.
+func (n *Notifier) blockMessages(s *config.Slack, data template.Data) ([]*slackRequest, error) {
+
+	if len(s.BlockKit.Template) > 0 {
+		return n.templateBlockMessages(s, data)
+	}
+
+	buttons, err := newButtonTemplates(n.buttons(s))
+	if err != nil {
+		return nil, err
+	}
+
+	// The blocks of each alert are kept in one message, a message has a header and the blocks of the alerts.
+	// The blocks of an alert with the accent color are in an attachment of the color.
+	type message struct {
+		blocks      []json.RawMessage
+		attachments []*attachment
+		size        int
+	}
+
+	var groups []*message
+	current := &message{}
+	for _, alert := range data.Alerts {
+		blocks, err := n.alertBlocks(s, data, alert, buttons)
+		if err != nil {
+			return nil, err
+		}
+
+		if current.size > 0 && current.size+len(blocks)+1 > MaxBlocks {
+			groups = append(groups, current)
+			current = &message{}
+		}
+
+		if color := notifier.AlertAccent(s.AlertAccent, alert).Color; len(color) > 0 {
+			current.attachments = append(current.attachments, &attachment{Color: color, Blocks: blocks})
+		} else {
+			current.blocks = append(current.blocks, blocks...)
+		}
+		current.size += len(blocks)
+	}
+	if current.size > 0 {
+		groups = append(groups, current)
+	}
+
+	var messages []*slackRequest
+	for i, m := range groups {
+		title := headerText(data)
+		if len(groups) > 1 {
+			title = fmt.Sprintf("%s (%d/%d)", title, i+1, len(groups))
+		}
+
+		header, err := marshalBlock(&block{
+			Type: "header",
+			Text: &textObject{Type: "plain_text", Text: truncate(title, maxHeaderLength)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, &slackRequest{
+			Channel:     s.Channel,
+			Text:        title,
+			Blocks:      append([]json.RawMessage{header}, m.blocks...),
+			Attachments: m.attachments,
+		})
+	}
+
+	return messages, nil
+}
+
+// The blocks rendered by the template of the receiver, they are split into the messages of `MaxBlocks` blocks.
+func (n *Notifier) templateBlockMessages(s *config.Slack, data template.Data) ([]*slackRequest, error) {
+
+	tmpl := n.template.WithAnnotationSection(s.AnnotationSection).WithOptionalTemplates(s.OptionalTemplates).WithAlertAccent(s.AlertAccent)
+	text, err := tmpl.TempleText(tmpl.SelectTemplate(data, "blocks", s.BlockKit.Template, n.logger), data, n.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &blocks); err != nil {
+		return nil, fmt.Errorf("the blocks rendered by template %s are not a json array, %s", s.BlockKit.Template, err.Error())
+	}
+
+	for i, b := range blocks {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(b, &typed); err != nil || len(typed.Type) == 0 {
+			return nil, fmt.Errorf("the block %d rendered by template %s has no type", i, s.BlockKit.Template)
+		}
+	}
+
+	var messages []*slackRequest
+	title := headerText(data)
+	for len(blocks) > 0 {
+		size := MaxBlocks
+		if size > len(blocks) {
+			size = len(blocks)
+		}
+
+		messages = append(messages, &slackRequest{
+			Channel: s.Channel,
+			Text:    title,
+			Blocks:  blocks[:size],
+		})
+		blocks = blocks[size:]
+	}
+
+	return messages, nil
+}
+
+// A section with the summary and the annotations, a context with the labels, and the buttons of the alert.
+func (n *Notifier) alertBlocks(s *config.Slack, data template.Data, alert template.Alert, buttons []*buttonTemplate) ([]json.RawMessage, error) {
+
+	status := ":fire:"
+	if alert.Status == string(model.AlertResolved) {
+		status = ":white_check_mark:"
+	}
+
+	summary := alert.Labels[model.AlertNameLabel]
+	if severity, ok := alert.Labels["severity"]; ok {
+		summary = fmt.Sprintf("%s [%s]", summary, severity)
+	}
+
+	annotations := notifier.AnnotationSection(alert.Annotations, s.AnnotationSection)
+	if annotations == nil {
+		annotations = alert.Annotations.SortedPairs()
+	}
+
+	if emoji := notifier.AlertAccent(s.AlertAccent, alert).Emoji; len(emoji) > 0 {
+		summary = fmt.Sprintf("%s %s", emoji, summary)
+	}
+
+	lines := []string{fmt.Sprintf("%s *%s*", status, escape(summary))}
+	for _, p := range annotations {
+		lines = append(lines, fmt.Sprintf("*%s*: %s", escape(p.Name), escape(p.Value)))
+	}
+
+	blocks := []*block{{
+		Type: "section",
+		Text: &textObject{Type: "mrkdwn", Text: truncate(strings.Join(lines, "\n"), maxTextLength)},
+	}}
+
+	var labels []string
+	for _, p := range alert.Labels.SortedPairs() {
+		if p.Name != model.AlertNameLabel {
+			labels = append(labels, fmt.Sprintf("`%s=%s`", escape(p.Name), escape(p.Value)))
+		}
+	}
+	if len(labels) > 0 {
+		blocks = append(blocks, &block{
+			Type:     "context",
+			Elements: []interface{}{&textObject{Type: "mrkdwn", Text: truncate(strings.Join(labels, " "), maxTextLength)}},
+		})
+	}
+
+	var elements []interface{}
+	for _, b := range buttons {
+		var buf bytes.Buffer
+		if err := b.url.Execute(&buf, buttonData{Alert: alert, ExternalURL: data.ExternalURL}); err != nil {
+			return nil, fmt.Errorf("execute the url of button %s error, %s", b.text, err.Error())
+		}
+
+		u := strings.TrimSpace(buf.String())
+		if len(u) == 0 || len(u) > maxTextLength {
+			continue
+		}
+		if _, err := url.ParseRequestURI(u); err != nil {
+			_ = level.Debug(n.logger).Log("msg", "SlackNotifier: skip the button of invalid url", "button", b.text, "url", u)
+			continue
+		}
+
+		elements = append(elements, &button{
+			Type:  "button",
+			Text:  &textObject{Type: "plain_text", Text: truncate(b.text, maxButtonTextLength)},
+			URL:   u,
+			Style: b.style,
+		})
+		if len(elements) >= maxButtons {
+			break
+		}
+	}
+	if len(elements) > 0 {
+		blocks = append(blocks, &block{Type: "actions", Elements: elements})
+	}
+
+	var res []json.RawMessage
+	for _, b := range blocks {
+		raw, err := marshalBlock(b)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, raw)
+	}
+
+	return res, nil
+}
+
+// The buttons of the receiver, or the default buttons.
+func (n *Notifier) buttons(s *config.Slack) []v1alpha1.SlackButton {
+
+	if len(s.BlockKit.Buttons) > 0 {
+		return s.BlockKit.Buttons
+	}
+
+	buttons := []v1alpha1.SlackButton{{Text: "Runbook", URL: "{{ .Annotations.runbook_url }}"}}
+	if l := notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, s.SourceLink); l.Enabled {
+		buttons = append(buttons, v1alpha1.SlackButton{Text: l.Text, URL: "{{ .GeneratorURL }}"})
+	}
+
+	return append(buttons, v1alpha1.SlackButton{Text: "Silence", URL: "{{ .SilenceURL }}"})
+}
+
+func newButtonTemplates(buttons []v1alpha1.SlackButton) ([]*buttonTemplate, error) {
+
+	var res []*buttonTemplate
+	for _, b := range buttons {
+		t, err := tmpltext.New(b.Text).Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Option("missingkey=zero").Parse(b.URL)
+		if err != nil {
+			return nil, fmt.Errorf("parse the url of button %s error, %s", b.Text, err.Error())
+		}
+
+		res = append(res, &buttonTemplate{text: b.Text, url: t, style: b.Style})
+	}
+
+	return res, nil
+}
+
+// The header of the messages, the status, the number of the alerts, and the group labels.
+func headerText(data template.Data) string {
+
+	var pairs []string
+	for _, p := range data.GroupLabels.SortedPairs() {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", p.Name, p.Value))
+	}
+
+	return fmt.Sprintf("[%s:%d] %s", strings.ToUpper(data.Status), len(data.Alerts), strings.Join(pairs, " "))
+}
+
+func marshalBlock(b *block) (json.RawMessage, error) {
+
+	bs, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// Escape the control characters of the mrkdwn of slack.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Truncate the text to at most `max` characters.
+func truncate(s string, max int) string {
+
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	return string([]rune(s)[:max-3]) + "..."
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/template"
 	"net/http"
 
@@ -29,11 +30,16 @@ type Notifier struct {
 	logger       log.Logger
 	template     *notifier.Template
 	templateName string
+	// The mode to generate message with the status templates.
+	statusTemplateMode string
 }
 
 type slackRequest struct {
 	Channel string `json:"channel"`
-	Text    string `json:"text"`
+	// The text is the fallback of the notifications if the blocks are set.
+	Text        string            `json:"text"`
+	Blocks      []json.RawMessage `json:"blocks,omitempty"`
+	Attachments []*attachment     `json:"attachments,omitempty"`
 }
 
 type slackResponse struct {
@@ -50,7 +56,7 @@ func NewSlackNotifier(logger log.Logger, receivers []config.Receiver, notifierCf
 	}
 	tmpl, err := notifier.NewTemplate(path)
 	if err != nil {
-		_ = level.Error(logger).Log("msg", "SlackNotifier: get template error", "error", err.Error())
+		notifier.ReportTemplateError(logger, notifierCfg, "Slack", receivers, err)
 		return nil
 	}
 
@@ -73,6 +79,8 @@ func NewSlackNotifier(logger log.Logger, receivers []config.Receiver, notifierCf
 		} else if opts.Global != nil && len(opts.Global.Template) > 0 {
 			n.templateName = opts.Global.Template
 		}
+
+		n.statusTemplateMode = opts.Slack.StatusTemplateMode
 	}
 
 	for _, r := range receivers {
@@ -82,7 +90,7 @@ func NewSlackNotifier(logger log.Logger, receivers []config.Receiver, notifierCf
 		}
 
 		if receiver.SlackConfig == nil {
-			_ = level.Warn(logger).Log("msg", "SlackNotifier: ignore receiver because of empty config")
+			notifier.ReportConfigError(logger, notifierCfg, "Slack", receiver, fmt.Errorf("no config is found"))
 			continue
 		}
 
@@ -94,73 +102,113 @@ func NewSlackNotifier(logger log.Logger, receivers []config.Receiver, notifierCf
 
 func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 
-	msg, err := n.template.TempleText(n.templateName, data, n.logger)
-	if err != nil {
-		_ = level.Error(n.logger).Log("msg", "SlackNotifier: generate message error", "error", err.Error())
-		return []error{err}
-	}
+	data = notifier.CollapseHighCardinality(n.notifierCfg.ReceiverOpts, data)
 
-	send := func(c *config.Slack) error {
+	send := func(c *config.Slack, sr *slackRequest) (err error) {
 
 		start := time.Now()
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "SlackNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Slack", time.Since(start), err)
 		}()
 
-		sr := &slackRequest{
-			Channel: c.Channel,
-			Text:    msg,
-		}
-
-		var buf bytes.Buffer
-		if err := json.NewEncoder(&buf).Encode(sr); err != nil {
-			_ = level.Error(n.logger).Log("msg", "SlackNotifier: encode message error", "error", err.Error())
+		if err := notifier.GetTargetLimiter().Wait(ctx, notifier.TargetKey("Slack", c.Channel), notifier.TargetThrottle(n.notifierCfg.ReceiverOpts)); err != nil {
+			_ = level.Error(n.logger).Log("msg", "SlackNotifier: message dropped because of flow control", "channel", c.Channel, "error", err.Error())
 			return err
 		}
 
-		request, err := http.NewRequest(http.MethodPost, URL, &buf)
-		if err != nil {
-			return err
-		}
-		request.Header.Set("Content-Type", "application/json")
-
 		token, err := n.notifierCfg.GetSecretData(c.GetNamespace(), c.SlackConfig.Token)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "SlackNotifier: get token secret", "error", err.Error())
 			return err
 		}
 
-		request.Header.Set("Authorization", "Bearer "+token)
-
-		body, err := notifier.DoHttpRequest(ctx, nil, request.WithContext(ctx))
-		if err != nil {
-			_ = level.Error(n.logger).Log("msg", "SlackNotifier: do http error", "error", err)
-			return err
-		}
-
-		var slResp slackResponse
-		if err := json.Unmarshal(body, &slResp); err != nil {
-			_ = level.Error(n.logger).Log("msg", "SlackNotifier: decode response body error", "error", err)
+		if err := n.post(ctx, notifier.GetDefaultClient("Slack"), URL, token, sr); err != nil {
 			return err
 		}
 
-		if !slResp.OK {
-			_ = level.Error(n.logger).Log("msg", "SlackNotifier: slack error", "error", slResp.Error)
-			return fmt.Errorf("%s", slResp.Error)
-		}
-
 		_ = level.Debug(n.logger).Log("msg", "SlackNotifier: send message", "channel", c.Channel)
 
 		return nil
 	}
 
+	var errs []error
 	group := async.NewGroup(ctx)
 	for _, slack := range n.slack {
 		s := slack
-		group.Add(func(stopCh chan interface{}) {
-			stopCh <- send(s)
-		})
+		if s.BlockKit != nil && s.BlockKit.Enabled {
+			messages, err := n.blockMessages(s, data)
+			if err != nil {
+				_ = level.Error(n.logger).Log("msg", "SlackNotifier: generate blocks error", "error", err.Error())
+				errs = append(errs, err)
+				continue
+			}
+
+			for _, m := range messages {
+				sr := m
+				group.Add(func(stopCh chan interface{}) {
+					stopCh <- send(s, sr)
+				})
+			}
+			continue
+		}
+
+		tmpl := n.template.WithAnnotationSection(s.AnnotationSection).WithOptionalTemplates(s.OptionalTemplates).WithAlertAccent(s.AlertAccent)
+		messages, err := tmpl.TempleTextByStatus(tmpl.SelectTemplate(data, "text", n.templateName, n.logger), data, n.statusTemplateMode, n.logger)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "SlackNotifier: generate message error", "error", err.Error())
+			errs = append(errs, err)
+			continue
+		}
+
+		links := notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, s.SourceLink).Markdown(data)
+		for i, m := range messages {
+			msg := m
+			// The source links are added to the last message.
+			if i == len(messages)-1 {
+				msg += links
+			}
+			group.Add(func(stopCh chan interface{}) {
+				stopCh <- send(s, &slackRequest{Channel: s.Channel, Text: msg})
+			})
+		}
+	}
+
+	return append(errs, group.Wait()...)
+}
+
+// Post the message to the url with the token, the error of the response is returned if slack does not accept it.
+func (n *Notifier) post(ctx context.Context, client *http.Client, u, token string, sr *slackRequest) error {
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(sr); err != nil {
+		_ = level.Error(n.logger).Log("msg", "SlackNotifier: encode message error", "error", err.Error())
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, u, &buf)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	body, err := notifier.DoHttpRequest(ctx, client, request.WithContext(ctx))
+	if err != nil {
+		_ = level.Error(n.logger).Log("msg", "SlackNotifier: do http error", "error", err)
+		return err
+	}
+
+	var slResp slackResponse
+	if err := json.Unmarshal(body, &slResp); err != nil {
+		_ = level.Error(n.logger).Log("msg", "SlackNotifier: decode response body error", "error", err)
+		return err
+	}
+
+	if !slResp.OK {
+		_ = level.Error(n.logger).Log("msg", "SlackNotifier: slack error", "error", slResp.Error)
+		return fmt.Errorf("%s", slResp.Error)
 	}
 
-	return group.Wait()
+	return nil
 }
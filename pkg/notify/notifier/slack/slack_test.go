@@ -0,0 +1,291 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	v1 "k8s.io/api/core/v1"
+)
+
+// The payload received by the fake slack, only the fields checked by the tests are decoded.
+type payload struct {
+	Channel     string         `json:"channel"`
+	Text        string         `json:"text"`
+	Blocks      []payloadBlock `json:"blocks"`
+	Attachments []struct {
+		Color  string         `json:"color"`
+		Blocks []payloadBlock `json:"blocks"`
+	} `json:"attachments"`
+}
+
+type payloadBlock struct {
+	Type string `json:"type"`
+	Text *struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"text"`
+	Elements []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"elements"`
+}
+
+// A fake chat.postMessage endpoint replying the body, the payloads are recorded.
+func slackServer(t *testing.T, reply string) (*httptest.Server, func() []*payload) {
+
+	var mutex sync.Mutex
+	var payloads []*payload
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want the bearer token", got)
+		}
+
+		p := &payload{}
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			t.Error(err)
+		}
+		mutex.Lock()
+		payloads = append(payloads, p)
+		mutex.Unlock()
+
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(s.Close)
+
+	return s, func() []*payload {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]*payload(nil), payloads...)
+	}
+}
+
+func newTestReceiver(blockKit *v1alpha1.SlackBlockKit) *config.Slack {
+
+	r := config.NewSlackReceiver().(*config.Slack)
+	r.Channel = "#alerts"
+	r.BlockKit = blockKit
+	r.SlackConfig = &config.SlackConfig{Token: &v1.SecretKeySelector{Key: "token"}}
+
+	return r
+}
+
+// A notifier of the receiver with a template file defining the blocks template `slack.blocks`.
+func newTestNotifier(t *testing.T, receiver *config.Slack) *Notifier {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(`{{ define "slack.blocks" }}`+
+		`[{"type":"section","text":{"type":"mrkdwn","text":"{{ len .Alerts }} alerts"}}]{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TemplateFiles: []string{file}}}}
+	n, ok := NewSlackNotifier(log.NewNopLogger(), []config.Receiver{receiver}, cfg).(*Notifier)
+	if !ok || n == nil {
+		t.Fatal("create the slack notifier failed")
+	}
+
+	return n
+}
+
+func testData(alerts int) template.Data {
+
+	data := template.Data{
+		Status:      "firing",
+		GroupLabels: template.KV{"alertname": "KubePodCrashLooping"},
+		ExternalURL: "http://alertmanager:9093",
+	}
+	for i := 0; i < alerts; i++ {
+		data.Alerts = append(data.Alerts, template.Alert{
+			Status:      "firing",
+			Labels:      template.KV{"alertname": "KubePodCrashLooping", "pod": fmt.Sprintf("web-%d", i)},
+			Annotations: template.KV{"message": "crash looping", "runbook_url": "https://runbook/crash"},
+		})
+	}
+
+	return data
+}
+
+// The types of the blocks.
+func blockTypes(blocks []payloadBlock) []string {
+
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b.Type)
+	}
+
+	return types
+}
+
+func TestBlockKitPayload(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		blockKit *v1alpha1.SlackBlockKit
+		alerts   int
+		// The texts and the block types of the payloads.
+		texts []string
+		types [][]string
+	}{
+		{
+			name:     "default layout",
+			blockKit: &v1alpha1.SlackBlockKit{Enabled: true},
+			alerts:   1,
+			texts:    []string{"[FIRING:1] alertname=KubePodCrashLooping"},
+			types:    [][]string{{"header", "section", "context", "actions"}},
+		},
+		{
+			// Each alert has 3 blocks, 16 alerts and the header fill a message.
+			name:     "split into messages",
+			blockKit: &v1alpha1.SlackBlockKit{Enabled: true},
+			alerts:   20,
+			texts:    []string{"[FIRING:20] alertname=KubePodCrashLooping (1/2)", "[FIRING:20] alertname=KubePodCrashLooping (2/2)"},
+			types: [][]string{
+				append([]string{"header"}, repeat([]string{"section", "context", "actions"}, 16)...),
+				append([]string{"header"}, repeat([]string{"section", "context", "actions"}, 4)...),
+			},
+		},
+		{
+			name:     "template layout",
+			blockKit: &v1alpha1.SlackBlockKit{Enabled: true, Template: "slack.blocks"},
+			alerts:   2,
+			texts:    []string{"[FIRING:2] alertname=KubePodCrashLooping"},
+			types:    [][]string{{"section"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, payloads := slackServer(t, `{"ok":true}`)
+			receiver := newTestReceiver(tt.blockKit)
+			n := newTestNotifier(t, receiver)
+
+			messages, err := n.blockMessages(receiver, testData(tt.alerts))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range messages {
+				if err := n.post(context.Background(), s.Client(), s.URL, "token", m); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := payloads()
+			if len(got) != len(tt.texts) {
+				t.Fatalf("expect %d messages, got %d", len(tt.texts), len(got))
+			}
+			for i, p := range got {
+				if p.Channel != "#alerts" || p.Text != tt.texts[i] {
+					t.Fatalf("message %d: channel = %s, text = %s", i, p.Channel, p.Text)
+				}
+				if types := blockTypes(p.Blocks); !reflect.DeepEqual(types, tt.types[i]) {
+					t.Fatalf("message %d: blocks = %v, want %v", i, types, tt.types[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBlockKitButtons(t *testing.T) {
+
+	s, payloads := slackServer(t, `{"ok":true}`)
+	receiver := newTestReceiver(&v1alpha1.SlackBlockKit{Enabled: true})
+	n := newTestNotifier(t, receiver)
+
+	messages, err := n.blockMessages(receiver, testData(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.post(context.Background(), s.Client(), s.URL, "token", messages[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	p := payloads()[0]
+	actions := p.Blocks[len(p.Blocks)-1]
+	var urls []string
+	for _, e := range actions.Elements {
+		urls = append(urls, e.URL)
+	}
+	// The source button is omitted as the alert has no GeneratorURL.
+	want := []string{
+		"https://runbook/crash",
+		"http://alertmanager:9093/#/silences/new?filter=%7Balertname%3D%22KubePodCrashLooping%22%2Cpod%3D%22web-0%22%7D",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Fatalf("button urls = %v, want %v", urls, want)
+	}
+	if section := p.Blocks[1].Text; section == nil || !strings.Contains(section.Text, "*message*: crash looping") {
+		t.Fatalf("unexpected section %+v", section)
+	}
+}
+
+func TestBlockKitAccent(t *testing.T) {
+
+	s, payloads := slackServer(t, `{"ok":true}`)
+	receiver := newTestReceiver(&v1alpha1.SlackBlockKit{Enabled: true})
+	receiver.AlertAccent = &v1alpha1.AlertAccent{
+		Enabled:    true,
+		Severities: map[string]v1alpha1.Accent{"critical": {Color: "#E01E5A", Emoji: "🟥"}},
+	}
+	n := newTestNotifier(t, receiver)
+
+	data := testData(1)
+	data.Alerts[0].Labels["severity"] = "critical"
+	messages, err := n.blockMessages(receiver, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.post(context.Background(), s.Client(), s.URL, "token", messages[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The blocks of the alert are in an attachment of the accent color, the header is not.
+	p := payloads()[0]
+	if types := blockTypes(p.Blocks); !reflect.DeepEqual(types, []string{"header"}) {
+		t.Fatalf("blocks = %v, want the header only", types)
+	}
+	if len(p.Attachments) != 1 || p.Attachments[0].Color != "#E01E5A" {
+		t.Fatalf("unexpected attachments %+v", p.Attachments)
+	}
+	blocks := p.Attachments[0].Blocks
+	if types := blockTypes(blocks); !reflect.DeepEqual(types, []string{"section", "context", "actions"}) {
+		t.Fatalf("attachment blocks = %v", types)
+	}
+	if text := blocks[0].Text.Text; !strings.HasPrefix(text, ":fire: *🟥 KubePodCrashLooping [critical]*") {
+		t.Fatalf("expect the emoji before the summary, got %q", text)
+	}
+}
+
+func TestPostError(t *testing.T) {
+
+	s, _ := slackServer(t, `{"ok":false,"error":"invalid_blocks"}`)
+	n := &Notifier{logger: log.NewNopLogger()}
+
+	err := n.post(context.Background(), s.Client(), s.URL, "token", &slackRequest{Channel: "#alerts", Text: "text"})
+	if err == nil || err.Error() != "invalid_blocks" {
+		t.Fatalf("expect the error of slack, got %v", err)
+	}
+}
+
+// The slice repeated n times.
+func repeat(s []string, n int) []string {
+
+	var res []string
+	for i := 0; i < n; i++ {
+		res = append(res, s...)
+	}
+
+	return res
+}
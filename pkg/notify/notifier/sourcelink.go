@@ -0,0 +1,115 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	"html"
+	"strings"
+)
+
+const (
+	DefaultSourceLinkText = "View in Prometheus"
+	// The maximum number of the source links in a message, the alerts of a group usually share a few links.
+	MaxSourceLinks = 5
+)
+
+// SourceLink decides whether and how to add the links to the source of the alerts to a message.
+type SourceLink struct {
+	Enabled bool
+	Text    string
+}
+
+// NewSourceLink creates the SourceLink of a receiver, `receiver` overrides the global option if it is not nil.
+func NewSourceLink(opts *v1alpha1.Options, receiver *bool) *SourceLink {
+
+	l := &SourceLink{
+		Text: DefaultSourceLinkText,
+	}
+
+	if opts != nil && opts.Global != nil && opts.Global.SourceLink != nil {
+		l.Enabled = opts.Global.SourceLink.Enabled
+		if len(opts.Global.SourceLink.Text) > 0 {
+			l.Text = opts.Global.SourceLink.Text
+		}
+	}
+
+	if receiver != nil {
+		l.Enabled = *receiver
+	}
+
+	return l
+}
+
+// Links returns the distinct GeneratorURLs of the alerts, the alerts without GeneratorURL are omitted.
+func (l *SourceLink) Links(data template.Data) []string {
+
+	if l == nil || !l.Enabled {
+		return nil
+	}
+
+	var links []string
+	for _, alert := range data.Alerts {
+		if len(alert.GeneratorURL) == 0 || config.StringIn(links, alert.GeneratorURL) {
+			continue
+		}
+
+		links = append(links, alert.GeneratorURL)
+		if len(links) >= MaxSourceLinks {
+			break
+		}
+	}
+
+	return links
+}
+
+// PlainText generates the links in plain text, it is empty if there is no link.
+func (l *SourceLink) PlainText(data template.Data) string {
+
+	s := ""
+	for _, link := range l.Links(data) {
+		s = fmt.Sprintf("%s\n%s: %s", s, l.Text, link)
+	}
+
+	if len(s) == 0 {
+		return s
+	}
+
+	return "\n" + s
+}
+
+// Markdown generates the links in the markdown of slack, it is empty if there is no link.
+func (l *SourceLink) Markdown(data template.Data) string {
+
+	s := ""
+	for _, link := range l.Links(data) {
+		s = fmt.Sprintf("%s\n<%s|%s>", s, link, l.Text)
+	}
+
+	if len(s) == 0 {
+		return s
+	}
+
+	return "\n" + s
+}
+
+// AppendHTML adds the links to the html as buttons, the links are inserted before the end of the body if it exists.
+func (l *SourceLink) AppendHTML(body string, data template.Data) string {
+
+	s := ""
+	for _, link := range l.Links(data) {
+		s = fmt.Sprintf(`%s<p style="margin: 10px 0;"><a href="%s" style="display: inline-block; padding: 8px 16px; color: #fff; background-color: #348eda; border-radius: 3px; text-decoration: none;">%s</a></p>`,
+			s, html.EscapeString(link), html.EscapeString(l.Text))
+	}
+
+	if len(s) == 0 {
+		return body
+	}
+
+	if i := strings.LastIndex(body, "</body>"); i >= 0 {
+		return body[:i] + s + body[i:]
+	}
+
+	return body + s
+}
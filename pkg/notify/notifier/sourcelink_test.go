@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func sourceLinkData(urls ...string) template.Data {
+
+	data := template.Data{}
+	for _, u := range urls {
+		data.Alerts = append(data.Alerts, template.Alert{Status: "firing", GeneratorURL: u})
+	}
+
+	return data
+}
+
+func TestNewSourceLink(t *testing.T) {
+
+	enabled, disabled := true, false
+	global := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{SourceLink: &v1alpha1.SourceLink{Enabled: true, Text: "Graph"}}}
+
+	tests := []struct {
+		name        string
+		opts        *v1alpha1.Options
+		receiver    *bool
+		wantEnabled bool
+		wantText    string
+	}{
+		{"disabled by default", nil, nil, false, DefaultSourceLinkText},
+		{"global option", global, nil, true, "Graph"},
+		{"receiver overrides global", global, &disabled, false, "Graph"},
+		{"receiver enables", &v1alpha1.Options{}, &enabled, true, DefaultSourceLinkText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewSourceLink(tt.opts, tt.receiver)
+			if l.Enabled != tt.wantEnabled || l.Text != tt.wantText {
+				t.Fatalf("source link = %+v, want enabled %v with text %q", l, tt.wantEnabled, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestSourceLinks(t *testing.T) {
+
+	var many []string
+	for i := 0; i < MaxSourceLinks+2; i++ {
+		many = append(many, fmt.Sprintf("http://prometheus/graph?g0.expr=%d", i))
+	}
+
+	tests := []struct {
+		name string
+		link *SourceLink
+		data template.Data
+		want []string
+	}{
+		{"disabled", &SourceLink{}, sourceLinkData("http://prometheus/a"), nil},
+		{"nil", nil, sourceLinkData("http://prometheus/a"), nil},
+		{"distinct links", &SourceLink{Enabled: true}, sourceLinkData("http://prometheus/a", "", "http://prometheus/a", "http://prometheus/b"),
+			[]string{"http://prometheus/a", "http://prometheus/b"}},
+		{"links capped", &SourceLink{Enabled: true}, sourceLinkData(many...), many[:MaxSourceLinks]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.link.Links(tt.data)
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Fatalf("Links() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceLinkFormats(t *testing.T) {
+
+	l := &SourceLink{Enabled: true, Text: "View"}
+	data := sourceLinkData(`http://prometheus/graph?a=1&b="2"`)
+
+	if got, want := l.PlainText(data), "\n\nView: http://prometheus/graph?a=1&b=\"2\""; got != want {
+		t.Fatalf("PlainText() = %q, want %q", got, want)
+	}
+	if got, want := l.Markdown(data), "\n\n<http://prometheus/graph?a=1&b=\"2\"|View>"; got != want {
+		t.Fatalf("Markdown() = %q, want %q", got, want)
+	}
+
+	body := l.AppendHTML("<html><body><p>alerts</p></body></html>", data)
+	if !strings.Contains(body, `href="http://prometheus/graph?a=1&amp;b=&#34;2&#34;"`) {
+		t.Fatalf("expect the link escaped in the html, got %s", body)
+	}
+	if !strings.HasSuffix(body, "</a></p></body></html>") {
+		t.Fatalf("expect the link before the end of the body, got %s", body)
+	}
+
+	// Nothing is added without the links.
+	if got := l.PlainText(sourceLinkData("")); got != "" {
+		t.Fatalf("PlainText() = %q, want empty", got)
+	}
+	if got := l.AppendHTML("<p>alerts</p>", sourceLinkData("")); got != "<p>alerts</p>" {
+		t.Fatalf("AppendHTML() = %q, want the body unchanged", got)
+	}
+}
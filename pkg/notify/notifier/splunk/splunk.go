@@ -0,0 +1,317 @@
+package splunk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/async"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultSendTimeout = time.Second * 5
+	DefaultSourceType  = "notification-manager"
+	DefaultEventPath   = "/services/collector/event"
+	EventModeAlert     = "alert"
+	EventModeGroup     = "group"
+	// The maximum number of events sent in one request.
+	MaxEventsPerRequest = 100
+)
+
+// The http clients are reused across notifications, the key is the md5 of the config and the timeout.
+var clients = notifier.NewClientCache(notifier.DefaultClientCacheSize)
+
+// HECError means the HTTP Event Collector rejected the events, `Code` is the status code in the HEC response.
+type HECError struct {
+	StatusCode int
+	Code       int
+	Text       string
+}
+
+func (e *HECError) Error() string {
+	return fmt.Sprintf("splunk hec error, status: %d, code: %d, text: %s", e.StatusCode, e.Code, e.Text)
+}
+
+type Notifier struct {
+	notifierCfg *config.Config
+	splunk      []*config.Splunk
+	timeout     time.Duration
+	logger      log.Logger
+	eventMode   string
+}
+
+// The event envelope of the HTTP Event Collector.
+type event struct {
+	Time       float64     `json:"time"`
+	Index      string      `json:"index,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+type alertEvent struct {
+	Receiver string `json:"receiver,omitempty"`
+	template.Alert
+}
+
+type hecResponse struct {
+	Text string `json:"text"`
+	Code int    `json:"code"`
+}
+
+func NewSplunkNotifier(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier {
+
+	n := &Notifier{
+		notifierCfg: notifierCfg,
+		timeout:     DefaultSendTimeout,
+		logger:      logger,
+		eventMode:   EventModeAlert,
+	}
+
+	opts := notifierCfg.ReceiverOpts
+	if opts != nil && opts.Splunk != nil {
+
+		if opts.Splunk.NotificationTimeout != nil {
+			n.timeout = time.Second * time.Duration(*opts.Splunk.NotificationTimeout)
+		}
+
+		if opts.Splunk.EventMode == EventModeGroup {
+			n.eventMode = EventModeGroup
+		}
+	}
+
+	for _, r := range receivers {
+		receiver, ok := r.(*config.Splunk)
+		if !ok || receiver == nil {
+			continue
+		}
+
+		if receiver.SplunkConfig == nil {
+			notifier.ReportConfigError(logger, notifierCfg, "Splunk", receiver, fmt.Errorf("no config is found"))
+			continue
+		}
+
+		n.splunk = append(n.splunk, receiver)
+	}
+
+	return n
+}
+
+func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
+
+	send := func(s *config.Splunk) (err error) {
+
+		start := time.Now()
+		defer func() {
+			_ = level.Debug(n.logger).Log("msg", "SplunkNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Splunk", time.Since(start), err)
+		}()
+
+		u, err := eventURL(s.SplunkConfig.URL)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "SplunkNotifier: parse url error", "error", err.Error())
+			return err
+		}
+
+		token, err := n.notifierCfg.GetSecretData(s.GetNamespace(), s.SplunkConfig.Token)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "SplunkNotifier: get token error", "error", err.Error())
+			return err
+		}
+
+		client, err := n.getClient(s)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "SplunkNotifier: get client error", "error", err.Error())
+			return err
+		}
+
+		events := n.events(s.SplunkConfig, data, start)
+		if err := n.sendEvents(ctx, client, u, token, events); err != nil {
+			return err
+		}
+
+		_ = level.Debug(n.logger).Log("msg", "SplunkNotifier: send message", "to", u, "events", len(events))
+		return nil
+	}
+
+	group := async.NewGroup(ctx)
+	for _, splunk := range n.splunk {
+		s := splunk
+		group.Add(func(stopCh chan interface{}) {
+			stopCh <- send(s)
+		})
+	}
+
+	return group.Wait()
+}
+
+// Send the events in batches of at most MaxEventsPerRequest events.
+func (n *Notifier) sendEvents(ctx context.Context, client *http.Client, u, token string, events []*event) error {
+
+	for i := 0; i < len(events); i += MaxEventsPerRequest {
+		end := i + MaxEventsPerRequest
+		if end > len(events) {
+			end = len(events)
+		}
+
+		body, err := encode(events[i:end])
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "SplunkNotifier: encode events error", "error", err.Error())
+			return err
+		}
+
+		if err := n.send(ctx, client, u, token, body); err != nil {
+			_ = level.Error(n.logger).Log("msg", "SplunkNotifier: send events error", "url", u, "error", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Send the events to the HTTP Event Collector, the response is parsed to detect the failure.
+func (n *Notifier) send(ctx context.Context, client *http.Client, u, token string, body []byte) error {
+
+	request, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Splunk "+token)
+
+	resp, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	hecResp := &hecResponse{}
+	if err := json.Unmarshal(respBody, hecResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &HECError{StatusCode: resp.StatusCode, Code: -1, Text: string(respBody)}
+		}
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK || hecResp.Code != 0 {
+		return &HECError{StatusCode: resp.StatusCode, Code: hecResp.Code, Text: hecResp.Text}
+	}
+
+	return nil
+}
+
+// Wrap the alerts in the event envelopes, every alert is an event in `alert` mode,
+// and the whole notification is an event in `group` mode.
+func (n *Notifier) events(c *config.SplunkConfig, data template.Data, now time.Time) []*event {
+
+	sourceType := c.SourceType
+	if len(sourceType) == 0 {
+		sourceType = DefaultSourceType
+	}
+
+	newEvent := func(t time.Time, e interface{}) *event {
+		return &event{
+			Time:       float64(t.UnixNano()/int64(time.Millisecond)) / 1000,
+			Index:      c.Index,
+			Source:     c.Source,
+			SourceType: sourceType,
+			Event:      e,
+		}
+	}
+
+	var events []*event
+	if n.eventMode == EventModeGroup {
+		events = append(events, newEvent(now, data))
+		return events
+	}
+
+	for _, alert := range data.Alerts {
+		t := alert.StartsAt
+		if alert.Status == "resolved" && !alert.EndsAt.IsZero() {
+			t = alert.EndsAt
+		}
+		if t.IsZero() {
+			t = now
+		}
+
+		events = append(events, newEvent(t, &alertEvent{
+			Receiver: data.Receiver,
+			Alert:    alert,
+		}))
+	}
+
+	return events
+}
+
+// Get the http client of the receiver, the client will be reused if the config is not changed.
+func (n *Notifier) getClient(s *config.Splunk) (*http.Client, error) {
+
+	key, err := notifier.Md5key(s.SplunkConfig)
+	if err != nil {
+		return nil, err
+	}
+	key = fmt.Sprintf("%s/%s/%s", s.GetNamespace(), key, n.timeout)
+
+	return clients.Get(key, func() (*http.Client, error) {
+
+		c := &v1alpha1.HTTPClientConfig{TLSConfig: s.SplunkConfig.TLSConfig}
+		transport, err := notifier.NewTransport(n.notifierCfg, "Splunk", s.GetNamespace(), s.SplunkConfig.URL, c)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Client{
+			Transport: transport,
+			Timeout:   n.timeout,
+		}, nil
+	})
+}
+
+// HEC accepts multiple events in one request by concatenating them.
+func encode(events []*event) ([]byte, error) {
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := encoder.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func eventURL(u string) (string, error) {
+
+	hec, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+
+	if len(hec.Path) == 0 || hec.Path == "/" {
+		hec.Path = DefaultEventPath
+	}
+
+	return hec.String(), nil
+}
@@ -0,0 +1,226 @@
+package splunk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// A fake HTTP Event Collector replying the status and the body, the requests are recorded.
+func hecServer(t *testing.T, status int, body string) (*httptest.Server, *[]*http.Request, *[][]byte) {
+
+	var requests []*http.Request
+	var bodies [][]byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, r)
+		bodies = append(bodies, bs)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+
+	return s, &requests, &bodies
+}
+
+func TestSend(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		// The HEC error expected, nil if the events are acknowledged.
+		want *HECError
+		// Whether an error which is not a HEC error is expected.
+		wantOtherErr bool
+	}{
+		{name: "acknowledged", status: http.StatusOK, body: `{"text":"Success","code":0}`},
+		{
+			name:   "invalid token",
+			status: http.StatusForbidden,
+			body:   `{"text":"Invalid token","code":4}`,
+			want:   &HECError{StatusCode: http.StatusForbidden, Code: 4, Text: "Invalid token"},
+		},
+		{
+			name:   "invalid data format",
+			status: http.StatusBadRequest,
+			body:   `{"text":"Invalid data format","code":6,"invalid-event-number":0}`,
+			want:   &HECError{StatusCode: http.StatusBadRequest, Code: 6, Text: "Invalid data format"},
+		},
+		{
+			name:   "error code with status ok",
+			status: http.StatusOK,
+			body:   `{"text":"Server is busy","code":9}`,
+			want:   &HECError{StatusCode: http.StatusOK, Code: 9, Text: "Server is busy"},
+		},
+		{
+			name:   "non-json error response",
+			status: http.StatusServiceUnavailable,
+			body:   "service unavailable",
+			want:   &HECError{StatusCode: http.StatusServiceUnavailable, Code: -1, Text: "service unavailable"},
+		},
+		{name: "non-json response with status ok", status: http.StatusOK, body: "ok", wantOtherErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, requests, _ := hecServer(t, tt.status, tt.body)
+
+			n := &Notifier{logger: log.NewNopLogger()}
+			err := n.send(context.Background(), s.Client(), s.URL+DefaultEventPath, "token", []byte(`{"event":"test"}`))
+
+			if len(*requests) != 1 {
+				t.Fatalf("expect 1 request, got %d", len(*requests))
+			}
+			if got := (*requests)[0].Header.Get("Authorization"); got != "Splunk token" {
+				t.Fatalf("Authorization = %q, want the HEC token", got)
+			}
+
+			switch {
+			case tt.wantOtherErr:
+				if _, ok := err.(*HECError); err == nil || ok {
+					t.Fatalf("expect a decoding error, got %v", err)
+				}
+			case tt.want == nil:
+				if err != nil {
+					t.Fatal(err)
+				}
+			default:
+				e, ok := err.(*HECError)
+				if !ok {
+					t.Fatalf("expect a HEC error, got %v", err)
+				}
+				if *e != *tt.want {
+					t.Fatalf("error = %+v, want %+v", e, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func testData(alerts int) template.Data {
+
+	data := template.Data{Receiver: "prometheus", Status: "firing"}
+	start := time.Unix(1600000000, 0)
+	for i := 0; i < alerts; i++ {
+		data.Alerts = append(data.Alerts, template.Alert{
+			Status:   "firing",
+			Labels:   template.KV{"alertname": "KubePodCrashLooping", "index": string(rune('a' + i%26))},
+			StartsAt: start.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	return data
+}
+
+func TestEvents(t *testing.T) {
+
+	c := &config.SplunkConfig{Index: "alerts", Source: "cluster-a"}
+	now := time.Unix(1600001000, 0)
+
+	resolved := testData(2)
+	resolved.Alerts[1].Status = "resolved"
+	resolved.Alerts[1].EndsAt = time.Unix(1600000500, 0)
+
+	tests := []struct {
+		name      string
+		eventMode string
+		data      template.Data
+		wantTimes []float64
+	}{
+		{"alert mode", EventModeAlert, testData(2), []float64{1600000000, 1600000001}},
+		{"resolved alert at the end time", EventModeAlert, resolved, []float64{1600000000, 1600000500}},
+		{"group mode", EventModeGroup, testData(3), []float64{1600001000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Notifier{eventMode: tt.eventMode}
+			events := n.events(c, tt.data, now)
+			if len(events) != len(tt.wantTimes) {
+				t.Fatalf("expect %d events, got %d", len(tt.wantTimes), len(events))
+			}
+
+			for i, e := range events {
+				if e.Time != tt.wantTimes[i] {
+					t.Fatalf("event %d: time = %f, want %f", i, e.Time, tt.wantTimes[i])
+				}
+				if e.Index != "alerts" || e.Source != "cluster-a" || e.SourceType != DefaultSourceType {
+					t.Fatalf("unexpected envelope %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestSendEventsInBatches(t *testing.T) {
+
+	s, requests, bodies := hecServer(t, http.StatusOK, `{"text":"Success","code":0}`)
+
+	n := &Notifier{logger: log.NewNopLogger(), eventMode: EventModeAlert}
+	events := n.events(&config.SplunkConfig{}, testData(MaxEventsPerRequest+1), time.Now())
+	if err := n.sendEvents(context.Background(), s.Client(), s.URL, "token", events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("expect 2 requests, got %d", len(*requests))
+	}
+
+	// The events are concatenated in a request.
+	var counts []int
+	for _, body := range *bodies {
+		count := 0
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			var e map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := e["event"]; !ok {
+				t.Fatalf("expect the alert in the event envelope, got %s", scanner.Text())
+			}
+			count++
+		}
+		counts = append(counts, count)
+	}
+	if counts[0] != MaxEventsPerRequest || counts[1] != 1 {
+		t.Fatalf("expect the events split into %d and 1, got %v", MaxEventsPerRequest, counts)
+	}
+}
+
+func TestEventURL(t *testing.T) {
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://splunk:8088", "https://splunk:8088" + DefaultEventPath},
+		{"https://splunk:8088/", "https://splunk:8088" + DefaultEventPath},
+		{"https://splunk:8088/services/collector/raw", "https://splunk:8088/services/collector/raw"},
+	}
+
+	for _, tt := range tests {
+		got, err := eventURL(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("eventURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
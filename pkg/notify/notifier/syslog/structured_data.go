@@ -0,0 +1,101 @@
+package syslog
+
+import (
+	"fmt"
+	"github.com/prometheus/alertmanager/template"
+	"strings"
+)
+
+const (
+	// The SD-ID of the alert labels, in form of name@<private enterprise number>.
+	DefaultSDID = "alert@32473"
+	// The maximum number of the SD-PARAMs of an alert, the excess labels are dropped.
+	DefaultMaxParams = 32
+	// The STRUCTURED-DATA of the message without structured data.
+	NilValue = "-"
+	// The maximum length of an SD-NAME.
+	maxNameLength = 32
+)
+
+// StructuredData encodes the labels of the alerts as the RFC 5424 STRUCTURED-DATA, such as
+// `[alert@32473 alertname="KubePodCrashLooping" namespace="default"]`, so the SIEMs can parse the labels as fields.
+type StructuredData struct {
+	// The SD-ID, default is `alert@32473`.
+	ID string
+	// The labels encoded as the SD-PARAMs in order, all the labels are encoded in the order of the names if it is empty.
+	Labels []string
+	// The maximum number of the SD-PARAMs, default is 32.
+	MaxParams int
+}
+
+// Encode returns the SD-ELEMENT of the labels of the alert, the labels which are not valid PARAM-NAMEs are skipped.
+// It returns the NILVALUE if no label is encoded.
+func (sd *StructuredData) Encode(alert template.Alert) (string, error) {
+
+	id := sd.ID
+	if len(id) == 0 {
+		id = DefaultSDID
+	}
+	if !validName(id) {
+		return "", fmt.Errorf("invalid SD-ID %q", id)
+	}
+
+	maxParams := sd.MaxParams
+	if maxParams <= 0 {
+		maxParams = DefaultMaxParams
+	}
+
+	names := sd.Labels
+	if len(names) == 0 {
+		names = alert.Labels.Names()
+	}
+
+	var b strings.Builder
+	params := 0
+	for _, name := range names {
+		if params >= maxParams {
+			break
+		}
+
+		value, ok := alert.Labels[name]
+		if !ok || !validName(name) {
+			continue
+		}
+
+		if params == 0 {
+			b.WriteString("[" + id)
+		}
+		b.WriteString(fmt.Sprintf(" %s=\"%s\"", name, EscapeParamValue(value)))
+		params++
+	}
+
+	if params == 0 {
+		return NilValue, nil
+	}
+
+	b.WriteString("]")
+	return b.String(), nil
+}
+
+// EscapeParamValue escapes `"`, `\` and `]` in the PARAM-VALUE with `\`.
+func EscapeParamValue(value string) string {
+
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	return r.Replace(value)
+}
+
+// An SD-NAME is 1 to 32 printable US-ASCII characters except `=`, space, `]` and `"`.
+func validName(name string) bool {
+
+	if len(name) == 0 || len(name) > maxNameLength {
+		return false
+	}
+
+	for _, c := range name {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,180 @@
+package syslog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestEncode(t *testing.T) {
+
+	alert := template.Alert{
+		Labels: template.KV{
+			"alertname": "KubePodCrashLooping",
+			"namespace": "default",
+			"pod":       "web-0",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		sd    StructuredData
+		alert template.Alert
+		want  string
+		err   bool
+	}{
+		{
+			name:  "all labels in order",
+			alert: alert,
+			want:  `[alert@32473 alertname="KubePodCrashLooping" namespace="default" pod="web-0"]`,
+		},
+		{
+			name:  "configured SD-ID",
+			sd:    StructuredData{ID: "kubesphere@12345"},
+			alert: alert,
+			want:  `[kubesphere@12345 alertname="KubePodCrashLooping" namespace="default" pod="web-0"]`,
+		},
+		{
+			name:  "invalid SD-ID",
+			sd:    StructuredData{ID: "alert id"},
+			alert: alert,
+			err:   true,
+		},
+		{
+			name:  "selected labels",
+			sd:    StructuredData{Labels: []string{"pod", "missing", "alertname"}},
+			alert: alert,
+			want:  `[alert@32473 pod="web-0" alertname="KubePodCrashLooping"]`,
+		},
+		{
+			name:  "param cap",
+			sd:    StructuredData{MaxParams: 2},
+			alert: alert,
+			want:  `[alert@32473 alertname="KubePodCrashLooping" namespace="default"]`,
+		},
+		{
+			name:  "escaped values",
+			alert: template.Alert{Labels: template.KV{"message": `say "hi" \ [x]`}},
+			want:  `[alert@32473 message="say \"hi\" \\ [x\]"]`,
+		},
+		{
+			name:  "invalid names skipped",
+			alert: template.Alert{Labels: template.KV{"a=b": "x", "ok": "y"}},
+			want:  `[alert@32473 ok="y"]`,
+		},
+		{
+			name:  "nil value",
+			alert: template.Alert{Labels: template.KV{"a b": "x"}},
+			want:  NilValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sd.Encode(tt.alert)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("Encode() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+
+	tests := []template.KV{
+		{"alertname": "Watchdog"},
+		{"message": `quote " here`, "path": `C:\tmp\`, "expr": `up[5m]`},
+		{"mixed": `\"]\]"\`, "empty": ""},
+	}
+
+	for _, labels := range tests {
+		sd := StructuredData{}
+		s, err := sd.Encode(template.Alert{Labels: labels})
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+
+		id, params := parseElement(t, s)
+		if id != DefaultSDID {
+			t.Errorf("SD-ID = %s, want %s", id, DefaultSDID)
+		}
+		if !reflect.DeepEqual(params, labels) {
+			t.Errorf("decoded %v from %s, want %v", params, s, labels)
+		}
+	}
+}
+
+func TestEscapeParamValue(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{`a"b`, `a\"b`},
+		{`a\b`, `a\\b`},
+		{`a]b`, `a\]b`},
+		{`[a]`, `[a\]`},
+		{`\"]`, `\\\"\]`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeParamValue(tt.value); got != tt.want {
+			t.Errorf("EscapeParamValue(%s) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// parseElement decodes a single SD-ELEMENT as RFC 5424 specifies.
+func parseElement(t *testing.T, s string) (string, template.KV) {
+
+	if !strings.HasPrefix(s, "[") {
+		t.Fatalf("invalid SD-ELEMENT %s", s)
+	}
+
+	i := 1
+	for i < len(s) && s[i] != ' ' && s[i] != ']' {
+		i++
+	}
+	id := s[1:i]
+
+	params := template.KV{}
+	for i < len(s) && s[i] == ' ' {
+		eq := strings.Index(s[i:], `="`)
+		if eq < 0 {
+			t.Fatalf("invalid SD-PARAM in %s", s)
+		}
+		name := s[i+1 : i+eq]
+		i += eq + 2
+
+		var value strings.Builder
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) && strings.ContainsRune(`"\]`, rune(s[i+1])) {
+				i++
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			t.Fatalf("unterminated PARAM-VALUE in %s", s)
+		}
+		params[name] = value.String()
+		i++
+	}
+
+	if i != len(s)-1 || s[i] != ']' {
+		t.Fatalf("invalid SD-ELEMENT %s", s)
+	}
+
+	return id, params
+}
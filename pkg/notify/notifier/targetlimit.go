@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// The targets are cleaned up when there are more than this many.
+	maxThrottledTargets = 10000
+)
+
+var targetLimiter *TargetLimiter
+
+// TargetLimiter limits the sends to each target, such as an email address or a slack channel,
+// the sends of all the receivers to the same target share the limit.
+type TargetLimiter struct {
+	// The time of the sends to each target in the last unit, including the reserved ones.
+	sends map[string][]time.Time
+	mutex sync.Mutex
+}
+
+// ThrottledError means the send to the target is dropped because the target has reached the limit.
+type ThrottledError struct {
+	Target string
+	Wait   time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("send to %s is throttled, need to wait %s", e.Target, e.Wait)
+}
+
+func init() {
+	targetLimiter = NewTargetLimiter()
+}
+
+func GetTargetLimiter() *TargetLimiter {
+	return targetLimiter
+}
+
+func NewTargetLimiter() *TargetLimiter {
+	return &TargetLimiter{
+		sends: make(map[string][]time.Time),
+	}
+}
+
+// TargetThrottle returns the flow control of the targets, it is nil if it is disabled.
+func TargetThrottle(opts *v1alpha1.Options) *v1alpha1.Throttle {
+
+	if opts == nil || opts.Global == nil || opts.Global.TargetThrottle == nil {
+		return nil
+	}
+
+	t := opts.Global.TargetThrottle
+	if t.Threshold <= 0 || t.Unit <= 0 {
+		return nil
+	}
+
+	return t
+}
+
+// TargetKey returns the key of the target of the notifier type, the target is case insensitive.
+func TargetKey(notifierType, target string) string {
+	return fmt.Sprintf("%s/%s", notifierType, strings.ToLower(strings.TrimSpace(target)))
+}
+
+// Wait reserves a send to the target, at most `Threshold` sends in any `Unit`. It waits until the reserved time,
+// or returns a ThrottledError without reserving if the wait is longer than `MaxWaitTime`.
+// The throttled sends are counted by the counters `target_throttled_dropped` and `target_throttled_wait_ms`.
+func (l *TargetLimiter) Wait(ctx context.Context, key string, t *v1alpha1.Throttle) error {
+
+	if t == nil || t.Threshold <= 0 || t.Unit <= 0 {
+		return nil
+	}
+
+	wait, ok := l.reserve(key, t, time.Now())
+	if !ok {
+		stats.GetCounters().Add("target_throttled_dropped", 1)
+		return &ThrottledError{Target: key, Wait: wait}
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	stats.GetCounters().Add("target_throttled_wait_ms", int(wait/time.Millisecond))
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reserve the earliest time which keeps the sends in any unit under the threshold, and return the wait until it.
+func (l *TargetLimiter) reserve(key string, t *v1alpha1.Throttle, now time.Time) (time.Duration, bool) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.sends[key]; !ok && len(l.sends) >= maxThrottledTargets {
+		for k, s := range l.sends {
+			if len(s) == 0 || now.Sub(s[len(s)-1]) >= t.Unit {
+				delete(l.sends, k)
+			}
+		}
+	}
+
+	// The sends are in time order, the ones before the last unit have no effect.
+	sends := l.sends[key]
+	for len(sends) > 0 && now.Sub(sends[0]) >= t.Unit {
+		sends = sends[1:]
+	}
+
+	at := now
+	if len(sends) >= t.Threshold {
+		at = sends[len(sends)-t.Threshold].Add(t.Unit)
+	}
+
+	wait := at.Sub(now)
+	if wait > t.MaxWaitTime {
+		l.sends[key] = sends
+		return wait, false
+	}
+
+	l.sends[key] = append(sends, at)
+	return wait, true
+}
@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+)
+
+func TestTargetLimiterReserve(t *testing.T) {
+
+	throttle := &v1alpha1.Throttle{Threshold: 2, Unit: time.Minute, MaxWaitTime: 30 * time.Second}
+	now := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name string
+		// The sends reserved before, in seconds after now.
+		sends    []int
+		at       int
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{name: "under the threshold", sends: []int{0}, at: 10, wantWait: 0, wantOK: true},
+		{name: "wait for the first send to expire", sends: []int{0, 10}, at: 40, wantWait: 20 * time.Second, wantOK: true},
+		{name: "wait longer than the max wait time", sends: []int{0, 10}, at: 20, wantWait: 40 * time.Second, wantOK: false},
+		{name: "sends before the unit expired", sends: []int{0, 10}, at: 75, wantWait: 0, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewTargetLimiter()
+			for _, s := range tt.sends {
+				if _, ok := l.reserve("key", throttle, now.Add(time.Duration(s)*time.Second)); !ok {
+					t.Fatalf("send at %ds is not reserved", s)
+				}
+			}
+
+			wait, ok := l.reserve("key", throttle, now.Add(time.Duration(tt.at)*time.Second))
+			if wait != tt.wantWait || ok != tt.wantOK {
+				t.Fatalf("reserve() = %s, %v, want %s, %v", wait, ok, tt.wantWait, tt.wantOK)
+			}
+
+			// A dropped send is not reserved, so it does not delay the later sends.
+			if !ok && len(l.sends["key"]) != len(tt.sends) {
+				t.Fatalf("expect %d sends reserved, got %d", len(tt.sends), len(l.sends["key"]))
+			}
+		})
+	}
+}
+
+func TestTargetLimiterShared(t *testing.T) {
+
+	throttle := &v1alpha1.Throttle{Threshold: 1, Unit: time.Minute}
+	l := NewTargetLimiter()
+
+	if err := l.Wait(context.Background(), TargetKey("Email", "Ops@Example.com"), throttle); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		throttled bool
+	}{
+		{"same address of another receiver", TargetKey("Email", " ops@example.com"), true},
+		{"another address", TargetKey("Email", "dev@example.com"), false},
+		{"same name of another notifier type", TargetKey("Slack", "ops@example.com"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := l.Wait(context.Background(), tt.key, throttle)
+			if _, ok := err.(*ThrottledError); ok != tt.throttled {
+				t.Fatalf("Wait() = %v, want throttled %v", err, tt.throttled)
+			}
+		})
+	}
+}
+
+func TestTargetLimiterWaitCanceled(t *testing.T) {
+
+	throttle := &v1alpha1.Throttle{Threshold: 1, Unit: time.Minute, MaxWaitTime: time.Minute}
+	l := NewTargetLimiter()
+	if err := l.Wait(context.Background(), "key", throttle); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "key", throttle); err != context.DeadlineExceeded {
+		t.Fatalf("Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestTargetThrottle(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		opts    *v1alpha1.Options
+		enabled bool
+	}{
+		{"no options", nil, false},
+		{"no target throttle", &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{}}, false},
+		{"zero threshold", &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TargetThrottle: &v1alpha1.Throttle{Unit: time.Minute}}}, false},
+		{"enabled", &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TargetThrottle: &v1alpha1.Throttle{Threshold: 1, Unit: time.Minute}}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := TargetThrottle(tt.opts) != nil; got != tt.enabled {
+			t.Errorf("%s: TargetThrottle() enabled = %v, want %v", tt.name, got, tt.enabled)
+		}
+	}
+}
@@ -6,20 +6,64 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/common/model"
 	"net/url"
+	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	tmpltext "text/template"
+)
+
+const (
+	StatusModeSection = "section"
+	StatusModeSplit   = "split"
+	// The common annotation to pass the name of the template selected by the alerts.
+	TemplateAnnotation = "notificationTemplate"
+	// The common annotations of the repeat notification of an acknowledged group, who and when acknowledged it.
+	AcknowledgedByAnnotation = "acknowledgedBy"
+	AcknowledgedAtAnnotation = "acknowledgedAt"
+)
+
+var templateNameRegex = regexp.MustCompile(`{{template"(.*?)".}}`)
+
+// The names of the templates which can be selected by the alerts.
+var selectedNameRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// The error of executing an undefined template, text/template and html/template report it in different forms.
+var missingTemplateRegex = regexp.MustCompile(`(?:template "([^"]+)" not defined|no such template "([^"]+)")`)
+
+var (
+	// The missing optional templates which have been warned.
+	warnedTemplates = make(map[string]bool)
+	optionalMutex   sync.Mutex
 )
 
 type Template struct {
-	Tmpl *template.Template
-	path []string
+	tmpl *template.Template
+	// The names of the templates defined in the template files.
+	names map[string]bool
+	path  []string
+	mutex sync.RWMutex
+	// The template whose parsed templates are shared, it is set for the derived templates of the receivers.
+	source *Template
+	// The annotations listed in the annotation section of the alerts.
+	annotations []v1alpha1.AnnotationField
+	// The patterns of the sub-templates which render empty if they are not defined.
+	optional []string
+	// Whether to render each alert as a collapsible section.
+	collapsible bool
+	// The annotations whose values are Markdown.
+	markdown []string
+	// The accents of the alerts.
+	accent *v1alpha1.AlertAccent
 }
 
 var notifierTemplate *Template
@@ -40,23 +84,353 @@ func NewTemplate(paths []string) (*Template, error) {
 		return notifierTemplate, nil
 	}
 
-	t := &Template{}
+	tmpl, names, err := parse(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	notifierTemplate = &Template{
+		tmpl:  tmpl,
+		names: names,
+		path:  paths,
+	}
+
+	return notifierTemplate, nil
+}
+
+// ReloadTemplate re-parses the template files and swaps the template in place,
+// so the notifiers holding the template will use the new one at the next send without being reconstructed.
+// If the template files are invalid, the previous template will be kept.
+func ReloadTemplate() error {
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if notifierTemplate == nil {
+		return nil
+	}
+
+	tmpl, names, err := parse(templatePaths)
+	if err != nil {
+		return err
+	}
+
+	notifierTemplate.mutex.Lock()
+	notifierTemplate.tmpl = tmpl
+	notifierTemplate.names = names
+	notifierTemplate.mutex.Unlock()
+
+	return nil
+}
+
+func parse(paths []string) (*template.Template, map[string]bool, error) {
 
 	tmpl, err := template.FromGlobs(paths...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tmpl.ExternalURL, _ = url.Parse("http://kubesphere.io")
 
-	t.Tmpl = tmpl
-	notifierTemplate = t
+	names, err := definedTemplates(paths)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return notifierTemplate, nil
+	return tmpl, names, nil
+}
+
+// The names of the templates defined in the template files, alertmanager does not expose the templates it parses,
+// so the files are parsed again in the same way.
+func definedTemplates(paths []string) (map[string]bool, error) {
+
+	tmpl := tmpltext.New("").Funcs(tmpltext.FuncMap(template.DefaultFuncs))
+	for _, p := range paths {
+		files, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if tmpl, err = tmpl.ParseGlob(p); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, t := range tmpl.Templates() {
+		names[t.Name()] = true
+	}
+
+	return names, nil
+}
+
+// Lookup returns true if the template `name` is defined in the template files.
+func (t *Template) Lookup(name string) bool {
+
+	if t.source != nil {
+		return t.source.Lookup(name)
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.names[name]
+}
+
+// A template sharing the parsed templates and the settings with `t`, so it is cheap to create one for each receiver.
+func (t *Template) derive() *Template {
+
+	source := t
+	if t.source != nil {
+		source = t.source
+	}
+
+	return &Template{
+		path:        source.path,
+		source:      source,
+		annotations: t.annotations,
+		optional:    t.optional,
+		collapsible: t.collapsible,
+		markdown:    t.markdown,
+		accent:      t.accent,
+	}
+}
+
+// WithAnnotationSection returns a template rendering the alerts with the annotation section of `fields`,
+// it shares the parsed templates with `t`, so it is cheap to create one for each receiver.
+func (t *Template) WithAnnotationSection(fields []v1alpha1.AnnotationField) *Template {
+
+	if len(fields) == 0 {
+		return t
+	}
+
+	d := t.derive()
+	d.annotations = fields
+	return d
+}
+
+// WithOptionalTemplates returns a template rendering the missing sub-templates matching the patterns,
+// such as `email.custom.*`, as empty with a warning instead of failing the whole message.
+// It shares the parsed templates with `t` too.
+func (t *Template) WithOptionalTemplates(patterns []string) *Template {
+
+	if len(patterns) == 0 {
+		return t
+	}
+
+	d := t.derive()
+	d.optional = patterns
+	return d
+}
+
+// WithCollapsible returns a template rendering each alert as a collapsible section if `collapsible` is true,
+// such as the `<details>` of the default html template, it shares the parsed templates with `t` too.
+func (t *Template) WithCollapsible(collapsible bool) *Template {
+
+	if !collapsible {
+		return t
+	}
+
+	d := t.derive()
+	d.collapsible = collapsible
+	return d
+}
+
+// WithMarkdownAnnotations returns a template rendering the values of the annotations as sanitized html converted
+// from Markdown, in the html templates using `AnnotationHTML`. It shares the parsed templates with `t` too.
+func (t *Template) WithMarkdownAnnotations(names []string) *Template {
+
+	if len(names) == 0 {
+		return t
+	}
+
+	d := t.derive()
+	d.markdown = names
+	return d
+}
+
+// WithAlertAccent returns a template rendering the alerts with the accents, `.Accent` of each alert,
+// if the accents are enabled. It shares the parsed templates with `t` too.
+func (t *Template) WithAlertAccent(accent *v1alpha1.AlertAccent) *Template {
+
+	if accent == nil || !accent.Enabled {
+		return t
+	}
+
+	d := t.derive()
+	d.accent = accent
+	return d
+}
+
+// CacheKey returns the key of the message rendered with the template `name` in the render cache,
+// the templates with different annotation sections, optional templates, collapsible, markdown or accent settings
+// render different messages.
+func (t *Template) CacheKey(name string) string {
+
+	key := name
+	if len(t.annotations) > 0 {
+		key = fmt.Sprintf("%s/%v", key, t.annotations)
+	}
+
+	if len(t.optional) > 0 {
+		key = fmt.Sprintf("%s/optional%v", key, t.optional)
+	}
+
+	if t.collapsible {
+		key += "/collapsible"
+	}
+
+	if len(t.markdown) > 0 {
+		key = fmt.Sprintf("%s/markdown%v", key, t.markdown)
+	}
+
+	if t.accent != nil {
+		key = fmt.Sprintf("%s/accent%v%v", key, t.accent.Palette, t.accent.Severities)
+	}
+
+	return key
+}
+
+func (t *Template) isOptional(name string) bool {
+
+	for _, pattern := range t.optional {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Warn only once for each missing template, to avoid flooding the log.
+func warnMissingTemplate(name string, l log.Logger) {
+
+	optionalMutex.Lock()
+	defer optionalMutex.Unlock()
+
+	if warnedTemplates[name] {
+		return
+	}
+
+	warnedTemplates[name] = true
+	_ = level.Warn(l).Log("msg", "optional template is not defined, render it as empty", "template", name)
+}
+
+func missingTemplate(err error) string {
+
+	sub := missingTemplateRegex.FindStringSubmatch(err.Error())
+	if len(sub) < 3 {
+		return ""
+	}
+
+	if len(sub[1]) > 0 {
+		return sub[1]
+	}
+
+	return sub[2]
+}
+
+// SelectTemplate returns the template `<selected>.<kind>` if the alerts select a template by the common annotation,
+// the kind is `html` or `text`. It returns `name` if no template is selected or the selected one is not defined.
+func (t *Template) SelectTemplate(data template.Data, kind, name string, l log.Logger) string {
+
+	selected := data.CommonAnnotations[TemplateAnnotation]
+	if len(selected) == 0 {
+		return name
+	}
+
+	// The name comes from the label of the alerts, it must not inject the template actions.
+	s := fmt.Sprintf("%s.%s", selected, kind)
+	if !selectedNameRegex.MatchString(s) {
+		_ = level.Warn(l).Log("msg", "invalid name of the selected template, use the default template", "template", s)
+		return name
+	}
+
+	if _, err := t.templeText(s, template.Data{}, true, l); err != nil && missingTemplate(err) == s {
+		optionalMutex.Lock()
+		warned := warnedTemplates[s]
+		warnedTemplates[s] = true
+		optionalMutex.Unlock()
+
+		if !warned {
+			_ = level.Warn(l).Log("msg", "selected template is not defined, use the default template", "template", s)
+		}
+		return name
+	}
+
+	return s
+}
+
+// Tmpl returns the template currently in use.
+func (t *Template) Tmpl() *template.Template {
+
+	if t.source != nil {
+		return t.source.Tmpl()
+	}
+
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.tmpl
 }
 
 func (t *Template) TempleText(name string, data template.Data, l log.Logger) (string, error) {
+	return t.templeText(name, data, false, l)
+}
 
-	name = t.transform(name)
+func (t *Template) templeText(name string, data template.Data, strict bool, l log.Logger) (string, error) {
+
+	tmpl, d := t.templateData(data, l)
+
+	return t.execute(name, strict, func(text string) (string, error) {
+		return tmpl.ExecuteTextString(text, d)
+	}, l)
+}
+
+// TempleHTML renders the template as html, the values are escaped.
+func (t *Template) TempleHTML(name string, data template.Data, l log.Logger) (string, error) {
+
+	tmpl, d := t.templateData(data, l)
+
+	return t.execute(name, false, func(text string) (string, error) {
+		return tmpl.ExecuteHTMLString(text, d)
+	}, l)
+}
+
+// Execute the template `name`. If a sub-template matching the optional templates is not defined,
+// it will be defined as empty and the template will be executed again, unless `strict` is true.
+// The templates are rendered in the render pool, alertmanager executes a clone of the shared template each time,
+// so the concurrent renders do not affect each other.
+func (t *Template) execute(name string, strict bool, exec func(text string) (string, error), l log.Logger) (string, error) {
+
+	release := GetRenderPool().Acquire()
+	defer release()
+
+	text := t.transform(name)
+	defined := ""
+	for {
+		s, err := exec(defined + text)
+		if err == nil {
+			return strings.TrimRight(s, "\n"), nil
+		}
+
+		if strict {
+			return "", err
+		}
+
+		missing := missingTemplate(err)
+		if len(missing) == 0 || !t.isOptional(missing) || strings.Contains(defined, strconv.Quote(missing)) {
+			return "", err
+		}
+
+		warnMissingTemplate(missing, l)
+		defined += fmt.Sprintf("{{ define %s }}{{ end }}", strconv.Quote(missing))
+	}
+}
+
+// Get the template in use and the data to render it, the data is generated from the alerts like alertmanager does.
+func (t *Template) templateData(data template.Data, l log.Logger) (*template.Template, *TemplateData) {
 
 	ctx := context.Background()
 	ctx = notify.WithGroupLabels(ctx, KvToLabelSet(data.GroupLabels))
@@ -75,25 +449,23 @@ func (t *Template) TempleText(name string, data template.Data, l log.Logger) (st
 		})
 	}
 
-	d := notify.GetTemplateData(ctx, t.Tmpl, as, l)
-
-	var e error
-	text := notify.TmplText(t.Tmpl, d, &e)
-	if e != nil {
-		return "", e
+	tmpl := t.Tmpl()
+	d := notify.GetTemplateData(ctx, tmpl, as, l)
+	// The common annotations set by the notification manager, such as the stale marker or the acknowledgement, are not in the alerts.
+	for k, v := range data.CommonAnnotations {
+		if _, ok := d.CommonAnnotations[k]; !ok {
+			d.CommonAnnotations[k] = v
+		}
 	}
 
-	s := text(name)
-
-	return strings.TrimRight(s, "\n"), nil
+	return tmpl, newTemplateData(d, t.annotations, t.collapsible, t.markdown, t.accent)
 }
 
 func (t *Template) transform(name string) string {
 
 	n := strings.ReplaceAll(name, " ", "")
 
-	b, _ := regexp.MatchString("{{template\"(.*?)\".}}", n)
-	if b {
+	if templateNameRegex.MatchString(n) {
 		return name
 	}
 
@@ -101,6 +473,12 @@ func (t *Template) transform(name string) string {
 }
 
 func (t *Template) Split(data template.Data, maxSize int, templateName string, l log.Logger) ([]string, error) {
+	return t.split(data, maxSize, func(d template.Data) (string, error) {
+		return t.TempleText(templateName, d, l)
+	}, l)
+}
+
+func (t *Template) split(data template.Data, maxSize int, render func(d template.Data) (string, error), l log.Logger) ([]string, error) {
 	d := template.Data{
 		Receiver:    data.Receiver,
 		GroupLabels: data.GroupLabels,
@@ -110,7 +488,7 @@ func (t *Template) Split(data template.Data, maxSize int, templateName string, l
 	for i := 0; i < len(data.Alerts); i++ {
 
 		d.Alerts = append(d.Alerts, data.Alerts[i])
-		msg, err := t.TempleText(templateName, d, l)
+		msg, err := render(d)
 		if err != nil {
 			return nil, err
 		}
@@ -142,6 +520,131 @@ func (t *Template) Split(data template.Data, maxSize int, templateName string, l
 	return messages, nil
 }
 
+// TempleTextByStatus generates messages with the status templates `<name>.firing` and `<name>.resolved`.
+// In section mode, the firing and resolved alerts are rendered in separate sections of one message,
+// in split mode, they are rendered as separate messages.
+// If the status templates are not defined, it will use the template `name`.
+func (t *Template) TempleTextByStatus(name string, data template.Data, mode string, l log.Logger) ([]string, error) {
+
+	if !t.hasStatusTemplates(name) {
+		msg, err := t.TempleText(name, data, l)
+		if err != nil {
+			return nil, err
+		}
+		return []string{msg}, nil
+	}
+
+	if mode != StatusModeSplit {
+		msg, err := t.sectionText(name, data, l)
+		if err != nil {
+			return nil, err
+		}
+		return []string{msg}, nil
+	}
+
+	var messages []string
+	for _, status := range []string{string(model.AlertFiring), string(model.AlertResolved)} {
+		d := FilterByStatus(data, status)
+		if len(d.Alerts) == 0 {
+			continue
+		}
+
+		msg, err := t.TempleText(statusTemplateName(name, status), d, l)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// SplitByStatus works like `Split`, but generates messages with the status templates like `TempleTextByStatus`.
+func (t *Template) SplitByStatus(data template.Data, maxSize int, name, mode string, l log.Logger) ([]string, error) {
+
+	if !t.hasStatusTemplates(name) {
+		return t.Split(data, maxSize, name, l)
+	}
+
+	if mode != StatusModeSplit {
+		return t.split(data, maxSize, func(d template.Data) (string, error) {
+			return t.sectionText(name, d, l)
+		}, l)
+	}
+
+	var messages []string
+	for _, status := range []string{string(model.AlertFiring), string(model.AlertResolved)} {
+		d := FilterByStatus(data, status)
+		if len(d.Alerts) == 0 {
+			continue
+		}
+
+		msgs, err := t.Split(d, maxSize, statusTemplateName(name, status), l)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msgs...)
+	}
+
+	return messages, nil
+}
+
+func (t *Template) sectionText(name string, data template.Data, l log.Logger) (string, error) {
+
+	var sections []string
+	for _, status := range []string{string(model.AlertFiring), string(model.AlertResolved)} {
+		d := FilterByStatus(data, status)
+		if len(d.Alerts) == 0 {
+			continue
+		}
+
+		msg, err := t.TempleText(statusTemplateName(name, status), d, l)
+		if err != nil {
+			return "", err
+		}
+		sections = append(sections, msg)
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// Both of the status templates must be defined, otherwise the template `name` will be used.
+func (t *Template) hasStatusTemplates(name string) bool {
+
+	for _, status := range []string{string(model.AlertFiring), string(model.AlertResolved)} {
+		if !t.Lookup(statusTemplateName(name, status)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func statusTemplateName(name, status string) string {
+
+	n := strings.ReplaceAll(name, " ", "")
+	sub := templateNameRegex.FindStringSubmatch(n)
+	if len(sub) > 1 {
+		n = sub[1]
+	}
+
+	return fmt.Sprintf("%s.%s", n, status)
+}
+
+// FilterByStatus returns the data with the alerts of the status only.
+func FilterByStatus(data template.Data, status string) template.Data {
+
+	d := data
+	d.Alerts = nil
+	for _, a := range data.Alerts {
+		if a.Status == status {
+			d.Alerts = append(d.Alerts, a)
+		}
+	}
+
+	return d
+}
+
 // When a string is serialized, the escape character in the string will occupy two bytes because of `\`.
 func Len(s string) int {
 
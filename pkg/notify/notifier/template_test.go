@@ -0,0 +1,350 @@
+package notifier
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func newTestTemplate(t *testing.T, text string) *Template {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(text), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := NewTemplate([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tmpl
+}
+
+func statusTestData(statuses ...string) template.Data {
+
+	data := template.Data{}
+	for i, status := range statuses {
+		a := template.Alert{
+			Status:   status,
+			Labels:   template.KV{"alertname": status + string(rune('A'+i))},
+			StartsAt: time.Now().Add(-time.Hour),
+		}
+		if status == "resolved" {
+			a.EndsAt = time.Now().Add(-time.Minute)
+		}
+		data.Alerts = append(data.Alerts, a)
+	}
+
+	return data
+}
+
+const statusTestTemplates = `{{ define "msg" }}all{{ end }}` +
+	`{{ define "msg.firing" }}FIRING{{ range .Alerts }} {{ .Labels.alertname }}{{ end }}{{ end }}` +
+	`{{ define "msg.resolved" }}RESOLVED{{ range .Alerts }} {{ .Labels.alertname }}{{ end }}{{ end }}`
+
+func TestTempleTextByStatus(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data template.Data
+		mode string
+		want []string
+	}{
+		{"firing only in section mode", statusTestData("firing", "firing"), StatusModeSection, []string{"FIRING firingA firingB"}},
+		{"resolved only in section mode", statusTestData("resolved"), StatusModeSection, []string{"RESOLVED resolvedA"}},
+		{"mixed in section mode", statusTestData("firing", "resolved"), StatusModeSection, []string{"FIRING firingA\n\nRESOLVED resolvedB"}},
+		{"firing only in split mode", statusTestData("firing"), StatusModeSplit, []string{"FIRING firingA"}},
+		{"resolved only in split mode", statusTestData("resolved", "resolved"), StatusModeSplit, []string{"RESOLVED resolvedA resolvedB"}},
+		{"mixed in split mode", statusTestData("resolved", "firing"), StatusModeSplit, []string{"FIRING firingB", "RESOLVED resolvedA"}},
+	}
+
+	tmpl := newTestTemplate(t, statusTestTemplates)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := tmpl.TempleTextByStatus("msg", tt.data, tt.mode, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(msgs) != len(tt.want) {
+				t.Fatalf("TempleTextByStatus() = %q, want %q", msgs, tt.want)
+			}
+			for i := range msgs {
+				if msgs[i] != tt.want[i] {
+					t.Fatalf("TempleTextByStatus() = %q, want %q", msgs, tt.want)
+				}
+			}
+
+			// SplitByStatus renders the same messages when they are small enough.
+			split, err := tmpl.SplitByStatus(tt.data, 4096, "msg", tt.mode, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(split) != len(tt.want) {
+				t.Fatalf("SplitByStatus() = %q, want %q", split, tt.want)
+			}
+			for i := range split {
+				if split[i] != tt.want[i] {
+					t.Fatalf("SplitByStatus() = %q, want %q", split, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusTemplatesLookup(t *testing.T) {
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			// The status templates fail to render without alerts, they are still found.
+			name: "status templates",
+			text: `{{ define "msg" }}all{{ end }}` +
+				`{{ define "msg.firing" }}firing {{ (index .Alerts 0).Labels.alertname }}{{ end }}` +
+				`{{ define "msg.resolved" }}resolved {{ (index .Alerts 0).Labels.alertname }}{{ end }}`,
+			want: "firing firingA\n\nresolved resolvedB",
+		},
+		{
+			name: "missing resolved template",
+			text: `{{ define "msg" }}all{{ end }}{{ define "msg.firing" }}firing{{ end }}`,
+			want: "all",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := newTestTemplate(t, tt.text)
+			msgs, err := tmpl.TempleTextByStatus("msg", statusTestData("firing", "resolved"), StatusModeSection, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(msgs) != 1 || msgs[0] != tt.want {
+				t.Fatalf("TempleTextByStatus() = %q, want %q", msgs, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadTemplate(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(`{{ define "msg" }}old{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := NewTemplate([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	render := func() string {
+		msg, err := tmpl.TempleText("msg", statusTestData("firing"), log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	if got := render(); got != "old" {
+		t.Fatalf("render = %q, want old", got)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(`{{ define "msg" }}new{{ end }}{{ define "msg.firing" }}{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadTemplate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := render(); got != "new" {
+		t.Fatalf("render = %q after reloaded, want new", got)
+	}
+	if !tmpl.Lookup("msg.firing") {
+		t.Fatal("expect msg.firing defined after reloaded")
+	}
+
+	// A parse error keeps the previous template.
+	if err := ioutil.WriteFile(file, []byte(`{{ define "msg" }}broken{{ end `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadTemplate(); err == nil {
+		t.Fatal("expect the parse error returned")
+	}
+	if got := render(); got != "new" {
+		t.Fatalf("render = %q after the parse error, want new", got)
+	}
+	if !tmpl.Lookup("msg.firing") {
+		t.Fatal("expect the names kept after the parse error")
+	}
+}
+
+func TestOptionalTemplates(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}disk full{{ template "email.custom.footer" . }}{{ end }}`)
+	data := template.Data{Alerts: template.Alerts{{Status: "firing", StartsAt: time.Now()}}}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     string
+		wantErr  bool
+	}{
+		{name: "required", wantErr: true},
+		{name: "optional", patterns: []string{"email.custom.*"}, want: "disk full"},
+		{name: "not matched", patterns: []string{"slack.custom.*"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tmpl.WithOptionalTemplates(tt.patterns)
+			for kind, render := range map[string]func(string, template.Data, log.Logger) (string, error){
+				"text": d.TempleText,
+				"html": d.TempleHTML,
+			} {
+				got, err := render(`msg`, data, log.NewNopLogger())
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("%s: render error = %v, want error %v", kind, err, tt.wantErr)
+				}
+				if got != tt.want {
+					t.Fatalf("%s: render = %q, want %q", kind, got, tt.want)
+				}
+			}
+		})
+	}
+
+	// The receivers with different optional templates must not share the rendered messages.
+	if tmpl.CacheKey("msg") == tmpl.WithOptionalTemplates([]string{"email.custom.*"}).CacheKey("msg") {
+		t.Fatal("expect the cache keys differ")
+	}
+}
+
+func TestOptionalTemplatesAfterReload(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(`{{ define "msg" }}old{{ template "custom" . }}{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := NewTemplate([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := tmpl.WithOptionalTemplates([]string{"custom"})
+
+	if err := ioutil.WriteFile(file, []byte(`{{ define "msg" }}new{{ template "custom" . }}{{ end }}{{ define "custom" }}!{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReloadTemplate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The derived template shares the reloaded templates.
+	got, err := d.TempleText("msg", statusTestData("firing"), log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "new!" {
+		t.Fatalf("render = %q after reloaded, want new!", got)
+	}
+	if !d.Lookup("custom") {
+		t.Fatal("expect the derived template shares the names")
+	}
+}
+
+func TestAnnotationSection(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}{{ range .Alerts }}`+
+		`{{ if .HasAnnotationSection }}{{ range .AnnotationSection }}{{ .Name }}={{ .Value }};{{ end }}`+
+		`{{ else }}{{ range .Annotations.SortedPairs }}{{ .Name }}={{ .Value }};{{ end }}{{ end }}`+
+		`{{ end }}{{ end }}`)
+	data := template.Data{Alerts: template.Alerts{{
+		Status:      "firing",
+		Annotations: template.KV{"summary": "disk full", "runbook_url": "http://runbook", "message": "hidden"},
+		StartsAt:    time.Now(),
+	}}}
+
+	tests := []struct {
+		name   string
+		fields []v1alpha1.AnnotationField
+		want   string
+	}{
+		{name: "all shown if not set", want: "message=hidden;runbook_url=http://runbook;summary=disk full;"},
+		{
+			name:   "listed in order",
+			fields: []v1alpha1.AnnotationField{{Name: "summary"}, {Name: "runbook_url"}},
+			want:   "summary=disk full;runbook_url=http://runbook;",
+		},
+		{
+			name:   "display names",
+			fields: []v1alpha1.AnnotationField{{Name: "runbook_url", DisplayName: "Runbook"}},
+			want:   "Runbook=http://runbook;",
+		},
+		{
+			name:   "missing annotations skipped",
+			fields: []v1alpha1.AnnotationField{{Name: "description"}, {Name: "summary"}},
+			want:   "summary=disk full;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tmpl.WithAnnotationSection(tt.fields)
+			for kind, render := range map[string]func(string, template.Data, log.Logger) (string, error){
+				"text": d.TempleText,
+				"html": d.TempleHTML,
+			} {
+				got, err := render(`msg`, data, log.NewNopLogger())
+				if err != nil {
+					t.Fatalf("%s: render error %v", kind, err)
+				}
+				if got != tt.want {
+					t.Fatalf("%s: render = %q, want %q", kind, got, tt.want)
+				}
+			}
+		})
+	}
+
+	// The receivers with different annotation sections must not share the rendered messages.
+	fields := []v1alpha1.AnnotationField{{Name: "summary"}}
+	if tmpl.CacheKey("msg") == tmpl.WithAnnotationSection(fields).CacheKey("msg") {
+		t.Fatal("expect the cache keys differ")
+	}
+	derived := tmpl.WithAnnotationSection(fields).WithOptionalTemplates([]string{"email.custom.*"})
+	if len(derived.annotations) != 1 || derived.source != tmpl {
+		t.Fatal("expect the derived template keeps the annotation section and shares the parsed templates")
+	}
+}
+
+func TestSelectTemplate(t *testing.T) {
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}default{{ end }}{{ define "db.text" }}db{{ end }}`)
+
+	tests := []struct {
+		name     string
+		selected string
+		want     string
+	}{
+		{name: "not selected", want: "msg"},
+		{name: "selected", selected: "db", want: "db.text"},
+		{name: "not defined", selected: "network", want: "msg"},
+		{name: "invalid name", selected: `db" }}{{ "x`, want: "msg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := template.Data{CommonAnnotations: template.KV{}}
+			if len(tt.selected) > 0 {
+				data.CommonAnnotations[TemplateAnnotation] = tt.selected
+			}
+
+			if got := tmpl.SelectTemplate(data, "text", "msg", log.NewNopLogger()); got != tt.want {
+				t.Fatalf("SelectTemplate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"sync"
+)
+
+const (
+	// The annotations override the labels of the same name in the context.
+	ContextPrecedenceAnnotations = "annotations"
+	// The labels override the annotations of the same name in the context.
+	ContextPrecedenceLabels = "labels"
+)
+
+var (
+	contextPrecedence = ContextPrecedenceAnnotations
+	contextMutex      sync.Mutex
+)
+
+// TemplateData is the data to render the templates, it extends the data of alertmanager with the annotation section,
+// so the templates written for alertmanager still work.
+type TemplateData struct {
+	*template.Data
+	Alerts TemplateAlerts
+	// Whether the receiver renders each alert as a collapsible section, with the summary line shown
+	// and the labels and annotations hidden until expanded.
+	Collapsible bool
+	// Whether the labels override the annotations in the context.
+	labelsFirst bool
+	// The names of the annotations whose values are Markdown, including the display names in the annotation section.
+	markdown map[string]bool
+}
+
+type TemplateAlert struct {
+	template.Alert
+	// The annotations listed by the receiver, in the order of the list and named by the display names,
+	// the annotations missing in the alert are skipped. It is nil if the receiver does not list the annotations.
+	AnnotationSection template.Pairs
+	labelsFirst       bool
+	accent            *v1alpha1.AlertAccent
+}
+
+type TemplateAlerts []TemplateAlert
+
+// HasAnnotationSection returns true if the receiver lists the annotations, the annotations not listed should be hidden.
+func (a TemplateAlert) HasAnnotationSection() bool {
+	return a.AnnotationSection != nil
+}
+
+// Summary returns the summary line of the alert, the alert name and the severity, such as `KubePodCrashLooping [critical]`.
+func (a TemplateAlert) Summary() string {
+
+	summary := a.Labels["alertname"]
+	if severity, ok := a.Labels["severity"]; ok {
+		summary = fmt.Sprintf("%s [%s]", summary, severity)
+	}
+
+	return summary
+}
+
+// Accent returns the accent of the alert, such as `{{ .Accent.Emoji }}`, it is empty if the receiver does not enable the accents.
+func (a TemplateAlert) Accent() v1alpha1.Accent {
+	return AlertAccent(a.accent, a.Alert)
+}
+
+// Context returns the labels and the annotations of the alert in one map, so the templates can use `{{ .Context.pod }}`
+// rather than looking up the labels and the annotations separately.
+func (a TemplateAlert) Context() template.KV {
+	return mergeContext(a.Labels, a.Annotations, a.labelsFirst)
+}
+
+// AnnotationHTML returns the value of the annotation as sanitized html converted from Markdown if the receiver
+// marks the annotation as Markdown, such as `{{ $.AnnotationHTML .Name .Value }}`, otherwise the value is returned as is.
+func (d *TemplateData) AnnotationHTML(name, value string) interface{} {
+
+	if d.markdown[name] {
+		return MarkdownToHTML(value)
+	}
+
+	return value
+}
+
+// CommonContext returns the common labels and the common annotations in one map, such as `{{ .CommonContext.summary }}`.
+func (d *TemplateData) CommonContext() template.KV {
+	return mergeContext(d.CommonLabels, d.CommonAnnotations, d.labelsFirst)
+}
+
+// SetContextPrecedence sets which of the labels and the annotations wins when they have the same name in the context,
+// `annotations` or `labels`. Default is `annotations`.
+func SetContextPrecedence(precedence string) {
+
+	contextMutex.Lock()
+	defer contextMutex.Unlock()
+
+	if precedence != ContextPrecedenceLabels {
+		precedence = ContextPrecedenceAnnotations
+	}
+	contextPrecedence = precedence
+}
+
+func contextLabelsFirst() bool {
+
+	contextMutex.Lock()
+	defer contextMutex.Unlock()
+
+	return contextPrecedence == ContextPrecedenceLabels
+}
+
+func mergeContext(labels, annotations template.KV, labelsFirst bool) template.KV {
+
+	first, second := annotations, labels
+	if labelsFirst {
+		first, second = labels, annotations
+	}
+
+	kv := template.KV{}
+	for k, v := range second {
+		kv[k] = v
+	}
+	for k, v := range first {
+		kv[k] = v
+	}
+
+	return kv
+}
+
+// Firing returns the subset of alerts that are firing.
+func (as TemplateAlerts) Firing() TemplateAlerts {
+	return as.filter(string(model.AlertFiring))
+}
+
+// Resolved returns the subset of alerts that are resolved.
+func (as TemplateAlerts) Resolved() TemplateAlerts {
+	return as.filter(string(model.AlertResolved))
+}
+
+func (as TemplateAlerts) filter(status string) TemplateAlerts {
+
+	res := TemplateAlerts{}
+	for _, a := range as {
+		if a.Status == status {
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+func newTemplateData(data *template.Data, fields []v1alpha1.AnnotationField, collapsible bool, markdown []string, accent *v1alpha1.AlertAccent) *TemplateData {
+
+	d := &TemplateData{
+		Data:        data,
+		Collapsible: collapsible,
+		labelsFirst: contextLabelsFirst(),
+	}
+
+	if len(markdown) > 0 {
+		d.markdown = make(map[string]bool)
+		for _, name := range markdown {
+			d.markdown[name] = true
+		}
+		for _, f := range fields {
+			if d.markdown[f.Name] && len(f.DisplayName) > 0 {
+				d.markdown[f.DisplayName] = true
+			}
+		}
+	}
+
+	for _, a := range data.Alerts {
+		d.Alerts = append(d.Alerts, TemplateAlert{
+			Alert:             a,
+			AnnotationSection: AnnotationSection(a.Annotations, fields),
+			labelsFirst:       d.labelsFirst,
+			accent:            accent,
+		})
+	}
+
+	return d
+}
+
+// AnnotationSection returns the annotations listed by the fields in order, named by the display names.
+// It is nil if no field is listed.
+func AnnotationSection(annotations template.KV, fields []v1alpha1.AnnotationField) template.Pairs {
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	pairs := template.Pairs{}
+	for _, f := range fields {
+		v, ok := annotations[f.Name]
+		if !ok {
+			continue
+		}
+
+		name := f.DisplayName
+		if len(name) == 0 {
+			name = f.Name
+		}
+
+		pairs = append(pairs, template.Pair{Name: name, Value: v})
+	}
+
+	return pairs
+}
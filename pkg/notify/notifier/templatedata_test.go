@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestContext(t *testing.T) {
+
+	// The common labels and annotations are those of the only alert.
+	data := template.Data{
+		Alerts: template.Alerts{{
+			Status:      "firing",
+			Labels:      template.KV{"namespace": "default", "pod": "web-0", "summary": "from label", "message": "from label"},
+			Annotations: template.KV{"summary": "from annotation", "runbook": "https://runbook", "message": "from annotation"},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		precedence string
+		want       string
+	}{
+		{"annotations by default", "", "default/from annotation/https://runbook web-0/from annotation"},
+		{"annotations first", ContextPrecedenceAnnotations, "default/from annotation/https://runbook web-0/from annotation"},
+		{"labels first", ContextPrecedenceLabels, "default/from label/https://runbook web-0/from label"},
+		{"unknown precedence", "unknown", "default/from annotation/https://runbook web-0/from annotation"},
+	}
+
+	tmpl := newTestTemplate(t, `{{ define "msg" }}{{ .CommonContext.namespace }}/{{ .CommonContext.summary }}/{{ .CommonContext.runbook }}`+
+		`{{ range .Alerts }} {{ .Context.pod }}/{{ .Context.message }}{{ end }}{{ end }}`)
+	defer SetContextPrecedence("")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetContextPrecedence(tt.precedence)
+			got, err := tmpl.TempleText("msg", data, log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("TempleText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeContextKeepsSources(t *testing.T) {
+
+	labels := template.KV{"a": "label"}
+	annotations := template.KV{"a": "annotation", "b": "annotation"}
+	kv := mergeContext(labels, annotations, false)
+	kv["c"] = "new"
+
+	if len(labels) != 1 || len(annotations) != 2 {
+		t.Fatalf("the labels or the annotations are changed, %v, %v", labels, annotations)
+	}
+}
@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/alertmanager/template"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the templates")
+
+// newSampleTemplate loads the default templates from the sample template config map.
+func newSampleTemplate(t *testing.T) *Template {
+
+	bs, err := ioutil.ReadFile("../../../config/samples/template.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cm := struct {
+		Data map[string]string `json:"data"`
+	}{}
+	if err := yaml.Unmarshal(bs, &cm); err != nil {
+		t.Fatal(err)
+	}
+
+	return newTestTemplate(t, cm.Data["template"])
+}
+
+func goldenTestData() template.Data {
+
+	startsAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	return template.Data{
+		Status:      "firing",
+		GroupLabels: template.KV{"alertname": "KubePodCrashLooping"},
+		Alerts: template.Alerts{
+			{
+				Status:      "firing",
+				Labels:      template.KV{"alertname": "KubePodCrashLooping", "severity": "critical", "pod": "web-0"},
+				Annotations: template.KV{"message": "Pod web-0 is restarting"},
+				StartsAt:    startsAt,
+			},
+			{
+				Status:      "resolved",
+				Labels:      template.KV{"alertname": "KubePodCrashLooping", "pod": "web-1"},
+				Annotations: template.KV{"message": "Pod web-1 is restarting"},
+				StartsAt:    startsAt,
+				EndsAt:      startsAt.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestDefaultHTMLGolden(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		collapsible bool
+		golden      string
+	}{
+		{"plain sections", false, "default.html.golden"},
+		{"collapsible sections", true, "default_collapsible.html.golden"},
+	}
+
+	tmpl := newSampleTemplate(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tmpl.WithCollapsible(tt.collapsible).TempleHTML("nm.default.html", goldenTestData(), log.NewNopLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("TempleHTML() differs from %s:\n%s", golden, got)
+			}
+		})
+	}
+}
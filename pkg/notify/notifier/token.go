@@ -13,19 +13,18 @@ type token struct {
 	accessToken   string
 	accessTokenAt time.Time
 	expires       time.Duration
-	mutex         sync.Mutex
 }
 
 type AccessTokenService struct {
 	mutex  sync.Mutex
-	tokens map[string]token
+	tokens map[string]*token
 }
 
 var ats *AccessTokenService
 
 func init() {
 	ats = &AccessTokenService{
-		tokens: make(map[string]token),
+		tokens: make(map[string]*token),
 	}
 }
 
@@ -76,7 +75,7 @@ func (ats *AccessTokenService) GetToken(ctx context.Context, key string, getToke
 			ch <- err
 			return
 		} else {
-			ats.tokens[key] = token{
+			ats.tokens[key] = &token{
 				accessToken:   accessToken,
 				accessTokenAt: time.Now(),
 				expires:       expires,
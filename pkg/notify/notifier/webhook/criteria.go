@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const DefaultJSONValue = "true"
+
+// The compiled success criteria of a webhook, the status code has been checked when doing the request.
+// The JSON path takes precedence over the regular expressions, and the failure regular expression
+// is checked before the success one.
+type successCriteria struct {
+	jsonPath       []string
+	jsonValue      string
+	reasonJSONPath []string
+	success        *regexp.Regexp
+	failure        *regexp.Regexp
+}
+
+// Compile the success criteria, it returns nil if only the status code is checked.
+func compileCriteria(c *v1alpha1.WebhookSuccessCriteria) (*successCriteria, error) {
+
+	if c == nil {
+		return nil, nil
+	}
+
+	sc := &successCriteria{
+		jsonValue: c.JSONValue,
+	}
+
+	if len(c.JSONPath) > 0 {
+		path, err := parseJSONPath(c.JSONPath)
+		if err != nil {
+			return nil, err
+		}
+		sc.jsonPath = path
+
+		if len(sc.jsonValue) == 0 {
+			sc.jsonValue = DefaultJSONValue
+		}
+	}
+
+	if len(c.ReasonJSONPath) > 0 {
+		path, err := parseJSONPath(c.ReasonJSONPath)
+		if err != nil {
+			return nil, err
+		}
+		sc.reasonJSONPath = path
+	}
+
+	var err error
+	if len(c.SuccessBodyRegex) > 0 {
+		if sc.success, err = regexp.Compile(c.SuccessBodyRegex); err != nil {
+			return nil, fmt.Errorf("invalid success body regex, %s", err.Error())
+		}
+	}
+
+	if len(c.FailureBodyRegex) > 0 {
+		if sc.failure, err = regexp.Compile(c.FailureBodyRegex); err != nil {
+			return nil, fmt.Errorf("invalid failure body regex, %s", err.Error())
+		}
+	}
+
+	if sc.jsonPath == nil && sc.success == nil && sc.failure == nil {
+		return nil, nil
+	}
+
+	return sc, nil
+}
+
+func parseJSONPath(path string) ([]string, error) {
+
+	keys := strings.Split(strings.TrimPrefix(path, "."), ".")
+	for _, k := range keys {
+		if len(k) == 0 {
+			return nil, fmt.Errorf("invalid json path %s", path)
+		}
+	}
+
+	return keys, nil
+}
+
+// Check the response body, it returns the reason of the failure, or an empty string if the call succeeded.
+func (c *successCriteria) check(body []byte) string {
+
+	if c.jsonPath != nil {
+		return c.checkJSON(body)
+	}
+
+	if c.failure != nil {
+		if sub := c.failure.FindSubmatch(body); sub != nil {
+			if len(sub) > 1 {
+				return string(sub[1])
+			}
+			return string(sub[0])
+		}
+	}
+
+	if c.success != nil && !c.success.Match(body) {
+		return fmt.Sprintf("the response body does not match %s", c.success.String())
+	}
+
+	return ""
+}
+
+func (c *successCriteria) checkJSON(body []byte) string {
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("the response body is not json, %s", err.Error())
+	}
+
+	value, ok := lookupJSON(v, c.jsonPath)
+	if !ok {
+		return fmt.Sprintf("the field %s is not found in the response body", strings.Join(c.jsonPath, "."))
+	}
+
+	if value == c.jsonValue {
+		return ""
+	}
+
+	if c.reasonJSONPath != nil {
+		if reason, ok := lookupJSON(v, c.reasonJSONPath); ok && len(reason) > 0 {
+			return reason
+		}
+	}
+
+	return fmt.Sprintf("the field %s is %s, not %s", strings.Join(c.jsonPath, "."), value, c.jsonValue)
+}
+
+// Get the value at the path in the form of string, the keys of the arrays are the indexes.
+func lookupJSON(v interface{}, path []string) (string, bool) {
+
+	for _, k := range path {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			val, ok := t[k]
+			if !ok {
+				return "", false
+			}
+			v = val
+		case []interface{}:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(t) {
+				return "", false
+			}
+			v = t[i]
+		default:
+			return "", false
+		}
+	}
+
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case bool:
+		return strconv.FormatBool(t), true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case nil:
+		return "null", true
+	default:
+		bs, err := json.Marshal(t)
+		if err != nil {
+			return "", false
+		}
+		return string(bs), true
+	}
+}
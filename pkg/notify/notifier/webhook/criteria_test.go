@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+)
+
+func TestCheckCriteria(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		criteria *v1alpha1.WebhookSuccessCriteria
+		body     string
+		want     string
+	}{
+		{
+			name:     "logical failure matching the failure regex",
+			criteria: &v1alpha1.WebhookSuccessCriteria{FailureBodyRegex: `"accepted":\s*false.*"reason":\s*"([^"]*)"`},
+			body:     `{"accepted":false,"reason":"quota exceeded"}`,
+			want:     "quota exceeded",
+		},
+		{
+			name:     "success not matching the failure regex",
+			criteria: &v1alpha1.WebhookSuccessCriteria{FailureBodyRegex: `"accepted":\s*false`},
+			body:     `{"accepted":true}`,
+		},
+		{
+			name:     "success matching the success regex",
+			criteria: &v1alpha1.WebhookSuccessCriteria{SuccessBodyRegex: `"accepted":\s*true`},
+			body:     `{"accepted":true}`,
+		},
+		{
+			name:     "logical failure not matching the success regex",
+			criteria: &v1alpha1.WebhookSuccessCriteria{SuccessBodyRegex: `"accepted":\s*true`},
+			body:     `{"accepted":false}`,
+			want:     `the response body does not match "accepted":\s*true`,
+		},
+		{
+			name: "failure regex before success regex",
+			criteria: &v1alpha1.WebhookSuccessCriteria{
+				SuccessBodyRegex: `"accepted"`,
+				FailureBodyRegex: `"error":"(\w+)"`,
+			},
+			body: `{"accepted":true,"error":"partial"}`,
+			want: "partial",
+		},
+		{
+			name:     "json path success",
+			criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "accepted"},
+			body:     `{"accepted":true}`,
+		},
+		{
+			name:     "json path logical failure with reason",
+			criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "accepted", ReasonJSONPath: "reason"},
+			body:     `{"accepted":false,"reason":"quota exceeded"}`,
+			want:     "quota exceeded",
+		},
+		{
+			name:     "json path with index and value",
+			criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "results.0.code", JSONValue: "0"},
+			body:     `{"results":[{"code":1}]}`,
+			want:     "the field results.0.code is 1, not 0",
+		},
+		{
+			name:     "json path not found",
+			criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "accepted"},
+			body:     `{}`,
+			want:     "the field accepted is not found in the response body",
+		},
+		{
+			name: "json path takes precedence over regexes",
+			criteria: &v1alpha1.WebhookSuccessCriteria{
+				JSONPath:         "accepted",
+				FailureBodyRegex: `reason`,
+			},
+			body: `{"accepted":true,"reason":"none"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compileCriteria(tt.criteria)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.check([]byte(tt.body)); got != tt.want {
+				t.Fatalf("check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileCriteria(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		criteria *v1alpha1.WebhookSuccessCriteria
+		wantNil  bool
+		wantErr  bool
+	}{
+		{name: "not set", wantNil: true},
+		{name: "empty", criteria: &v1alpha1.WebhookSuccessCriteria{}, wantNil: true},
+		{name: "invalid success regex", criteria: &v1alpha1.WebhookSuccessCriteria{SuccessBodyRegex: "("}, wantErr: true},
+		{name: "invalid failure regex", criteria: &v1alpha1.WebhookSuccessCriteria{FailureBodyRegex: "["}, wantErr: true},
+		{name: "invalid json path", criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "result..code"}, wantErr: true},
+		{name: "invalid reason json path", criteria: &v1alpha1.WebhookSuccessCriteria{JSONPath: "ok", ReasonJSONPath: "."}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := compileCriteria(tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compileCriteria() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (c == nil) != tt.wantNil {
+				t.Fatalf("compileCriteria() = %v, want nil %v", c, tt.wantNil)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"fmt"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"time"
+)
+
+const (
+	PayloadV1 = "v1"
+	PayloadV2 = "v2"
+)
+
+// The builder of the payload of a schema version, the message is the text rendered by the custom template,
+// it is empty if the template is not set.
+type payloadBuilder func(data template.Data, message string) interface{}
+
+// The payload of v1, the notification data in the same shape as the payload without a version,
+// with the version and the message.
+type payloadV1 struct {
+	SchemaVersion string `json:"schemaVersion"`
+	template.Data
+	Message string `json:"message,omitempty"`
+}
+
+// The payload of v2, the labels of the group are in `group`, the alerts are counted by status,
+// and the `endsAt` of the alerts is omitted if it is unknown.
+type payloadV2 struct {
+	SchemaVersion string     `json:"schemaVersion"`
+	Status        string     `json:"status"`
+	Group         groupV2    `json:"group"`
+	Counts        countsV2   `json:"counts"`
+	Alerts        []*alertV2 `json:"alerts"`
+	ExternalURL   string     `json:"externalURL,omitempty"`
+	Message       string     `json:"message,omitempty"`
+}
+
+type groupV2 struct {
+	Receiver          string      `json:"receiver,omitempty"`
+	Labels            template.KV `json:"labels"`
+	CommonLabels      template.KV `json:"commonLabels"`
+	CommonAnnotations template.KV `json:"commonAnnotations"`
+}
+
+type countsV2 struct {
+	Firing   int `json:"firing"`
+	Resolved int `json:"resolved"`
+}
+
+type alertV2 struct {
+	Fingerprint  string      `json:"fingerprint,omitempty"`
+	Status       string      `json:"status"`
+	Labels       template.KV `json:"labels"`
+	Annotations  template.KV `json:"annotations"`
+	StartsAt     time.Time   `json:"startsAt"`
+	EndsAt       *time.Time  `json:"endsAt,omitempty"`
+	GeneratorURL string      `json:"generatorURL,omitempty"`
+}
+
+var payloadBuilders = map[string]payloadBuilder{
+	PayloadV1: buildPayloadV1,
+	PayloadV2: buildPayloadV2,
+}
+
+// The builder of the schema version, an error is returned if the version is unknown.
+func getPayloadBuilder(version string) (payloadBuilder, error) {
+
+	b, ok := payloadBuilders[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown payload version %q, must be %s or %s", version, PayloadV1, PayloadV2)
+	}
+
+	return b, nil
+}
+
+func buildPayloadV1(data template.Data, message string) interface{} {
+
+	return &payloadV1{
+		SchemaVersion: PayloadV1,
+		Data:          data,
+		Message:       message,
+	}
+}
+
+func buildPayloadV2(data template.Data, message string) interface{} {
+
+	p := &payloadV2{
+		SchemaVersion: PayloadV2,
+		Status:        data.Status,
+		Group: groupV2{
+			Receiver:          data.Receiver,
+			Labels:            nonNilKV(data.GroupLabels),
+			CommonLabels:      nonNilKV(data.CommonLabels),
+			CommonAnnotations: nonNilKV(data.CommonAnnotations),
+		},
+		Alerts:      []*alertV2{},
+		ExternalURL: data.ExternalURL,
+		Message:     message,
+	}
+
+	for _, alert := range data.Alerts {
+		a := &alertV2{
+			Fingerprint:  alert.Fingerprint,
+			Status:       alert.Status,
+			Labels:       nonNilKV(alert.Labels),
+			Annotations:  nonNilKV(alert.Annotations),
+			StartsAt:     alert.StartsAt,
+			GeneratorURL: alert.GeneratorURL,
+		}
+		if !alert.EndsAt.IsZero() {
+			endsAt := alert.EndsAt
+			a.EndsAt = &endsAt
+		}
+		p.Alerts = append(p.Alerts, a)
+
+		if alert.Status == string(model.AlertResolved) {
+			p.Counts.Resolved++
+		} else {
+			p.Counts.Firing++
+		}
+	}
+
+	return p
+}
+
+// The empty kv is encoded as `{}` rather than `null`.
+func nonNilKV(kv template.KV) template.KV {
+
+	if kv == nil {
+		return template.KV{}
+	}
+
+	return kv
+}
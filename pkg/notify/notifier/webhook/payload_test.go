@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func testPayloadData() template.Data {
+
+	return template.Data{
+		Receiver:    "prometheus",
+		Status:      "firing",
+		GroupLabels: template.KV{"alertname": "KubePodCrashLooping"},
+		ExternalURL: "http://alertmanager:9093",
+		Alerts: template.Alerts{
+			{
+				Status:      "firing",
+				Labels:      template.KV{"alertname": "KubePodCrashLooping", "pod": "web-0"},
+				StartsAt:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				Fingerprint: "a1",
+			},
+			{
+				Status:      "resolved",
+				Labels:      template.KV{"alertname": "KubePodCrashLooping", "pod": "web-1"},
+				StartsAt:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndsAt:      time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC),
+				Fingerprint: "b2",
+			},
+		},
+	}
+}
+
+func TestGetPayloadBuilder(t *testing.T) {
+
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{PayloadV1, false},
+		{PayloadV2, false},
+		{"v3", true},
+		{"V1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if _, err := getPayloadBuilder(tt.version); (err != nil) != tt.wantErr {
+				t.Fatalf("getPayloadBuilder(%q) error = %v, want error %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildPayloadV2(t *testing.T) {
+
+	b, err := json.Marshal(buildPayloadV2(testPayloadData(), "crash looping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"schemaVersion": "v2",
+		"status":        "firing",
+		"group": map[string]interface{}{
+			"receiver":          "prometheus",
+			"labels":            map[string]interface{}{"alertname": "KubePodCrashLooping"},
+			"commonLabels":      map[string]interface{}{},
+			"commonAnnotations": map[string]interface{}{},
+		},
+		"counts": map[string]interface{}{"firing": float64(1), "resolved": float64(1)},
+		"alerts": []interface{}{
+			// The endsAt of the firing alert is omitted as it is unknown.
+			map[string]interface{}{
+				"fingerprint": "a1",
+				"status":      "firing",
+				"labels":      map[string]interface{}{"alertname": "KubePodCrashLooping", "pod": "web-0"},
+				"annotations": map[string]interface{}{},
+				"startsAt":    "2026-01-01T00:00:00Z",
+			},
+			map[string]interface{}{
+				"fingerprint": "b2",
+				"status":      "resolved",
+				"labels":      map[string]interface{}{"alertname": "KubePodCrashLooping", "pod": "web-1"},
+				"annotations": map[string]interface{}{},
+				"startsAt":    "2026-01-01T00:00:00Z",
+				"endsAt":      "2026-01-01T01:00:00Z",
+			},
+		},
+		"externalURL": "http://alertmanager:9093",
+		"message":     "crash looping",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("payload = %s", b)
+	}
+}
+
+func TestNotifyPayloadVersion(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		version string
+		// The top-level fields of the payload checked.
+		want map[string]interface{}
+	}{
+		{
+			name: "no version",
+			want: map[string]interface{}{"schemaVersion": nil, "status": "firing", "receiver": "prometheus"},
+		},
+		{
+			name:    "v1",
+			version: PayloadV1,
+			want:    map[string]interface{}{"schemaVersion": "v1", "status": "firing", "receiver": "prometheus"},
+		},
+		{
+			name:    "v2",
+			version: PayloadV2,
+			want:    map[string]interface{}{"schemaVersion": "v2", "status": "firing", "receiver": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer s.Close()
+
+			w := newTestWebhook(s.URL, nil)
+			w.PayloadVersion = tt.version
+			n := NewWebhookNotifier(log.NewNopLogger(), []config.Receiver{w}, &config.Config{})
+			if errs := n.Notify(context.Background(), testPayloadData()); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifierSkipsUnknownPayloadVersion(t *testing.T) {
+
+	invalid := newTestWebhook("http://invalid", nil)
+	invalid.PayloadVersion = "v3"
+	valid := newTestWebhook("http://valid", nil)
+	valid.PayloadVersion = PayloadV2
+
+	n := NewWebhookNotifier(log.NewNopLogger(), []config.Receiver{invalid, valid}, &config.Config{}).(*Notifier)
+	if len(n.webhooks) != 1 || n.webhooks[0].WebhookConfig.URL != "http://valid" {
+		t.Fatalf("expect only the valid webhook, got %d", len(n.webhooks))
+	}
+}
@@ -3,27 +3,38 @@ package webhook
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
-	"github.com/mwitkow/go-conntrack"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/template"
 	"net/http"
-	"net/url"
+	"strings"
 	"time"
 )
 
 const (
 	DefaultSendTimeout = time.Second * 5
 	DefaultTemplate    = `{{ template "webhook.default.message" . }}`
+	// The same as the default of the http client.
+	DefaultMaxRedirects = 10
 )
 
+// ResponseError means the webhook returns a logical failure which is matched by the success criteria.
+type ResponseError struct {
+	URL    string
+	Reason string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("webhook %s returns failure, reason: %s", e.URL, e.Reason)
+}
+
 type Notifier struct {
 	notifierCfg  *config.Config
 	webhooks     []*config.Webhook
@@ -31,6 +42,8 @@ type Notifier struct {
 	logger       log.Logger
 	template     *notifier.Template
 	templateName string
+	// The compiled success criteria of the webhooks.
+	criteria map[*config.Webhook]*successCriteria
 }
 
 func NewWebhookNotifier(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier {
@@ -42,7 +55,7 @@ func NewWebhookNotifier(logger log.Logger, receivers []config.Receiver, notifier
 	}
 	tmpl, err := notifier.NewTemplate(path)
 	if err != nil {
-		_ = level.Error(logger).Log("msg", "WebhookNotifier: get template error", "error", err.Error())
+		notifier.ReportTemplateError(logger, notifierCfg, "Webhook", receivers, err)
 		return nil
 	}
 
@@ -52,6 +65,7 @@ func NewWebhookNotifier(logger log.Logger, receivers []config.Receiver, notifier
 		logger:       logger,
 		template:     tmpl,
 		templateName: DefaultTemplate,
+		criteria:     make(map[*config.Webhook]*successCriteria),
 	}
 
 	if opts != nil && opts.Webhook != nil {
@@ -74,9 +88,25 @@ func NewWebhookNotifier(logger log.Logger, receivers []config.Receiver, notifier
 		}
 
 		if receiver.WebhookConfig == nil {
-			_ = level.Warn(logger).Log("msg", "WebhookNotifier: ignore receiver because of empty config")
+			notifier.ReportConfigError(logger, notifierCfg, "Webhook", receiver, fmt.Errorf("no config is found"))
+			continue
+		}
+
+		if len(receiver.PayloadVersion) > 0 {
+			if _, err := getPayloadBuilder(receiver.PayloadVersion); err != nil {
+				notifier.ReportConfigError(logger, notifierCfg, "Webhook", receiver, err)
+				continue
+			}
+		}
+
+		c, err := compileCriteria(receiver.WebhookConfig.SuccessCriteria)
+		if err != nil {
+			notifier.ReportConfigError(logger, notifierCfg, "Webhook", receiver, err)
 			continue
 		}
+		if c != nil {
+			n.criteria[receiver] = c
+		}
 
 		n.webhooks = append(n.webhooks, receiver)
 	}
@@ -86,26 +116,44 @@ func NewWebhookNotifier(logger log.Logger, receivers []config.Receiver, notifier
 
 func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 
-	var value interface{} = data
-	if n.templateName != DefaultTemplate {
-		msg, err := n.template.TempleText(n.templateName, data, n.logger)
-		if err != nil {
-			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: generate message error", "error", err.Error())
-			return []error{err}
-		}
-
-		value = msg
-	}
-
-	send := func(w *config.Webhook) error {
+	cache := notifier.NewRenderCache()
+	send := func(w *config.Webhook) (err error) {
 
 		start := time.Now()
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "WebhookNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Webhook", time.Since(start), err)
 		}()
 
+		var value interface{} = data
+		message := ""
+		if n.templateName != DefaultTemplate {
+			// The webhooks with the same optional templates share the rendered message.
+			tmpl := n.template.WithOptionalTemplates(w.OptionalTemplates)
+			message, err = cache.Render(tmpl.CacheKey(n.templateName), data, func() (string, error) {
+				return tmpl.TempleText(n.templateName, data, n.logger)
+			})
+			if err != nil {
+				_ = level.Error(n.logger).Log("msg", "WebhookNotifier: generate message error", "error", err.Error())
+				return err
+			}
+
+			value = message
+		}
+
+		// The receiver pinned to a payload version gets the envelope of the version,
+		// the message rendered by the custom template is in the envelope.
+		payload := value
+		if len(w.PayloadVersion) > 0 {
+			build, err := getPayloadBuilder(w.PayloadVersion)
+			if err != nil {
+				return err
+			}
+			payload = build(data, message)
+		}
+
 		var buf bytes.Buffer
-		if err := json.NewEncoder(&buf).Encode(value); err != nil {
+		if err := json.NewEncoder(&buf).Encode(payload); err != nil {
 			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: encode message error", "error", err.Error())
 			return err
 		}
@@ -116,45 +164,34 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 		}
 		request.Header.Set("Content-Type", "application/json")
 
-		if w.WebhookConfig.HttpConfig.BearerToken != nil {
-			bearer, err := n.notifierCfg.GetSecretData(w.GetNamespace(), w.WebhookConfig.HttpConfig.BearerToken)
-			if err != nil {
-				_ = level.Error(n.logger).Log("msg", "WebhookNotifier: get bearer token error", "error", err.Error())
-				return err
-			}
-
-			request.Header.Set("Authorization", bearer)
-		} else if w.WebhookConfig.HttpConfig.BasicAuth != nil {
-			pass := ""
-			if w.WebhookConfig.HttpConfig.BasicAuth.Password != nil {
-				p, err := n.notifierCfg.GetSecretData(w.GetNamespace(), w.WebhookConfig.HttpConfig.BasicAuth.Password)
-				if err != nil {
-					_ = level.Error(n.logger).Log("msg", "WebhookNotifier: get password error", "error", err.Error())
-					return err
-				}
-
-				pass = p
-			}
-			request.SetBasicAuth(w.WebhookConfig.HttpConfig.BasicAuth.Username, pass)
+		if err := notifier.SetAuthorization(n.notifierCfg, w.GetNamespace(), w.WebhookConfig.HttpConfig, request); err != nil {
+			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: set authorization error", "error", err.Error())
+			return err
 		}
 
-		transport, err := n.getTransport(w)
+		transport, err := notifier.NewTransport(n.notifierCfg, "Webhook", w.GetNamespace(), w.WebhookConfig.URL, w.WebhookConfig.HttpConfig)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: get transport error", "error", err.Error())
 			return err
 		}
 
 		client := &http.Client{
-			Transport: transport,
-			Timeout:   n.timeout,
+			Transport:     transport,
+			Timeout:       n.timeout,
+			CheckRedirect: checkRedirect(w.Redirect),
 		}
 
-		_, err = notifier.DoHttpRequest(ctx, client, request)
+		body, err := notifier.DoHttpRequest(ctx, client, request)
 		if err != nil {
 			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: do http request error", "error", err.Error())
 			return err
 		}
 
+		if err := n.checkResponse(w, body); err != nil {
+			_ = level.Error(n.logger).Log("msg", "WebhookNotifier: check response error", "error", err.Error())
+			return err
+		}
+
 		_ = level.Debug(n.logger).Log("msg", "WebhookNotifier: send message", "to", w.WebhookConfig.URL)
 
 		return nil
@@ -171,79 +208,60 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 	return group.Wait()
 }
 
-func (n *Notifier) getTransport(w *config.Webhook) (http.RoundTripper, error) {
+// The redirect policy of the receiver. The Authorization header is removed when redirected to another host
+// unless it is preserved, and it is restored if it is preserved, as the http client removes it for some hosts.
+func checkRedirect(r *v1alpha1.WebhookRedirect) func(req *http.Request, via []*http.Request) error {
 
-	transport := &http.Transport{
-		DisableKeepAlives:  false,
-		DisableCompression: true,
-		DialContext: conntrack.NewDialContextFunc(
-			conntrack.DialWithTracing(),
-			conntrack.DialWithName(w.WebhookConfig.URL),
-		),
+	maxRedirects := DefaultMaxRedirects
+	follow, preserve := true, false
+	if r != nil {
+		if r.Follow != nil {
+			follow = *r.Follow
+		}
+		if r.MaxRedirects > 0 {
+			maxRedirects = r.MaxRedirects
+		}
+		preserve = r.PreserveAuthorization
 	}
 
-	if c := w.WebhookConfig.HttpConfig; c != nil {
-
-		if c.TLSConfig != nil {
-			tlsConfig := &tls.Config{InsecureSkipVerify: c.TLSConfig.InsecureSkipVerify}
-
-			// If a CA cert is provided then let's read it in so we can validate the
-			// scrape target's certificate properly.
-			if c.TLSConfig.RootCA != nil {
-				if ca, err := n.notifierCfg.GetSecretData(w.GetNamespace(), c.TLSConfig.RootCA); err != nil {
-					return nil, err
-				} else {
-					caCertPool := x509.NewCertPool()
-					if !caCertPool.AppendCertsFromPEM([]byte(ca)) {
-						return nil, err
-					}
-					tlsConfig.RootCAs = caCertPool
-				}
-			}
-
-			if len(c.TLSConfig.ServerName) > 0 {
-				tlsConfig.ServerName = c.TLSConfig.ServerName
-			}
+	return func(req *http.Request, via []*http.Request) error {
 
-			// If a client cert & key is provided then configure TLS config accordingly.
-			if c.TLSConfig.ClientCertificate != nil {
-				if c.TLSConfig.Cert != nil && c.TLSConfig.Key == nil {
-					return nil, fmt.Errorf("client cert file specified without client key file")
-				} else if c.TLSConfig.Cert == nil && c.TLSConfig.Key != nil {
-					return nil, fmt.Errorf("client key file specified without client cert file")
-				} else if c.TLSConfig.Cert != nil && c.TLSConfig.Key != nil {
-					key, err := n.notifierCfg.GetSecretData(w.GetNamespace(), c.TLSConfig.Key)
-					if err != nil {
-						return nil, err
-					}
-
-					cert, err := n.notifierCfg.GetSecretData(w.GetNamespace(), c.TLSConfig.Cert)
-					if err != nil {
-						return nil, err
-					}
-
-					tlsCert, err := tls.X509KeyPair([]byte(cert), []byte(key))
-					if err != nil {
-						return nil, err
-					}
-					tlsConfig.Certificates = []tls.Certificate{tlsCert}
-				}
-			}
+		if !follow {
+			return http.ErrUseLastResponse
+		}
 
-			transport.TLSClientConfig = tlsConfig
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
 		}
 
-		if len(c.ProxyURL) > 0 {
-			var proxy func(*http.Request) (*url.URL, error)
-			if u, err := url.Parse(c.ProxyURL); err != nil {
-				return nil, err
-			} else {
-				proxy = http.ProxyURL(u)
+		origin := via[0]
+		if strings.EqualFold(req.URL.Host, origin.URL.Host) || preserve {
+			if auth := origin.Header.Get("Authorization"); len(auth) > 0 {
+				req.Header.Set("Authorization", auth)
 			}
-
-			transport.Proxy = proxy
+			return nil
 		}
+
+		req.Header.Del("Authorization")
+		return nil
+	}
+}
+
+// Check the response body with the success criteria, the status code has been checked when doing the request.
+func (n *Notifier) checkResponse(w *config.Webhook, body []byte) error {
+
+	c, ok := n.criteria[w]
+	if !ok {
+		return nil
 	}
 
-	return transport, nil
+	reason := c.check(body)
+	if len(reason) == 0 {
+		return nil
+	}
+
+	return &ResponseError{
+		URL:    w.WebhookConfig.URL,
+		Reason: reason,
+	}
 }
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func newTestWebhook(url string, criteria *v1alpha1.WebhookSuccessCriteria) *config.Webhook {
+
+	w := config.NewWebhookReceiver().(*config.Webhook)
+	w.SetNamespace("default")
+	w.WebhookConfig = &config.WebhookConfig{URL: url, HttpConfig: &v1alpha1.HTTPClientConfig{}, SuccessCriteria: criteria}
+	return w
+}
+
+func TestNotifyChecksResponse(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantReason string
+		wantErr    bool
+	}{
+		{name: "success", status: http.StatusOK, body: `{"accepted":true}`},
+		{name: "logical failure", status: http.StatusOK, body: `{"accepted":false,"reason":"quota exceeded"}`, wantReason: "quota exceeded", wantErr: true},
+		// The status code is checked before the body.
+		{name: "status code failure", status: http.StatusInternalServerError, body: `{"accepted":true}`, wantErr: true},
+	}
+
+	criteria := &v1alpha1.WebhookSuccessCriteria{
+		SuccessBodyRegex: `"accepted":true`,
+		FailureBodyRegex: `"reason":"([^"]*)"`,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer s.Close()
+
+			n := NewWebhookNotifier(log.NewNopLogger(), []config.Receiver{newTestWebhook(s.URL, criteria)}, &config.Config{})
+			errs := n.Notify(context.Background(), template.Data{Status: "firing", Alerts: template.Alerts{{Status: "firing"}}})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Fatalf("Notify() = %v, want error %v", errs, tt.wantErr)
+			}
+			if len(tt.wantReason) == 0 {
+				return
+			}
+
+			e, ok := errs[0].(*ResponseError)
+			if !ok {
+				t.Fatalf("expect a response error, got %v", errs[0])
+			}
+			if e.Reason != tt.wantReason || e.URL != s.URL {
+				t.Fatalf("unexpected response error %+v", e)
+			}
+		})
+	}
+}
+
+func TestNotifierSkipsInvalidCriteria(t *testing.T) {
+
+	n := NewWebhookNotifier(log.NewNopLogger(), []config.Receiver{
+		newTestWebhook("http://invalid", &v1alpha1.WebhookSuccessCriteria{SuccessBodyRegex: "("}),
+		newTestWebhook("http://valid", &v1alpha1.WebhookSuccessCriteria{SuccessBodyRegex: "ok"}),
+	}, &config.Config{}).(*Notifier)
+
+	if len(n.webhooks) != 1 || n.webhooks[0].WebhookConfig.URL != "http://valid" {
+		t.Fatalf("expect only the valid webhook, got %d", len(n.webhooks))
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+
+	follow, notFollow := true, false
+	tests := []struct {
+		name     string
+		redirect *v1alpha1.WebhookRedirect
+		to       string
+		// The number of the requests before this one.
+		via      int
+		wantAuth string
+		wantErr  bool
+		wantLast bool
+	}{
+		{name: "same host", to: "http://hook.example.com/next", via: 1, wantAuth: "Bearer token"},
+		{name: "another host", to: "http://evil.example.com/next", via: 1, wantAuth: ""},
+		{name: "another port", to: "http://hook.example.com:8080/next", via: 1, wantAuth: ""},
+		{name: "authorization preserved", redirect: &v1alpha1.WebhookRedirect{PreserveAuthorization: true}, to: "http://other.example.com/next", via: 1, wantAuth: "Bearer token"},
+		{name: "not followed", redirect: &v1alpha1.WebhookRedirect{Follow: &notFollow}, to: "http://hook.example.com/next", via: 1, wantLast: true},
+		{name: "followed explicitly", redirect: &v1alpha1.WebhookRedirect{Follow: &follow}, to: "http://hook.example.com/next", via: 1, wantAuth: "Bearer token"},
+		{name: "default max redirects", to: "http://hook.example.com/next", via: DefaultMaxRedirects, wantAuth: "Bearer token"},
+		{name: "too many redirects", to: "http://hook.example.com/next", via: DefaultMaxRedirects + 1, wantErr: true},
+		{name: "max redirects", redirect: &v1alpha1.WebhookRedirect{MaxRedirects: 2}, to: "http://hook.example.com/next", via: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := httptest.NewRequest(http.MethodPost, "http://hook.example.com/", nil)
+			origin.Header.Set("Authorization", "Bearer token")
+			via := []*http.Request{origin}
+			for len(via) < tt.via {
+				via = append(via, httptest.NewRequest(http.MethodPost, "http://hook.example.com/", nil))
+			}
+
+			// The http client copies the headers of the previous request.
+			req := httptest.NewRequest(http.MethodPost, tt.to, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			err := checkRedirect(tt.redirect)(req, via)
+			if tt.wantLast {
+				if err != http.ErrUseLastResponse {
+					t.Fatalf("checkRedirect() = %v, want %v", err, http.ErrUseLastResponse)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkRedirect() = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && req.Header.Get("Authorization") != tt.wantAuth {
+				t.Fatalf("Authorization = %q, want %q", req.Header.Get("Authorization"), tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestNotifyRedirect(t *testing.T) {
+
+	notFollow := false
+	tests := []struct {
+		name     string
+		redirect *v1alpha1.WebhookRedirect
+		wantErr  bool
+		// Whether the request reaches the target of the redirect.
+		wantSent bool
+	}{
+		{name: "followed by default", wantSent: true},
+		{name: "not followed", redirect: &v1alpha1.WebhookRedirect{Follow: &notFollow}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := false
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+			}))
+			defer target.Close()
+
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+			}))
+			defer s.Close()
+
+			w := newTestWebhook(s.URL, nil)
+			w.Redirect = tt.redirect
+			n := NewWebhookNotifier(log.NewNopLogger(), []config.Receiver{w}, &config.Config{})
+			errs := n.Notify(context.Background(), template.Data{Status: "firing", Alerts: template.Alerts{{Status: "firing"}}})
+			if (len(errs) > 0) != tt.wantErr {
+				t.Fatalf("Notify() = %v, want error %v", errs, tt.wantErr)
+			}
+			if sent != tt.wantSent {
+				t.Fatalf("sent to the target = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/template"
 	"net/http"
 	"strings"
@@ -39,6 +40,8 @@ type Notifier struct {
 	ats            *notifier.AccessTokenService
 	messageMaxSize int
 	tokenExpires   time.Duration
+	// The mode to generate message with the status templates.
+	statusTemplateMode string
 }
 
 type weChatMessageContent struct {
@@ -70,7 +73,7 @@ func NewWechatNotifier(logger log.Logger, receivers []config.Receiver, notifierC
 	}
 	tmpl, err := notifier.NewTemplate(path)
 	if err != nil {
-		_ = level.Error(logger).Log("msg", "WechatNotifier: get template error", "error", err.Error())
+		notifier.ReportTemplateError(logger, notifierCfg, "Wechat", receivers, err)
 		return nil
 	}
 
@@ -105,6 +108,8 @@ func NewWechatNotifier(logger log.Logger, receivers []config.Receiver, notifierC
 		if opts.Wechat.TokenExpires != 0 {
 			n.tokenExpires = opts.Wechat.TokenExpires
 		}
+
+		n.statusTemplateMode = opts.Wechat.StatusTemplateMode
 	}
 
 	for _, r := range receivers {
@@ -115,7 +120,7 @@ func NewWechatNotifier(logger log.Logger, receivers []config.Receiver, notifierC
 		}
 
 		if receiver.WechatConfig == nil {
-			_ = level.Warn(logger).Log("msg", "WechatNotifier: ignore receiver because of empty config")
+			notifier.ReportConfigError(logger, notifierCfg, "Wechat", receiver, fmt.Errorf("no config is found"))
 			continue
 		}
 
@@ -158,11 +163,14 @@ func NewWechatNotifier(logger log.Logger, receivers []config.Receiver, notifierC
 
 func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 
-	send := func(w *config.Wechat, msg string) error {
+	data = notifier.CollapseHighCardinality(n.notifierCfg.ReceiverOpts, data)
+
+	send := func(w *config.Wechat, msg string) (err error) {
 
 		start := time.Now()
 		defer func() {
 			_ = level.Debug(n.logger).Log("msg", "WechatNotifier: send message", "used", time.Since(start).String())
+			stats.GetLatencyRecorder().Record("Wechat", time.Since(start), err)
 		}()
 
 		wechatMsg := &weChatMessage{
@@ -211,7 +219,7 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 			}
 			request.Header.Set("Content-Type", "application/json")
 
-			body, err := notifier.DoHttpRequest(ctx, nil, request)
+			body, err := notifier.DoHttpRequest(ctx, notifier.GetDefaultClient("Wechat"), request)
 			if err != nil {
 				_ = level.Error(n.logger).Log("msg", "WechatNotifier: do http error", "error", err)
 				return false, err
@@ -247,15 +255,18 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 		return err
 	}
 
-	messages, err := n.template.Split(data, MessageMaxSize, n.templateName, n.logger)
-	if err != nil {
-		_ = level.Error(n.logger).Log("msg", "WechatNotifier: split message error", "error", err.Error())
-		return nil
-	}
-
 	group := async.NewGroup(ctx)
 	for _, w := range n.wechat {
 
+		// The size of the source links is reserved when splitting the message.
+		links := notifier.NewSourceLink(n.notifierCfg.ReceiverOpts, w.SourceLink).PlainText(data)
+		tmpl := n.template.WithAnnotationSection(w.AnnotationSection).WithOptionalTemplates(w.OptionalTemplates).WithAlertAccent(w.AlertAccent)
+		messages, err := tmpl.SplitByStatus(data, MessageMaxSize-len(links), tmpl.SelectTemplate(data, "text", n.templateName, n.logger), n.statusTemplateMode, n.logger)
+		if err != nil {
+			_ = level.Error(n.logger).Log("msg", "WechatNotifier: split message error", "error", err.Error())
+			continue
+		}
+
 		us, ps, ts := 0, 0, 0
 		toUser := strings.Split(w.ToUser, "|")
 		toParty := strings.Split(w.ToParty, "|")
@@ -271,8 +282,12 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 			nw.ToParty = batch(toParty, &ps, ToPartyBatchSize)
 			nw.ToTag = batch(toTag, &ts, ToTagBatchSize)
 
-			for _, m := range messages {
+			for i, m := range messages {
 				msg := m
+				// The source links are added to the last message.
+				if i == len(messages)-1 {
+					msg += links
+				}
 				group.Add(func(stopCh chan interface{}) {
 					stopCh <- send(nw, msg)
 				})
@@ -283,6 +298,20 @@ func (n *Notifier) Notify(ctx context.Context, data template.Data) []error {
 	return group.Wait()
 }
 
+// WarmUp fetches the access tokens of the receivers in advance, the tokens are cached by the access token service.
+func (n *Notifier) WarmUp(ctx context.Context) []error {
+
+	var errs []error
+	for _, w := range n.wechat {
+		if _, err := n.getToken(ctx, w); err != nil {
+			_ = level.Error(n.logger).Log("msg", "WechatNotifier: warm up error", "error", err.Error())
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
 func (n *Notifier) getToken(ctx context.Context, w *config.Wechat) (string, error) {
 
 	get := func(ctx context.Context) (string, time.Duration, error) {
@@ -312,7 +341,7 @@ func (n *Notifier) getToken(ctx context.Context, w *config.Wechat) (string, erro
 		}
 		request.Header.Set("Content-Type", "application/json")
 
-		body, err := notifier.DoHttpRequest(ctx, nil, request)
+		body, err := notifier.DoHttpRequest(ctx, notifier.GetDefaultClient("Wechat"), request)
 		if err != nil {
 			return "", 0, err
 		}
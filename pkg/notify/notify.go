@@ -2,16 +2,23 @@ package notify
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/dingtalk"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier/elasticsearch"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/email"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier/grpc"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier/pushgateway"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/slack"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier/splunk"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/webhook"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/wechat"
 	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
 )
 
 type Factory func(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) notifier.Notifier
@@ -26,6 +33,10 @@ func init() {
 	Register("Slack", slack.NewSlackNotifier)
 	Register("Webhook", webhook.NewWebhookNotifier)
 	Register("DingTalk", dingtalk.NewDingTalkNotifier)
+	Register("Elasticsearch", elasticsearch.NewElasticsearchNotifier)
+	Register("Splunk", splunk.NewSplunkNotifier)
+	Register("GRPC", grpc.NewGrpcNotifier)
+	Register("Pushgateway", pushgateway.NewPushgatewayNotifier)
 }
 
 func Register(name string, factory Factory) {
@@ -39,27 +50,227 @@ func Register(name string, factory Factory) {
 type Notification struct {
 	Notifiers []notifier.Notifier
 	Data      template.Data
+	// The notifications of the receivers which only receive the firing or resolved alerts.
+	partitions []*Notification
+	// The error holding the notification, such as the failed enrichment in `closed` failure policy.
+	err error
+}
+
+// ApplyOptions applies the global options of the rendering, it is called when the options are changed,
+// instead of each notification.
+func ApplyOptions(opts *v1alpha1.Options) {
+
+	var contextPrecedence string
+	var renderConcurrency int
+	if opts != nil && opts.Global != nil {
+		contextPrecedence = opts.Global.ContextPrecedence
+		renderConcurrency = opts.Global.RenderConcurrency
+	}
+
+	notifier.SetContextPrecedence(contextPrecedence)
+	notifier.GetRenderPool().SetLimit(renderConcurrency)
 }
 
 func NewNotification(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config, data template.Data) *Notification {
+	return NewPreprocessedNotification(logger, receivers, notifierCfg, Preprocess(logger, notifierCfg, data))
+}
+
+// Preprocess the alerts of the notification, such as dropping the duplicate alerts and setting the reason.
+// The states of the groups are updated, so the data must be preprocessed once.
+func Preprocess(logger log.Logger, notifierCfg *config.Config, data template.Data) template.Data {
+
+	var routingTrace *v1alpha1.RoutingTrace
+	if opts := notifierCfg.ReceiverOpts; opts != nil && opts.Global != nil {
+		routingTrace = opts.Global.RoutingTrace
+	}
+	setRoutingTrace(routingTrace)
+
+	return preprocess(logger, notifierCfg.ReceiverOpts, data)
+}
+
+// NewPreprocessedNotification creates the notification of the data returned by Preprocess, such as the data buffered by the batcher.
+func NewPreprocessedNotification(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config, data template.Data) *Notification {
 
 	n := &Notification{Data: data}
 
-	if receivers == nil || len(receivers) == 0 {
+	// Nothing to send if all the alerts are dropped, the alerts may name the receivers even if the namespace has none.
+	if len(n.Data.Alerts) == 0 {
+		return n
+	}
+	if len(receivers) == 0 && !routingByLabel(notifierCfg) {
+		traceDropped(n.Data.Alerts, "no receiver matched")
+		return n
+	}
+
+	var ackOpts *v1alpha1.Acknowledgement
+	if opts := notifierCfg.ReceiverOpts; opts != nil && opts.Global != nil {
+		ackOpts = opts.Global.Acknowledgement
+
+		var err error
+		if n.Data, err = enrich(logger, notifierCfg.Reader(), opts.Global.Enrichment, n.Data); err != nil {
+			traceDropped(n.Data.Alerts, fmt.Sprintf("held by the failed enrichment, %s", err.Error()))
+			n.err = err
+			return n
+		}
+	}
+
+	// The repeat notification of an acknowledged group is marked, and only sent to the receivers it is downgraded to.
+	acked := false
+	if ackOpts != nil {
+		if ack := acknowledged(n.Data); ack != nil {
+			n.Data = markAcknowledged(n.Data, ack)
+			acked = true
+		}
+	}
+
+	routes := routeByLabel(logger, notifierCfg, receivers, n.Data)
+	if routes == nil {
+		routes = []*route{{receivers: receivers, data: n.Data}}
+	}
+
+	if acked {
+		for _, r := range routes {
+			r.receivers = downgradeReceivers(r.receivers, ackOpts)
+		}
+	}
+
+	// The alerts routed by the label or selecting different templates are sent in separate notifications.
+	var ds []template.Data
+	var rs [][]config.Receiver
+	for _, r := range routes {
+		for _, d := range selectTemplate(notifierCfg, r.data) {
+			ds = append(ds, d)
+			rs = append(rs, r.receivers)
+		}
+	}
+
+	traceRoutes(ds, rs)
+
+	if len(ds) == 1 {
+		n.Data = ds[0]
+		n.partition(logger, rs[0], notifierCfg)
 		return n
 	}
 
+	for i := range ds {
+		p := &Notification{Data: ds[i]}
+		p.partition(logger, rs[i], notifierCfg)
+		n.partitions = append(n.partitions, p)
+	}
+
+	return n
+}
+
+// Create the notifiers of the receivers, the receivers which only receive the alerts of a status get the alerts of
+// the status in a separate notification, so every alert is delivered to a receiver once.
+func (n *Notification) partition(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) {
+
+	var all []config.Receiver
+	receiversOfStatus := make(map[string][]config.Receiver)
+	for _, r := range receivers {
+		status := r.GetAlertStatus()
+		if status == string(model.AlertFiring) || status == string(model.AlertResolved) {
+			receiversOfStatus[status] = append(receiversOfStatus[status], r)
+		} else {
+			all = append(all, r)
+		}
+	}
+
+	if len(all) > 0 {
+		n.Notifiers = newNotifiers(logger, all, notifierCfg)
+	}
+
+	for _, status := range []string{string(model.AlertFiring), string(model.AlertResolved)} {
+		if len(receiversOfStatus[status]) == 0 {
+			continue
+		}
+
+		d := dataOfStatus(n.Data, status)
+		if len(d.Alerts) == 0 {
+			continue
+		}
+
+		n.partitions = append(n.partitions, &Notification{
+			Notifiers: newNotifiers(logger, receiversOfStatus[status], notifierCfg),
+			Data:      d,
+		})
+	}
+}
+
+// Create the notifiers of the receivers, the receivers limiting the annotation length get the notifiers
+// shared with the receivers of the same limit, which truncate the annotations before notifying.
+// The receivers limiting the notifications in flight get their own notifiers, so they are limited
+// independent of the other receivers of the same type. So do the degraded receivers, so that they recover
+// once a notification is delivered to them.
+func newNotifiers(logger log.Logger, receivers []config.Receiver, notifierCfg *config.Config) []notifier.Notifier {
+
+	var notifiers []notifier.Notifier
+	var shared []config.Receiver
+	truncated := make(map[int][]config.Receiver)
+	for _, r := range receivers {
+		key := receiverKey(r)
+		degraded := isDegraded(key)
+		if r.GetMaxInFlight() > 0 || degraded {
+			nf := newReceiverNotifier(logger, r, notifierCfg)
+			if nf == nil {
+				continue
+			}
+
+			if r.GetMaxInFlight() > 0 {
+				nf = &limitedNotifier{Notifier: nf, logger: logger, key: key, limit: r.GetMaxInFlight()}
+			}
+			if degraded {
+				nf = &recoveringNotifier{Notifier: nf, key: key}
+			}
+			notifiers = append(notifiers, nf)
+			continue
+		}
+
+		if l := r.GetAnnotationMaxLength(); l > 0 {
+			truncated[l] = append(truncated[l], r)
+			continue
+		}
+		shared = append(shared, r)
+	}
+
+	for l, rs := range truncated {
+		for _, f := range factories {
+			if f == nil {
+				continue
+			}
+			if nf := f(logger, rs, notifierCfg); nf != nil {
+				notifiers = append(notifiers, &truncatedNotifier{Notifier: nf, maxLength: l})
+			}
+		}
+	}
+
+	if len(shared) == 0 {
+		return notifiers
+	}
+
 	for _, f := range factories {
 		if f != nil {
-			n.Notifiers = append(n.Notifiers, f(logger, receivers, notifierCfg))
+			notifiers = append(notifiers, f(logger, shared, notifierCfg))
 		}
 	}
 
-	return n
+	return notifiers
+}
+
+// The alerts of the status in the data, the status of the data is set to the status.
+func dataOfStatus(data template.Data, status string) template.Data {
+
+	d := notifier.FilterByStatus(data, status)
+	d.Status = status
+	return d
 }
 
 func (n *Notification) Notify(ctx context.Context) []error {
 
+	if n.err != nil {
+		return []error{n.err}
+	}
+
 	group := async.NewGroup(ctx)
 	for _, notify := range n.Notifiers {
 		if notify != nil {
@@ -70,5 +281,12 @@ func (n *Notification) Notify(ctx context.Context) []error {
 		}
 	}
 
+	for _, partition := range n.partitions {
+		p := partition
+		group.Add(func(stopCh chan interface{}) {
+			stopCh <- p.Notify(ctx)
+		})
+	}
+
 	return group.Wait()
 }
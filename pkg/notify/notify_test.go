@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestRouteByStatus(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		statuses []string
+		// The statuses of the alerts received by the receivers, and the status of the notification.
+		wantAll      []string
+		wantFiring   []string
+		wantResolved []string
+	}{
+		{
+			name:         "mixed",
+			statuses:     []string{"firing", "resolved", "firing"},
+			wantAll:      []string{"firing", "firing", "resolved", "firing"},
+			wantFiring:   []string{"firing", "firing", "firing"},
+			wantResolved: []string{"resolved", "resolved"},
+		},
+		{
+			name:       "firing only",
+			statuses:   []string{"firing"},
+			wantAll:    []string{"firing", "firing"},
+			wantFiring: []string{"firing", "firing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all, firing, resolved := newWebhookServer(t), newWebhookServer(t), newWebhookServer(t)
+			cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+				TemplateFiles: testTemplateFiles(t),
+			}}}
+
+			firingHook := newTestWebhook("firing", firing.URL)
+			firingHook.SetAlertStatus("firing")
+			resolvedHook := newTestWebhook("resolved", resolved.URL)
+			resolvedHook.SetAlertStatus("resolved")
+
+			var alerts []template.Alert
+			for i, status := range tt.statuses {
+				alerts = append(alerts, testAlert(string(rune('a'+i)), status))
+			}
+
+			n := NewNotification(log.NewNopLogger(), []config.Receiver{newTestWebhook("all", all.URL), firingHook, resolvedHook},
+				cfg, testGroup(tt.name, alerts...))
+			if errs := n.Notify(context.Background()); len(errs) != 0 {
+				t.Fatal(errs)
+			}
+
+			for name, c := range map[string]struct {
+				s    *webhookServer
+				want []string
+			}{
+				"all":      {all, tt.wantAll},
+				"firing":   {firing, tt.wantFiring},
+				"resolved": {resolved, tt.wantResolved},
+			} {
+				if got := receivedStatuses(c.s.notifications()); !reflect.DeepEqual(got, c.want) {
+					t.Fatalf("%s: received %v, want %v", name, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+// The status of each notification followed by the statuses of its alerts.
+func receivedStatuses(ds []template.Data) []string {
+
+	var statuses []string
+	for _, d := range ds {
+		statuses = append(statuses, d.Status)
+		for _, a := range d.Alerts {
+			statuses = append(statuses, a.Status)
+		}
+	}
+
+	return statuses
+}
@@ -0,0 +1,204 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	json "github.com/json-iterator/go"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"regexp"
+	"strings"
+)
+
+const (
+	TruncatedMarker           = "... [truncated]"
+	TruncatedAlertsAnnotation = "truncatedAlerts"
+	DefaultNotificationLabel  = "notifications"
+)
+
+// The values of the notification label which disable the notification.
+var disabledValues = []string{"disabled", "false", "off", "no", "0"}
+
+// Preprocess the alerts before they are sent to the notifiers, so that all notifiers are protected.
+func preprocess(logger log.Logger, opts *v1alpha1.Options, data template.Data) template.Data {
+
+	notificationLabel := DefaultNotificationLabel
+	if opts != nil && opts.Global != nil && len(opts.Global.NotificationLabel) > 0 {
+		notificationLabel = opts.Global.NotificationLabel
+	}
+	data = dropDisabled(logger, data, notificationLabel)
+
+	if opts == nil || opts.Global == nil {
+		return data
+	}
+
+	if len(opts.Global.Suppressions) > 0 {
+		data = suppress(logger, data, opts.Global.Suppressions)
+	}
+
+	if d := opts.Global.Dedup; d != nil && d.Enabled {
+		data = dropDuplicate(logger, data, d)
+	}
+
+	// Nothing will be sent, so the state of the reason is kept for the next notification of the group.
+	if len(data.Alerts) == 0 {
+		return data
+	}
+
+	if opts.Global.AnnotationMaxLength > 0 {
+		data = truncateAnnotations(data, opts.Global.AnnotationMaxLength)
+	}
+
+	if opts.Global.AlertsMaxSize > 0 {
+		data = truncateAlerts(logger, data, opts.Global.AlertsMaxSize)
+	}
+
+	if opts.Global.NotificationReason {
+		data = setReason(data)
+	}
+
+	if l := opts.Global.SourceLink; l != nil && len(l.Regex) > 0 {
+		data = rewriteGeneratorURL(logger, data, l.Regex, l.Replacement)
+	}
+
+	return data
+}
+
+// Replace the part of the GeneratorURL matched by `regex` with `replacement`, such as replacing an internal host.
+func rewriteGeneratorURL(logger log.Logger, data template.Data, regex, replacement string) template.Data {
+
+	r, err := regexp.Compile(regex)
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "compile the regex of source link error", "regex", regex, "error", err.Error())
+		return data
+	}
+
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		if len(alert.GeneratorURL) > 0 {
+			alert.GeneratorURL = r.ReplaceAllString(alert.GeneratorURL, replacement)
+		}
+		alerts = append(alerts, alert)
+	}
+	data.Alerts = alerts
+
+	return data
+}
+
+// truncatedNotifier truncates the annotation values to the maximum length of its receivers before notifying,
+// the global maximum length has been applied when preprocessing.
+type truncatedNotifier struct {
+	notifier.Notifier
+	maxLength int
+}
+
+func (n *truncatedNotifier) Notify(ctx context.Context, data template.Data) []error {
+	return n.Notifier.Notify(ctx, truncateAnnotations(data, n.maxLength))
+}
+
+// Drop the alerts whose label or annotation `key` disables the notification.
+func dropDisabled(logger log.Logger, data template.Data, key string) template.Data {
+
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		v, ok := alert.Labels[key]
+		if !ok {
+			v, ok = alert.Annotations[key]
+		}
+
+		if ok && isDisabled(v) {
+			traceDropped(template.Alerts{alert}, fmt.Sprintf("disabled by %s=%s", key, v))
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if dropped := len(data.Alerts) - len(alerts); dropped > 0 {
+		_ = level.Debug(logger).Log("msg", "drop the alerts disabled by label", "label", key, "dropped", dropped)
+		stats.GetCounters().Add("label_disabled", dropped)
+		data.Alerts = alerts
+	}
+
+	return data
+}
+
+func isDisabled(value string) bool {
+
+	for _, v := range disabledValues {
+		if strings.EqualFold(strings.TrimSpace(value), v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Truncate the annotation values longer than `maxLength`.
+func truncateAnnotations(data template.Data, maxLength int) template.Data {
+
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		var annotations template.KV
+		for k, v := range alert.Annotations {
+			if len([]rune(v)) <= maxLength {
+				continue
+			}
+
+			if annotations == nil {
+				annotations = copyKV(alert.Annotations)
+			}
+			annotations[k] = string([]rune(v)[:maxLength]) + TruncatedMarker
+		}
+
+		if annotations != nil {
+			alert.Annotations = annotations
+		}
+		alerts = append(alerts, alert)
+	}
+
+	data.Alerts = alerts
+	return data
+}
+
+// Drop the alerts exceeding the `maxSize`, the size of an alert is the length of it in json.
+// At least one alert will be kept.
+func truncateAlerts(logger log.Logger, data template.Data, maxSize int) template.Data {
+
+	size := 0
+	for i, alert := range data.Alerts {
+		bs, err := json.Marshal(alert)
+		if err != nil {
+			continue
+		}
+
+		size += len(bs)
+		if size <= maxSize || i == 0 {
+			continue
+		}
+
+		truncated := len(data.Alerts) - i
+		_ = level.Warn(logger).Log("msg", "alerts are too large, truncate them", "truncated", truncated)
+		traceDropped(data.Alerts[i:], fmt.Sprintf("truncated, the alerts exceed the max size %d", maxSize))
+
+		data.Alerts = data.Alerts[:i]
+		data.CommonAnnotations = copyKV(data.CommonAnnotations)
+		data.CommonAnnotations[TruncatedAlertsAnnotation] = fmt.Sprintf("%d", truncated)
+		break
+	}
+
+	return data
+}
+
+func copyKV(kv template.KV) template.KV {
+
+	m := template.KV{}
+	for k, v := range kv {
+		m[k] = v
+	}
+
+	return m
+}
@@ -0,0 +1,307 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func testAlert(name, status string, labels ...string) template.Alert {
+
+	kv := template.KV{"alertname": "KubePodCrashLooping", "namespace": "default", "pod": name}
+	for i := 0; i+1 < len(labels); i += 2 {
+		kv[labels[i]] = labels[i+1]
+	}
+
+	return template.Alert{
+		Status:      status,
+		Labels:      kv,
+		Annotations: template.KV{"message": name + " is crash looping"},
+		StartsAt:    time.Unix(1600000000, 0),
+		Fingerprint: name,
+	}
+}
+
+// The data of a group, the receiver identifies the group so the cases do not share the states.
+func testGroup(receiver string, alerts ...template.Alert) template.Data {
+
+	return template.Data{
+		Receiver:     receiver,
+		Status:       "firing",
+		GroupLabels:  template.KV{"alertname": "KubePodCrashLooping"},
+		CommonLabels: template.KV{"alertname": "KubePodCrashLooping", "namespace": "default"},
+		Alerts:       alerts,
+	}
+}
+
+type webhookServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	received []template.Data
+}
+
+func newWebhookServer(t *testing.T) *webhookServer {
+
+	s := &webhookServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data template.Data
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+		s.mutex.Lock()
+		s.received = append(s.received, data)
+		s.mutex.Unlock()
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *webhookServer) notifications() []template.Data {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]template.Data(nil), s.received...)
+}
+
+func newTestWebhook(name, url string) *config.Webhook {
+
+	w := config.NewWebhookReceiver().(*config.Webhook)
+	w.SetNamespace(name)
+	w.WebhookConfig = &config.WebhookConfig{URL: url, HttpConfig: &v1alpha1.HTTPClientConfig{}}
+	return w
+}
+
+// The template files of the tests, the notifiers without receivers of their types may still render the default template.
+func testTemplateFiles(t *testing.T) []string {
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(`{{ define "nm.default.text" }}{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return []string{file}
+}
+
+func TestTruncateAnnotations(t *testing.T) {
+
+	long := testAlert("a", "firing")
+	long.Annotations["message"] = strings.Repeat("x", 100)
+
+	tests := []struct {
+		name      string
+		maxLength int
+		alert     template.Alert
+		want      string
+	}{
+		{"oversized annotation", 10, long, strings.Repeat("x", 10) + TruncatedMarker},
+		{"annotation within the limit", 100, long, strings.Repeat("x", 100)},
+		{"runes are not split", 2, testAlertWithMessage("告警内容"), "告警" + TruncatedMarker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := preprocess(log.NewNopLogger(), &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{AnnotationMaxLength: tt.maxLength}},
+				testGroup("truncate", tt.alert))
+
+			got := data.Alerts[0].Annotations["message"]
+			if got != tt.want {
+				t.Fatalf("message = %q, want %q", got, tt.want)
+			}
+			if !strings.HasSuffix(tt.want, "[truncated]") && strings.Contains(got, "[truncated]") {
+				t.Fatal("expect the annotation within the limit not marked")
+			}
+		})
+	}
+
+	// The annotations of the alerts received are not modified.
+	if len(long.Annotations["message"]) != 100 {
+		t.Fatal("expect the original annotation kept")
+	}
+}
+
+func testAlertWithMessage(message string) template.Alert {
+
+	a := testAlert("a", "firing")
+	a.Annotations["message"] = message
+	return a
+}
+
+func TestTruncateAlerts(t *testing.T) {
+
+	var alerts template.Alerts
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		alerts = append(alerts, testAlert(name, "firing"))
+	}
+	bs, err := json.Marshal(alerts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(bs)
+
+	tests := []struct {
+		name          string
+		maxSize       int
+		wantAlerts    int
+		wantTruncated string
+	}{
+		{"oversized alert list", size*2 + 1, 2, "3"},
+		{"alert list within the limit", size * 5, 5, ""},
+		{"the first alert is always kept", 1, 1, "4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := preprocess(log.NewNopLogger(), &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{AlertsMaxSize: tt.maxSize}},
+				testGroup("truncate", alerts...))
+
+			if len(data.Alerts) != tt.wantAlerts {
+				t.Fatalf("got %d alerts, want %d", len(data.Alerts), tt.wantAlerts)
+			}
+			if got := data.CommonAnnotations[TruncatedAlertsAnnotation]; got != tt.wantTruncated {
+				t.Fatalf("truncated alerts = %q, want %q", got, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestReceiverAnnotationMaxLength(t *testing.T) {
+
+	s := newWebhookServer(t)
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		TemplateFiles:       testTemplateFiles(t),
+		AnnotationMaxLength: 20,
+	}}}
+
+	hook := newTestWebhook("global", s.URL)
+	limited := newTestWebhook("limited", s.URL)
+	limited.SetAnnotationMaxLength(5)
+
+	n := NewNotification(log.NewNopLogger(), []config.Receiver{hook, limited}, cfg,
+		testGroup("receiver", testAlertWithMessage(strings.Repeat("x", 30))))
+	if errs := n.Notify(context.Background()); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	var got []string
+	for _, d := range s.notifications() {
+		got = append(got, d.Alerts[0].Annotations["message"])
+	}
+	if len(got) != 2 {
+		t.Fatalf("expect 2 notifications, got %d", len(got))
+	}
+
+	want := map[string]bool{
+		strings.Repeat("x", 20) + TruncatedMarker: true,
+		strings.Repeat("x", 5) + TruncatedMarker:  true,
+	}
+	for _, m := range got {
+		if !want[m] {
+			t.Fatalf("unexpected message %q", m)
+		}
+		delete(want, m)
+	}
+}
+
+func TestDropDisabled(t *testing.T) {
+
+	disabledByAnnotation := testAlert("d", "firing")
+	disabledByAnnotation.Annotations[DefaultNotificationLabel] = "Off"
+
+	tests := []struct {
+		name  string
+		label string
+		alert template.Alert
+		drop  bool
+	}{
+		{"disabled by label", "", testAlert("a", "firing", DefaultNotificationLabel, "disabled"), true},
+		{"disabled by annotation", "", disabledByAnnotation, true},
+		{"value trimmed and case insensitive", "", testAlert("a", "firing", DefaultNotificationLabel, " FALSE "), true},
+		{"enabled", "", testAlert("a", "firing", DefaultNotificationLabel, "on"), false},
+		{"custom label", "notify", testAlert("a", "firing", "notify", "no"), true},
+		{"default label ignored with a custom label", "notify", testAlert("a", "firing", DefaultNotificationLabel, "no"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{NotificationLabel: tt.label}}
+			before := stats.GetCounters().Values()["label_disabled"]
+
+			data := preprocess(log.NewNopLogger(), opts, testGroup("disabled", tt.alert, testAlert("b", "firing")))
+
+			want := 2
+			if tt.drop {
+				want = 1
+			}
+			if len(data.Alerts) != want {
+				t.Fatalf("expect %d alerts, got %d", want, len(data.Alerts))
+			}
+			if got := stats.GetCounters().Values()["label_disabled"] - before; got != int64(2-want) {
+				t.Fatalf("expect %d alerts counted, got %d", 2-want, got)
+			}
+		})
+	}
+}
+
+func TestAllAlertsDisabled(t *testing.T) {
+
+	s := newWebhookServer(t)
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TemplateFiles: testTemplateFiles(t)}}}
+
+	n := NewNotification(log.NewNopLogger(), []config.Receiver{newTestWebhook("disabled", s.URL)}, cfg,
+		testGroup("disabled", testAlert("a", "firing", DefaultNotificationLabel, "off")))
+	if errs := n.Notify(context.Background()); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if got := len(s.notifications()); got != 0 {
+		t.Fatalf("expect nothing sent, got %d notifications", got)
+	}
+}
+
+func TestRewriteGeneratorURL(t *testing.T) {
+
+	withURL := func(u string) template.Alert {
+		a := testAlert("a", "firing")
+		a.GeneratorURL = u
+		return a
+	}
+
+	tests := []struct {
+		name        string
+		regex       string
+		replacement string
+		url         string
+		want        string
+	}{
+		{"internal host replaced", `^http://prometheus-k8s\.monitoring\.svc:9090`, "https://prometheus.example.com", "http://prometheus-k8s.monitoring.svc:9090/graph?g0.expr=up",
+			"https://prometheus.example.com/graph?g0.expr=up"},
+		{"capture group", `^http://([a-z-]+)\.svc`, "https://$1.example.com", "http://thanos.svc/graph", "https://thanos.example.com/graph"},
+		{"not matched", `^http://prometheus-k8s`, "https://prometheus.example.com", "http://thanos/graph", "http://thanos/graph"},
+		{"empty url kept", `.*`, "https://prometheus.example.com", "", ""},
+		{"invalid regex", `(`, "https://prometheus.example.com", "http://prometheus/graph", "http://prometheus/graph"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{SourceLink: &v1alpha1.SourceLink{Regex: tt.regex, Replacement: tt.replacement}}}
+			data := preprocess(log.NewNopLogger(), opts, testGroup("rewrite", withURL(tt.url)))
+			if got := data.Alerts[0].GeneratorURL; got != tt.want {
+				t.Fatalf("GeneratorURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
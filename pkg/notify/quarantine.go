@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"strings"
+	"time"
+)
+
+const (
+	TargetQuarantinedAlertName = "TargetQuarantined"
+)
+
+func init() {
+	notifier.OnTargetQuarantined(notifyTargetQuarantined)
+}
+
+// Send a notification about the quarantined target to the receivers of the administrators, with the last error of it.
+// A target is quarantined at most once in the cooldown, so the notifications are not rate limited.
+func notifyTargetQuarantined(logger log.Logger, notifierCfg *config.Config, e *notifier.QuarantinedError) {
+
+	opts := notifier.TargetQuarantine(notifierCfg.ReceiverOpts)
+	if opts == nil || len(opts.Receiver) == 0 {
+		return
+	}
+
+	parts := strings.SplitN(e.Target, "/", 2)
+	for len(parts) < 2 {
+		parts = append(parts, "")
+	}
+
+	labels := template.KV{
+		model.AlertNameLabel: TargetQuarantinedAlertName,
+		"severity":           "warning",
+		"notifier":           parts[0],
+		"target":             parts[1],
+	}
+
+	data := metaNotificationData(opts.Receiver, labels, e.Error())
+	data.Alerts[0].Annotations["error"] = e.Reason
+	data.Alerts[0].Annotations["until"] = e.Until.Format(time.RFC3339)
+	data.CommonAnnotations["error"] = e.Reason
+	data.CommonAnnotations["until"] = e.Until.Format(time.RFC3339)
+
+	if sendMeta(logger, notifierCfg, opts.Receiver, data) {
+		stats.GetCounters().Add("target_quarantine_notified", 1)
+	}
+}
+
+// ReleaseTargets releases the quarantined targets of the receiver when it is changed, or its config is changed,
+// such as the smarthost of the email config is fixed. The key is in form of `type/namespace/name`.
+func ReleaseTargets(_ string, r config.Receiver) {
+
+	if e, ok := r.(*config.Email); ok && e != nil {
+		for _, address := range e.To {
+			notifier.GetQuarantine().Release(notifier.TargetKey("Email", address))
+		}
+	}
+}
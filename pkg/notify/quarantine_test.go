@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+)
+
+func TestTargetQuarantinedNotified(t *testing.T) {
+
+	sent := captureMetaNotifications(t)
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		TargetQuarantine: &v1alpha1.TargetQuarantine{Threshold: 2, Cooldown: time.Hour, Receiver: "admin"},
+	}}}
+
+	key := notifier.TargetKey("Email", "full@example.com")
+	defer notifier.GetQuarantine().Release(key)
+
+	healthy := notifier.TargetKey("Email", "ops@example.com")
+	defer notifier.GetQuarantine().Release(healthy)
+
+	for i := 0; i < 3; i++ {
+		notifier.GetQuarantine().Record(log.NewNopLogger(), cfg, key, fmt.Errorf("550 5.2.2 mailbox full"))
+		notifier.GetQuarantine().Record(log.NewNopLogger(), cfg, healthy, nil)
+	}
+
+	notifications := sent()
+	if len(notifications) != 1 {
+		t.Fatalf("expect 1 notification, got %d", len(notifications))
+	}
+
+	data := notifications[0]
+	if data.CommonLabels["target"] != "full@example.com" || data.CommonLabels["notifier"] != "Email" {
+		t.Fatalf("unexpected labels %v", data.CommonLabels)
+	}
+	if !strings.Contains(data.CommonAnnotations["error"], "mailbox full") {
+		t.Fatalf("expect the error of the target, got %s", data.CommonAnnotations["error"])
+	}
+	if err := notifier.GetQuarantine().Check(healthy); err != nil {
+		t.Fatalf("expect the healthy target not quarantined, got %s", err)
+	}
+}
+
+func TestReleaseTargetsOnReceiverChange(t *testing.T) {
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		TargetQuarantine: &v1alpha1.TargetQuarantine{Threshold: 1, Cooldown: time.Hour},
+	}}}
+
+	key := notifier.TargetKey("Email", "changed@example.com")
+	defer notifier.GetQuarantine().Release(key)
+
+	notifier.GetQuarantine().Record(log.NewNopLogger(), cfg, key, fmt.Errorf("550 5.1.1 no such user"))
+	if err := notifier.GetQuarantine().Check(key); err == nil {
+		t.Fatal("expect the target quarantined")
+	}
+
+	ReleaseTargets("email/default/ops", config.NewEmail([]string{"changed@example.com"}))
+	if err := notifier.GetQuarantine().Check(key); err != nil {
+		t.Fatalf("expect the target released when the receiver is changed, got %s", err)
+	}
+}
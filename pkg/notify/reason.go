@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ReasonInitialFiring = "initial-firing"
+	ReasonUpdateFiring  = "update-firing"
+	ReasonResolved      = "resolved"
+	ReasonRepeat        = "repeat"
+	// The alerts of the group went quiet and are assumed to be resolved by the stale tracker.
+	ReasonStale = "stale"
+	// The common annotation to pass the reason to the templates and the payloads.
+	ReasonAnnotation = "notificationReason"
+	// The group which is not notified in this time will be forgot.
+	groupStateExpires = time.Hour * 24
+)
+
+var groups = &groupStates{
+	states: make(map[string]*groupState),
+}
+
+// The firing alerts of each alert group notified last time, used to decide the reason of the notification.
+type groupStates struct {
+	states map[string]*groupState
+	mutex  sync.Mutex
+}
+
+type groupState struct {
+	firing   []string
+	notified time.Time
+}
+
+// Set the reason of the notification to the common annotations.
+func setReason(data template.Data) template.Data {
+
+	reason := groups.reason(groupKey(data), firingKeys(data), time.Now())
+	if reason == ReasonResolved && data.CommonAnnotations[StaleAnnotation] == "true" {
+		reason = ReasonStale
+	}
+	data.CommonAnnotations = copyKV(data.CommonAnnotations)
+	data.CommonAnnotations[ReasonAnnotation] = reason
+	return data
+}
+
+// Compare the firing alerts with the last notification of the group, and update the state of the group.
+func (g *groupStates) reason(key string, firing []string, now time.Time) string {
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for k, s := range g.states {
+		if now.Sub(s.notified) > groupStateExpires {
+			delete(g.states, k)
+		}
+	}
+
+	s, ok := g.states[key]
+	if len(firing) == 0 {
+		delete(g.states, key)
+		return ReasonResolved
+	}
+
+	g.states[key] = &groupState{
+		firing:   firing,
+		notified: now,
+	}
+
+	if !ok {
+		return ReasonInitialFiring
+	}
+
+	if strings.Join(s.firing, ",") == strings.Join(firing, ",") {
+		return ReasonRepeat
+	}
+
+	return ReasonUpdateFiring
+}
+
+// The group is identified by the receiver, the group labels and the namespace.
+func groupKey(data template.Data) string {
+
+	var pairs []string
+	for _, p := range data.GroupLabels.SortedPairs() {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", p.Name, p.Value))
+	}
+
+	return fmt.Sprintf("%s/%s/%s", data.Receiver, data.CommonLabels["namespace"], strings.Join(pairs, ","))
+}
+
+// The sorted keys of the firing alerts, the fingerprint is used as the key if it exists.
+func firingKeys(data template.Data) []string {
+
+	var keys []string
+	for _, alert := range data.Alerts.Firing() {
+		key := alert.Fingerprint
+		if len(key) == 0 {
+			key, _ = notifier.Md5key(alert.Labels.SortedPairs())
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestGroupStatesReason(t *testing.T) {
+
+	type step struct {
+		firing []string
+		// The time since the last step.
+		after time.Duration
+		want  string
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "initial firing and repeat",
+			steps: []step{
+				{firing: []string{"a"}, want: ReasonInitialFiring},
+				{firing: []string{"a"}, after: time.Hour, want: ReasonRepeat},
+			},
+		},
+		{
+			name: "alert added and resolved",
+			steps: []step{
+				{firing: []string{"a"}, want: ReasonInitialFiring},
+				{firing: []string{"a", "b"}, want: ReasonUpdateFiring},
+				{firing: []string{"b"}, want: ReasonUpdateFiring},
+				{firing: []string{"b"}, want: ReasonRepeat},
+			},
+		},
+		{
+			name: "resolved and firing again",
+			steps: []step{
+				{firing: []string{"a"}, want: ReasonInitialFiring},
+				{want: ReasonResolved},
+				{want: ReasonResolved},
+				{firing: []string{"a"}, want: ReasonInitialFiring},
+			},
+		},
+		{
+			name: "expired",
+			steps: []step{
+				{firing: []string{"a"}, want: ReasonInitialFiring},
+				{firing: []string{"a"}, after: groupStateExpires + time.Minute, want: ReasonInitialFiring},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &groupStates{states: make(map[string]*groupState)}
+			now := time.Unix(1600000000, 0)
+			for i, s := range tt.steps {
+				now = now.Add(s.after)
+				if got := g.reason("group", s.firing, now); got != s.want {
+					t.Fatalf("step %d: reason = %s, want %s", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreprocessReason(t *testing.T) {
+
+	a, b := testAlert("a", "firing"), testAlert("b", "firing")
+	resolved := testAlert("a", "resolved")
+
+	reasonOnly := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{NotificationReason: true}}
+	dedup := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{NotificationReason: true, Dedup: &v1alpha1.Dedup{Enabled: true}}}
+
+	tests := []struct {
+		name string
+		opts *v1alpha1.Options
+		// The alerts of the notifications of the group in order.
+		notifications [][]template.Alert
+		// The reasons of the notifications, empty if the notification is emptied by the preprocessing.
+		want []string
+	}{
+		{
+			name:          "repeat",
+			opts:          reasonOnly,
+			notifications: [][]template.Alert{{a}, {a}, {a, b}},
+			want:          []string{ReasonInitialFiring, ReasonRepeat, ReasonUpdateFiring},
+		},
+		{
+			name:          "resolved",
+			opts:          reasonOnly,
+			notifications: [][]template.Alert{{a}, {resolved}, {a}},
+			want:          []string{ReasonInitialFiring, ReasonResolved, ReasonInitialFiring},
+		},
+		{
+			name:          "dedup drops repeat",
+			opts:          dedup,
+			notifications: [][]template.Alert{{a}, {a}, {a, b}},
+			want:          []string{ReasonInitialFiring, "", ReasonUpdateFiring},
+		},
+		{
+			name:          "dedup keeps resolved",
+			opts:          dedup,
+			notifications: [][]template.Alert{{a}, {a}, {resolved}},
+			want:          []string{ReasonInitialFiring, "", ReasonResolved},
+		},
+		{
+			name:          "no reason",
+			opts:          &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{}},
+			notifications: [][]template.Alert{{a}, {a}},
+			want:          []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := "reason-" + tt.name
+			for i, alerts := range tt.notifications {
+				if got := reasonOf(preprocess(log.NewNopLogger(), tt.opts, testGroup(receiver, alerts...))); got != tt.want[i] {
+					t.Fatalf("notification %d: reason = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func reasonOf(data template.Data) string {
+	return data.CommonAnnotations[ReasonAnnotation]
+}
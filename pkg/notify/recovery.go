@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRecoveryInterval = time.Hour
+	RecoveryAlertName       = "ReceiverRecovered"
+)
+
+var recoveries = &metaNotificationStates{
+	notified: make(map[string]time.Time),
+}
+
+// Send a notification about the recovered receiver to the receivers of the administrators, with how long it was degraded.
+// It is sent at most once in the interval for the same receiver, so a flapping receiver does not flood them.
+// The key of the receiver is in form of `type/namespace/name`.
+func notifyRecovery(logger log.Logger, notifierCfg *config.Config, key string, degraded time.Duration) {
+
+	stats.GetCounters().Add("receiver_recovered", 1)
+	stats.GetCounters().Add("receiver_degraded_seconds", int(degraded/time.Second))
+
+	if notifierCfg == nil || notifierCfg.ReceiverOpts == nil || notifierCfg.ReceiverOpts.Global == nil {
+		return
+	}
+
+	opts := notifierCfg.ReceiverOpts.Global.RecoveryNotification
+	if opts == nil || len(opts.Receiver) == 0 {
+		return
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultRecoveryInterval
+	}
+
+	if !recoveries.allow(key, interval, time.Now()) {
+		stats.GetCounters().Add("recovery_throttled", 1)
+		return
+	}
+
+	parts := strings.SplitN(key, "/", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+
+	labels := template.KV{
+		model.AlertNameLabel: RecoveryAlertName,
+		"severity":           "info",
+		"notifier":           parts[0],
+		"namespace":          parts[1],
+		"receiver":           parts[2],
+	}
+
+	degraded = degraded.Round(time.Second)
+	data := metaNotificationData(opts.Receiver, labels, fmt.Sprintf("receiver %s recovered after degraded for %s", key, degraded))
+	data.Alerts[0].Annotations["degradedDuration"] = degraded.String()
+	data.CommonAnnotations["degradedDuration"] = degraded.String()
+
+	if sendMeta(logger, notifierCfg, opts.Receiver, data) {
+		stats.GetCounters().Add("recovery_notified", 1)
+	}
+}
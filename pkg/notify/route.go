@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"strings"
+)
+
+const (
+	DefaultReceiversLabel  = "receivers"
+	ReceiversLabelMerge    = "merge"
+	ReceiversLabelOverride = "override"
+)
+
+type route struct {
+	receivers []config.Receiver
+	data      template.Data
+}
+
+// Route the alerts to the receivers named in the receivers label, the alerts naming no known receiver
+// are sent to the receivers of the namespace. An alert can only name the global receivers and the receivers of
+// the tenants of its namespace. In `merge` mode the other alerts are also sent to the receivers
+// of the namespace. The receivers getting the same alerts share a route.
+// It returns nil if the routing is disabled or no alert names a known receiver.
+func routeByLabel(logger log.Logger, notifierCfg *config.Config, receivers []config.Receiver, data template.Data) []*route {
+
+	if !routingByLabel(notifierCfg) {
+		return nil
+	}
+
+	opts := notifierCfg.ReceiverOpts
+	label := opts.Global.ReceiversLabel.Label
+	if len(label) == 0 {
+		label = DefaultReceiversLabel
+	}
+	override := opts.Global.ReceiversLabel.Mode == ReceiversLabelOverride
+
+	var all []config.Receiver
+	alertsOfReceiver := make(map[config.Receiver][]int)
+	add := func(r config.Receiver, i int) {
+		indexes, ok := alertsOfReceiver[r]
+		if !ok {
+			all = append(all, r)
+		}
+		if len(indexes) == 0 || indexes[len(indexes)-1] != i {
+			alertsOfReceiver[r] = append(indexes, i)
+		}
+	}
+
+	routed := false
+	named := make(map[string][]config.Receiver)
+	for i, alert := range data.Alerts {
+		var ns *string
+		if v, ok := alert.Labels["namespace"]; ok && len(v) > 0 {
+			ns = &v
+		}
+
+		var rcvs []config.Receiver
+		for _, name := range strings.Split(alert.Labels[label], ",") {
+			name = strings.TrimSpace(name)
+			if len(name) == 0 {
+				continue
+			}
+
+			key := alert.Labels["namespace"] + "/" + name
+			rs, ok := named[key]
+			if !ok {
+				rs = notifierCfg.RcvsFromNameInNs(name, ns)
+				named[key] = rs
+				if len(rs) == 0 {
+					_ = level.Warn(logger).Log("msg", "skip the unknown receiver named by label", "label", label, "receiver", name,
+						"namespace", alert.Labels["namespace"])
+				}
+			}
+			rcvs = append(rcvs, rs...)
+		}
+
+		if len(rcvs) == 0 || !override {
+			for _, r := range receivers {
+				add(r, i)
+			}
+		}
+
+		for _, r := range rcvs {
+			add(r, i)
+		}
+		routed = routed || len(rcvs) > 0
+	}
+
+	if !routed {
+		return nil
+	}
+
+	var keys []string
+	routes := make(map[string]*route)
+	for _, r := range all {
+		var s []string
+		for _, i := range alertsOfReceiver[r] {
+			s = append(s, fmt.Sprint(i))
+		}
+		key := strings.Join(s, ",")
+
+		rt, ok := routes[key]
+		if !ok {
+			rt = &route{data: dataOfAlerts(data, alertsOfReceiver[r])}
+			routes[key] = rt
+			keys = append(keys, key)
+		}
+		rt.receivers = append(rt.receivers, r)
+	}
+
+	var res []*route
+	for _, key := range keys {
+		res = append(res, routes[key])
+	}
+
+	return res
+}
+
+func routingByLabel(notifierCfg *config.Config) bool {
+
+	opts := notifierCfg.ReceiverOpts
+	return opts != nil && opts.Global != nil && opts.Global.ReceiversLabel != nil
+}
+
+// The alerts of the indexes in the data, the status of the data is firing if any alert is firing.
+func dataOfAlerts(data template.Data, indexes []int) template.Data {
+
+	if len(indexes) == len(data.Alerts) {
+		return data
+	}
+
+	d := data
+	d.Status = string(model.AlertResolved)
+	d.Alerts = nil
+	for _, i := range indexes {
+		d.Alerts = append(d.Alerts, data.Alerts[i])
+		if data.Alerts[i].Status == string(model.AlertFiring) {
+			d.Status = string(model.AlertFiring)
+		}
+	}
+
+	return d
+}
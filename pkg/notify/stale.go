@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	"sync"
+	"time"
+)
+
+const (
+	// The common annotation to mark the notification as a stale notification.
+	StaleAnnotation = "stale"
+	// The common annotation to pass the message of the stale notification.
+	StaleMessageAnnotation = "staleMessage"
+	// The maximum number of the groups tracked, the group not updated for the longest time will be dropped if exceeded.
+	MaxStaleGroups     = 10000
+	staleCheckInterval = time.Second * 30
+)
+
+// StaleTracker tracks the last time each firing group is received, if a group is not updated for longer than
+// the stale timeout without being resolved, it will send a synthetic resolved notification, so that
+// the responders know the alerts went quiet.
+type StaleTracker struct {
+	logger      log.Logger
+	notifierCfg *config.Config
+	groups      map[string]*staleGroup
+	mutex       sync.Mutex
+	// The clock, it can be replaced.
+	now func() time.Time
+	// Send the stale notification to the receivers of the namespace, it can be replaced.
+	send func(ctx context.Context, ns *string, data template.Data) []error
+}
+
+type staleGroup struct {
+	namespace *string
+	data      template.Data
+	lastSeen  time.Time
+}
+
+func NewStaleTracker(logger log.Logger, notifierCfg *config.Config) *StaleTracker {
+
+	t := &StaleTracker{
+		logger:      logger,
+		notifierCfg: notifierCfg,
+		groups:      make(map[string]*staleGroup),
+		now:         time.Now,
+	}
+	t.send = t.notify
+
+	return t
+}
+
+// Track records the firing alerts of the group, the group will be removed if all the alerts are resolved.
+func (t *StaleTracker) Track(ns *string, data template.Data) {
+
+	if t.timeout() <= 0 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := groupKey(data)
+	firing := data.Alerts.Firing()
+	if len(firing) == 0 {
+		delete(t.groups, key)
+		return
+	}
+
+	data.Alerts = firing
+	t.groups[key] = &staleGroup{
+		namespace: ns,
+		data:      data,
+		lastSeen:  t.now(),
+	}
+
+	if len(t.groups) > MaxStaleGroups {
+		oldest := ""
+		for k, g := range t.groups {
+			if oldest == "" || g.lastSeen.Before(t.groups[oldest].lastSeen) {
+				oldest = k
+			}
+		}
+		delete(t.groups, oldest)
+	}
+}
+
+// Run checks the stale groups periodically until the context is done.
+func (t *StaleTracker) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(staleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Check(ctx)
+		}
+	}
+}
+
+// Check sends the stale notifications of the groups which are not updated for longer than the stale timeout.
+func (t *StaleTracker) Check(ctx context.Context) {
+
+	timeout := t.timeout()
+	now := t.now()
+
+	var stale []*staleGroup
+	t.mutex.Lock()
+	for k, g := range t.groups {
+		if timeout <= 0 || now.Sub(g.lastSeen) > timeout {
+			if timeout > 0 {
+				stale = append(stale, g)
+			}
+			delete(t.groups, k)
+		}
+	}
+	t.mutex.Unlock()
+
+	for _, g := range stale {
+		data := t.staleData(g, now)
+		_ = level.Info(t.logger).Log("msg", "StaleTracker: alerts went quiet", "group", groupKey(data), "lastSeen", g.lastSeen)
+
+		if errs := t.send(ctx, g.namespace, data); len(errs) > 0 {
+			_ = level.Error(t.logger).Log("msg", "StaleTracker: send stale notification error", "group", groupKey(data))
+		}
+	}
+}
+
+func (t *StaleTracker) notify(ctx context.Context, ns *string, data template.Data) []error {
+
+	receivers := t.notifierCfg.RcvsFromNs(ns)
+	return NewNotification(t.logger, receivers, t.notifierCfg, data).Notify(ctx)
+}
+
+// Generate the stale notification, the alerts are assumed to be resolved.
+func (t *StaleTracker) staleData(g *staleGroup, now time.Time) template.Data {
+
+	data := g.data
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		alert.Status = "resolved"
+		alert.EndsAt = now
+		alerts = append(alerts, alert)
+	}
+	data.Alerts = alerts
+	data.Status = "resolved"
+
+	msg := fmt.Sprintf("The alerts have not been updated since %s, they are assumed to be resolved.", g.lastSeen.Format(time.RFC3339))
+	opts := t.notifierCfg.ReceiverOpts
+	if opts != nil && opts.Global != nil && len(opts.Global.StaleTemplate) > 0 {
+		if tmpl, err := notifier.NewTemplate(opts.Global.TemplateFiles); err != nil {
+			_ = level.Error(t.logger).Log("msg", "StaleTracker: get template error", "error", err.Error())
+		} else if s, err := tmpl.TempleText(opts.Global.StaleTemplate, data, t.logger); err != nil {
+			_ = level.Error(t.logger).Log("msg", "StaleTracker: generate message error", "error", err.Error())
+		} else {
+			msg = s
+		}
+	}
+
+	data.CommonAnnotations = copyKV(data.CommonAnnotations)
+	data.CommonAnnotations[StaleAnnotation] = "true"
+	data.CommonAnnotations[StaleMessageAnnotation] = msg
+	return data
+}
+
+func (t *StaleTracker) timeout() time.Duration {
+
+	opts := t.notifierCfg.ReceiverOpts
+	if opts == nil || opts.Global == nil {
+		return 0
+	}
+
+	return opts.Global.StaleTimeout
+}
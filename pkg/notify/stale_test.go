@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The notifier template with the default templates in the sample config map.
+func sampleTemplate(t *testing.T) *notifier.Template {
+
+	bs, err := ioutil.ReadFile("../../config/samples/template.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cm corev1.ConfigMap
+	if err := yaml.Unmarshal(bs, &cm); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "template.tmpl")
+	if err := ioutil.WriteFile(file, []byte(cm.Data["template"]), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := notifier.NewTemplate([]string{file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tmpl
+}
+
+func TestStaleNotificationMarked(t *testing.T) {
+
+	opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{NotificationReason: true, StaleTimeout: time.Hour}}
+	tracker := NewStaleTracker(log.NewNopLogger(), &config.Config{ReceiverOpts: opts})
+	tmpl := sampleTemplate(t)
+
+	firing := testGroup("stale-marked", testAlert("a", "firing"))
+	preprocess(log.NewNopLogger(), opts, firing)
+
+	lastSeen := time.Unix(1600000000, 0)
+	stale := preprocess(log.NewNopLogger(), opts, tracker.staleData(&staleGroup{data: firing, lastSeen: lastSeen}, lastSeen.Add(2*time.Hour)))
+	if stale.Status != "resolved" || reasonOf(stale) != ReasonStale {
+		t.Fatalf("expect a resolved notification with the reason %s, got %s, %s", ReasonStale, stale.Status, reasonOf(stale))
+	}
+
+	resolved := testGroup("stale-resolved", testAlert("b", "resolved"))
+	resolved.Status = "resolved"
+	preprocess(log.NewNopLogger(), opts, testGroup("stale-resolved", testAlert("b", "firing")))
+	resolved = preprocess(log.NewNopLogger(), opts, resolved)
+	if reasonOf(resolved) != ReasonResolved {
+		t.Fatalf("reason = %s, want %s", reasonOf(resolved), ReasonResolved)
+	}
+
+	for _, name := range []string{"nm.default.text", "nm.default.html"} {
+		msg, err := tmpl.TempleText(`{{ template "`+name+`" . }}`, stale, log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"Assumed Resolved", stale.CommonAnnotations[StaleMessageAnnotation]} {
+			if !strings.Contains(msg, want) {
+				t.Errorf("expect %q in %s:\n%s", want, name, msg)
+			}
+		}
+
+		msg, err = tmpl.TempleText(`{{ template "`+name+`" . }}`, resolved, log.NewNopLogger())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(msg, "Assumed Resolved") {
+			t.Errorf("expect no stale marker in %s of the resolved notification:\n%s", name, msg)
+		}
+	}
+
+	subject, err := tmpl.TempleText(`{{ template "nm.default.subject" . }}`, stale, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(subject, "[Stale] ") {
+		t.Errorf("expect the subject marked stale, got %q", subject)
+	}
+}
+
+func TestStaleTrackerCheck(t *testing.T) {
+
+	start := time.Unix(1600000000, 0)
+
+	type step struct {
+		// The time since the start.
+		at     time.Duration
+		alerts []template.Alert
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+		// The time since the start to check.
+		checkAt time.Duration
+		want    int
+	}{
+		{
+			name:    "quiet group",
+			steps:   []step{{at: 0, alerts: []template.Alert{testAlert("a", "firing")}}},
+			checkAt: 2 * time.Hour,
+			want:    1,
+		},
+		{
+			name: "group resolved normally",
+			steps: []step{
+				{at: 0, alerts: []template.Alert{testAlert("a", "firing")}},
+				{at: 10 * time.Minute, alerts: []template.Alert{testAlert("a", "resolved")}},
+			},
+			checkAt: 2 * time.Hour,
+		},
+		{
+			name: "group updated within the timeout",
+			steps: []step{
+				{at: 0, alerts: []template.Alert{testAlert("a", "firing")}},
+				{at: 90 * time.Minute, alerts: []template.Alert{testAlert("a", "firing")}},
+			},
+			checkAt: 2 * time.Hour,
+		},
+		{
+			name:    "group within the timeout",
+			steps:   []step{{at: 0, alerts: []template.Alert{testAlert("a", "firing")}}},
+			checkAt: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{StaleTimeout: time.Hour}}
+			tracker := NewStaleTracker(log.NewNopLogger(), &config.Config{ReceiverOpts: opts})
+
+			now := start
+			tracker.now = func() time.Time { return now }
+			var sent []template.Data
+			tracker.send = func(_ context.Context, _ *string, data template.Data) []error {
+				sent = append(sent, data)
+				return nil
+			}
+
+			for _, s := range tt.steps {
+				now = start.Add(s.at)
+				tracker.Track(nil, testGroup("stale", s.alerts...))
+			}
+
+			now = start.Add(tt.checkAt)
+			tracker.Check(context.Background())
+			// The stale group is notified only once.
+			tracker.Check(context.Background())
+
+			if len(sent) != tt.want {
+				t.Fatalf("expect %d stale notifications, got %d", tt.want, len(sent))
+			}
+			for _, data := range sent {
+				if data.Status != "resolved" || data.CommonAnnotations[StaleAnnotation] != "true" {
+					t.Fatalf("expect a resolved notification marked stale, got %+v", data)
+				}
+				if !data.Alerts[0].EndsAt.Equal(now) {
+					t.Fatalf("expect the alerts ended at %s, got %s", now, data.Alerts[0].EndsAt)
+				}
+			}
+		})
+	}
+}
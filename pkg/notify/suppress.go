@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCauseTTL = time.Hour * 4
+)
+
+var causes = &causeStates{
+	states: make(map[string]map[string]time.Time),
+}
+
+// The cause alerts of each suppression, the key is the selectors of the suppression, and then the fingerprint of the alert.
+// The value is the time until which the cause alert suppresses the symptom alerts.
+type causeStates struct {
+	states map[string]map[string]time.Time
+	mutex  sync.Mutex
+}
+
+type suppressionRule struct {
+	key     string
+	cause   labels.Selector
+	symptom labels.Selector
+}
+
+// Drop the symptom alerts of the suppressions whose cause alert is firing or within the grace period.
+// The cause alerts in the notification are recorded first, so the symptom alerts in the same notification are suppressed too.
+func suppress(logger log.Logger, data template.Data, suppressions []v1alpha1.Suppression) template.Data {
+
+	now := time.Now()
+	var rules []*suppressionRule
+	for _, s := range suppressions {
+		rule, err := newSuppressionRule(s)
+		if err != nil {
+			_ = level.Error(logger).Log("msg", "parse the suppression error", "error", err.Error())
+			continue
+		}
+		rules = append(rules, rule)
+
+		for _, alert := range data.Alerts {
+			if rule.cause.Matches(labels.Set(alert.Labels)) {
+				causes.update(rule.key, alert, s, now)
+			}
+		}
+	}
+
+	var alerts template.Alerts
+	for _, alert := range data.Alerts {
+		if rule := suppressedBy(rules, alert, now); rule != nil {
+			traceDropped(template.Alerts{alert}, fmt.Sprintf("suppressed by the cause alerts %s", rule.cause.String()))
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if dropped := len(data.Alerts) - len(alerts); dropped > 0 {
+		_ = level.Debug(logger).Log("msg", "drop the alerts suppressed by the cause alerts", "dropped", dropped)
+		stats.GetCounters().Add("suppressed", dropped)
+		data.Alerts = alerts
+	}
+
+	return data
+}
+
+func newSuppressionRule(s v1alpha1.Suppression) (*suppressionRule, error) {
+
+	cause, err := metav1.LabelSelectorAsSelector(s.Cause)
+	if err != nil {
+		return nil, err
+	}
+
+	symptom, err := metav1.LabelSelectorAsSelector(s.Symptom)
+	if err != nil {
+		return nil, err
+	}
+
+	return &suppressionRule{
+		key:     fmt.Sprintf("%s/%s", cause.String(), symptom.String()),
+		cause:   cause,
+		symptom: symptom,
+	}, nil
+}
+
+// The rule suppressing the alert, an alert is suppressed if it is a symptom of a rule, but not a cause of it,
+// and the rule has an active cause alert.
+func suppressedBy(rules []*suppressionRule, alert template.Alert, now time.Time) *suppressionRule {
+
+	set := labels.Set(alert.Labels)
+	for _, rule := range rules {
+		if rule.symptom.Matches(set) && !rule.cause.Matches(set) && causes.active(rule.key, now) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// A firing cause alert suppresses the symptom alerts for the TTL since it is notified,
+// a resolved one suppresses them for the grace period since it is resolved.
+func (c *causeStates) update(key string, alert template.Alert, s v1alpha1.Suppression, now time.Time) {
+
+	until := now
+	if alert.Status == string(model.AlertResolved) {
+		if !alert.EndsAt.IsZero() && alert.EndsAt.Before(now) {
+			until = alert.EndsAt
+		}
+		until = until.Add(s.GracePeriod)
+	} else {
+		ttl := s.CauseTTL
+		if ttl <= 0 {
+			ttl = DefaultCauseTTL
+		}
+		until = until.Add(ttl)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for k, alerts := range c.states {
+		for fp, t := range alerts {
+			if now.After(t) {
+				delete(alerts, fp)
+			}
+		}
+		if len(alerts) == 0 {
+			delete(c.states, k)
+		}
+	}
+
+	if _, ok := c.states[key]; !ok {
+		c.states[key] = make(map[string]time.Time)
+	}
+	c.states[key][notifier.KvToLabelSet(alert.Labels).Fingerprint().String()] = until
+}
+
+func (c *causeStates) active(key string, now time.Time) bool {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, t := range c.states[key] {
+		if now.Before(t) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/prometheus/alertmanager/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testSuppression() v1alpha1.Suppression {
+
+	return v1alpha1.Suppression{
+		Cause:       &metav1.LabelSelector{MatchLabels: map[string]string{"alertname": "APIServerDown"}},
+		Symptom:     &metav1.LabelSelector{MatchLabels: map[string]string{"namespace": "default"}},
+		GracePeriod: 10 * time.Minute,
+	}
+}
+
+// The names of the pods of the alerts.
+func podsOf(data template.Data) []string {
+
+	var pods []string
+	for _, a := range data.Alerts {
+		pods = append(pods, a.Labels["pod"])
+	}
+
+	return pods
+}
+
+func TestSuppress(t *testing.T) {
+
+	now := time.Now()
+	resolvedCause := func(endsAt time.Time) template.Alert {
+		a := testAlert("apiserver", "resolved", "alertname", "APIServerDown")
+		a.EndsAt = endsAt
+		return a
+	}
+
+	tests := []struct {
+		name string
+		// The alerts notified before.
+		before template.Alerts
+		alerts template.Alerts
+		want   []string
+	}{
+		{
+			name:   "no cause",
+			alerts: template.Alerts{testAlert("a", "firing"), testAlert("b", "firing")},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "cause in the same notification",
+			alerts: template.Alerts{testAlert("apiserver", "firing", "alertname", "APIServerDown"), testAlert("a", "firing")},
+			want:   []string{"apiserver"},
+		},
+		{
+			name:   "cause notified before",
+			before: template.Alerts{testAlert("apiserver", "firing", "alertname", "APIServerDown")},
+			alerts: template.Alerts{testAlert("a", "firing"), testAlert("b", "resolved")},
+			want:   nil,
+		},
+		{
+			name:   "symptom of other labels",
+			before: template.Alerts{testAlert("apiserver", "firing", "alertname", "APIServerDown")},
+			alerts: template.Alerts{testAlert("a", "firing", "namespace", "kube-system")},
+			want:   []string{"a"},
+		},
+		{
+			name:   "cause resolved within the grace period",
+			before: template.Alerts{resolvedCause(now.Add(-5 * time.Minute))},
+			alerts: template.Alerts{testAlert("a", "firing")},
+			want:   nil,
+		},
+		{
+			name:   "cause resolved after the grace period",
+			before: template.Alerts{resolvedCause(now.Add(-20 * time.Minute))},
+			alerts: template.Alerts{testAlert("a", "firing")},
+			want:   []string{"a"},
+		},
+	}
+
+	suppressions := []v1alpha1.Suppression{testSuppression()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			causes = &causeStates{states: make(map[string]map[string]time.Time)}
+			if len(tt.before) > 0 {
+				suppress(log.NewNopLogger(), testGroup("suppress-cause", tt.before...), suppressions)
+			}
+
+			got := podsOf(suppress(log.NewNopLogger(), testGroup("suppress-symptom", tt.alerts...), suppressions))
+			if len(got) != len(tt.want) {
+				t.Fatalf("alerts = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("alerts = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCauseTTL(t *testing.T) {
+
+	s := testSuppression()
+	s.CauseTTL = time.Hour
+	rule, err := newSuppressionRule(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name       string
+		notifiedAt time.Time
+		active     bool
+	}{
+		{"notified within the ttl", now.Add(-30 * time.Minute), true},
+		{"resolved notification lost", now.Add(-2 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			causes = &causeStates{states: make(map[string]map[string]time.Time)}
+			causes.update(rule.key, testAlert("apiserver", "firing", "alertname", "APIServerDown"), s, tt.notifiedAt)
+			if got := suppressedBy([]*suppressionRule{rule}, testAlert("a", "firing"), now) != nil; got != tt.active {
+				t.Fatalf("suppressed = %v, want %v", got, tt.active)
+			}
+		})
+	}
+}
+
+func TestSuppressInvalidRule(t *testing.T) {
+
+	causes = &causeStates{states: make(map[string]map[string]time.Time)}
+	invalid := v1alpha1.Suppression{
+		Cause: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "alertname", Operator: "Unknown"}}},
+	}
+
+	data := testGroup("suppress-invalid", testAlert("apiserver", "firing", "alertname", "APIServerDown"), testAlert("a", "firing"))
+	got := suppress(log.NewNopLogger(), data, []v1alpha1.Suppression{invalid, testSuppression()})
+	if pods := podsOf(got); len(pods) != 1 || pods[0] != "apiserver" {
+		t.Fatalf("alerts = %v, want [apiserver]", pods)
+	}
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+)
+
+const (
+	DefaultTemplateLabel = "template"
+	TemplateModeSplit    = "split"
+	TemplateModeFirst    = "first"
+	TemplateModeMajority = "majority"
+)
+
+// Select the template of the alerts by the template label, the name is passed to the notifiers by the common annotation.
+// In `split` mode the alerts selecting different templates are split into separate notifications,
+// the alerts selecting no template are sent with the default template.
+// In the other modes the group is sent with one template, the one of the first alert or the one selected by the most alerts.
+func selectTemplate(notifierCfg *config.Config, data template.Data) []template.Data {
+
+	opts := notifierCfg.ReceiverOpts
+	if opts == nil || opts.Global == nil || opts.Global.TemplateSelector == nil {
+		return []template.Data{data}
+	}
+
+	label := opts.Global.TemplateSelector.Label
+	if len(label) == 0 {
+		label = DefaultTemplateLabel
+	}
+
+	var names []string
+	alertsOfName := make(map[string][]int)
+	for i, alert := range data.Alerts {
+		name := alert.Labels[label]
+		if _, ok := alertsOfName[name]; !ok {
+			names = append(names, name)
+		}
+		alertsOfName[name] = append(alertsOfName[name], i)
+	}
+
+	withTemplate := func(d template.Data, name string) template.Data {
+		if len(name) > 0 {
+			d.CommonAnnotations = copyKV(d.CommonAnnotations)
+			d.CommonAnnotations[notifier.TemplateAnnotation] = name
+		}
+		return d
+	}
+
+	switch opts.Global.TemplateSelector.Mode {
+	case TemplateModeFirst:
+		for _, name := range names {
+			if len(name) > 0 {
+				return []template.Data{withTemplate(data, name)}
+			}
+		}
+		return []template.Data{data}
+	case TemplateModeMajority:
+		selected, max := "", 0
+		for _, name := range names {
+			if len(name) > 0 && len(alertsOfName[name]) > max {
+				selected, max = name, len(alertsOfName[name])
+			}
+		}
+		return []template.Data{withTemplate(data, selected)}
+	default:
+		var ds []template.Data
+		for _, name := range names {
+			ds = append(ds, withTemplate(dataOfAlerts(data, alertsOfName[name]), name))
+		}
+		return ds
+	}
+}
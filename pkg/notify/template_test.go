@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+)
+
+func TestSelectTemplate(t *testing.T) {
+
+	data := testGroup("template",
+		testAlert("a", "firing", "template", "db"),
+		testAlert("b", "firing"),
+		testAlert("c", "firing", "template", "network"),
+		testAlert("d", "firing", "template", "network"),
+	)
+
+	tests := []struct {
+		name     string
+		selector *v1alpha1.TemplateSelector
+		// The selected template and the alerts of each notification.
+		want [][]string
+	}{
+		{name: "disabled", want: [][]string{{"", "a", "b", "c", "d"}}},
+		{
+			name:     "split",
+			selector: &v1alpha1.TemplateSelector{},
+			want:     [][]string{{"db", "a"}, {"", "b"}, {"network", "c", "d"}},
+		},
+		{
+			name:     "first",
+			selector: &v1alpha1.TemplateSelector{Mode: TemplateModeFirst},
+			want:     [][]string{{"db", "a", "b", "c", "d"}},
+		},
+		{
+			name:     "majority",
+			selector: &v1alpha1.TemplateSelector{Mode: TemplateModeMajority},
+			want:     [][]string{{"network", "a", "b", "c", "d"}},
+		},
+		{
+			name:     "custom label",
+			selector: &v1alpha1.TemplateSelector{Label: "pod", Mode: TemplateModeFirst},
+			want:     [][]string{{"a", "a", "b", "c", "d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TemplateSelector: tt.selector}}}
+
+			var got [][]string
+			for _, d := range selectTemplate(cfg, data) {
+				n := []string{d.CommonAnnotations[notifier.TemplateAnnotation]}
+				for _, a := range d.Alerts {
+					n = append(n, a.Labels["pod"])
+				}
+				got = append(got, n)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("selected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := data.CommonAnnotations[notifier.TemplateAnnotation]; ok {
+		t.Fatal("expect the data of the group not modified")
+	}
+}
+
+func TestSelectTemplateKeepsGroup(t *testing.T) {
+
+	data := testGroup("template", testAlert("a", "firing"))
+	data.CommonAnnotations = template.KV{"summary": "crash looping"}
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{TemplateSelector: &v1alpha1.TemplateSelector{}}}}
+
+	ds := selectTemplate(cfg, data)
+	if len(ds) != 1 || !reflect.DeepEqual(ds[0], data) {
+		t.Fatalf("expect the group sent as is, got %v", ds)
+	}
+}
@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"fmt"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/common/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultRoutingTraceSize = 1000
+)
+
+var tracer = &routingTracer{}
+
+// RoutingDecision is what happened to an alert in a notification, it is either dropped for the reason,
+// or dispatched to the receivers by the notifiers.
+type RoutingDecision struct {
+	Fingerprint string    `json:"fingerprint"`
+	AlertName   string    `json:"alertname,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Status      string    `json:"status"`
+	Time        time.Time `json:"time"`
+	// The reason the alert is dropped, empty if it is dispatched.
+	Dropped string `json:"dropped,omitempty"`
+	// The receivers matched, in form of `type/tenant/namespace`.
+	Receivers []string `json:"receivers,omitempty"`
+	// The types of the notifiers the alert is dispatched to.
+	Notifiers []string `json:"notifiers,omitempty"`
+}
+
+// The ring buffer of the recent routing decisions, nothing is recorded if it is disabled.
+type routingTracer struct {
+	enabled   bool
+	decisions []*RoutingDecision
+	next      int
+	mutex     sync.Mutex
+}
+
+// Enable or disable the tracer, the decisions recorded are kept if the size is not changed.
+func setRoutingTrace(opts *v1alpha1.RoutingTrace) {
+
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	if opts == nil || !opts.Enabled {
+		tracer.enabled = false
+		tracer.decisions = nil
+		tracer.next = 0
+		return
+	}
+
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultRoutingTraceSize
+	}
+
+	tracer.enabled = true
+	if len(tracer.decisions) != size {
+		tracer.decisions = make([]*RoutingDecision, size)
+		tracer.next = 0
+	}
+}
+
+// RoutingDecisions returns the recent decisions of the alert with the fingerprint, newest first.
+// All the recent decisions are returned if the fingerprint is empty.
+func RoutingDecisions(fingerprint string) []*RoutingDecision {
+
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	res := []*RoutingDecision{}
+	size := len(tracer.decisions)
+	for i := 1; i <= size; i++ {
+		d := tracer.decisions[(tracer.next-i+size)%size]
+		if d == nil {
+			break
+		}
+		if len(fingerprint) == 0 || d.Fingerprint == fingerprint {
+			res = append(res, d)
+		}
+	}
+
+	return res
+}
+
+// Record the alerts dropped for the reason.
+func traceDropped(alerts template.Alerts, reason string) {
+
+	if !tracer.isEnabled() {
+		return
+	}
+
+	for _, alert := range alerts {
+		d := newRoutingDecision(alert)
+		d.Dropped = reason
+		tracer.add(d)
+	}
+}
+
+// Record the receivers each alert of the routes is sent to, the routes are the alerts and the receivers of them.
+// The receivers only receiving the alerts of a status are not matched by the alerts of the other status.
+func traceRoutes(ds []template.Data, rs [][]config.Receiver) {
+
+	if !tracer.isEnabled() {
+		return
+	}
+
+	var keys []string
+	decisions := make(map[string]*RoutingDecision)
+	receivers := make(map[string]map[string]bool)
+	notifiers := make(map[string]map[string]bool)
+	for i, data := range ds {
+		for _, alert := range data.Alerts {
+			key := fingerprint(alert)
+			if _, ok := decisions[key]; !ok {
+				decisions[key] = newRoutingDecision(alert)
+				receivers[key] = make(map[string]bool)
+				notifiers[key] = make(map[string]bool)
+				keys = append(keys, key)
+			}
+
+			for _, r := range rs[i] {
+				if status := r.GetAlertStatus(); len(status) > 0 && status != alert.Status {
+					continue
+				}
+				receivers[key][describeReceiver(r)] = true
+				notifiers[key][config.ReceiverType(r)] = true
+			}
+		}
+	}
+
+	for _, key := range keys {
+		d := decisions[key]
+		d.Receivers = sortedKeys(receivers[key])
+		d.Notifiers = sortedKeys(notifiers[key])
+		if len(d.Receivers) == 0 {
+			d.Dropped = "no receiver matched"
+		}
+		tracer.add(d)
+	}
+}
+
+func (t *routingTracer) isEnabled() bool {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.enabled
+}
+
+func (t *routingTracer) add(d *RoutingDecision) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.enabled || len(t.decisions) == 0 {
+		return
+	}
+
+	t.decisions[t.next] = d
+	t.next = (t.next + 1) % len(t.decisions)
+}
+
+func newRoutingDecision(alert template.Alert) *RoutingDecision {
+
+	return &RoutingDecision{
+		Fingerprint: fingerprint(alert),
+		AlertName:   alert.Labels[model.AlertNameLabel],
+		Namespace:   alert.Labels["namespace"],
+		Status:      alert.Status,
+		Time:        time.Now(),
+	}
+}
+
+func describeReceiver(r config.Receiver) string {
+
+	ns := ""
+	if n, ok := r.(interface{ GetNamespace() string }); ok {
+		ns = n.GetNamespace()
+	}
+
+	return fmt.Sprintf("%s/%s/%s", config.ReceiverType(r), r.GetTenantID(), ns)
+}
+
+func sortedKeys(m map[string]bool) []string {
+
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
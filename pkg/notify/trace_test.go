@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// The fingerprints and the dropped reasons of the decisions.
+func describeDecisions(ds []*RoutingDecision) [][2]string {
+
+	var res [][2]string
+	for _, d := range ds {
+		res = append(res, [2]string{d.Fingerprint, d.Dropped})
+	}
+
+	return res
+}
+
+func TestRoutingTraceRing(t *testing.T) {
+
+	defer setRoutingTrace(nil)
+
+	tests := []struct {
+		name        string
+		trace       *v1alpha1.RoutingTrace
+		dropped     []string
+		fingerprint string
+		want        [][2]string
+	}{
+		{
+			name:    "disabled",
+			dropped: []string{"a", "b"},
+		},
+		{
+			name:    "newest first",
+			trace:   &v1alpha1.RoutingTrace{Enabled: true, Size: 3},
+			dropped: []string{"a", "b"},
+			want:    [][2]string{{"b", "dedup"}, {"a", "dedup"}},
+		},
+		{
+			name:    "oldest overwritten",
+			trace:   &v1alpha1.RoutingTrace{Enabled: true, Size: 2},
+			dropped: []string{"a", "b", "c"},
+			want:    [][2]string{{"c", "dedup"}, {"b", "dedup"}},
+		},
+		{
+			name:        "by fingerprint",
+			trace:       &v1alpha1.RoutingTrace{Enabled: true, Size: 3},
+			dropped:     []string{"a", "b", "a"},
+			fingerprint: "a",
+			want:        [][2]string{{"a", "dedup"}, {"a", "dedup"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The decisions recorded before are cleared.
+			setRoutingTrace(nil)
+			setRoutingTrace(tt.trace)
+
+			for _, name := range tt.dropped {
+				traceDropped(template.Alerts{testAlert(name, "firing")}, "dedup")
+			}
+			if got := describeDecisions(RoutingDecisions(tt.fingerprint)); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("decisions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceRoutes(t *testing.T) {
+
+	defer setRoutingTrace(nil)
+	setRoutingTrace(&v1alpha1.RoutingTrace{Enabled: true})
+
+	all := config.NewWebhookReceiver().(*config.Webhook)
+	all.SetNamespace("default")
+	firing := config.NewEmailReceiver().(*config.Email)
+	firing.SetNamespace("default")
+	firing.SetAlertStatus("firing")
+
+	ds := []template.Data{
+		{Alerts: template.Alerts{testAlert("a", "firing"), testAlert("b", "resolved")}},
+		{Alerts: template.Alerts{testAlert("c", "resolved")}},
+	}
+	traceRoutes(ds, [][]config.Receiver{{all, firing}, {firing}})
+
+	tests := []struct {
+		fingerprint string
+		receivers   []string
+		notifiers   []string
+		dropped     string
+	}{
+		{"a", []string{"email//default", "webhook//default"}, []string{"email", "webhook"}, ""},
+		{"b", []string{"webhook//default"}, []string{"webhook"}, ""},
+		// The receiver only receiving the firing alerts is not matched by the resolved alert.
+		{"c", nil, nil, "no receiver matched"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fingerprint, func(t *testing.T) {
+			ds := RoutingDecisions(tt.fingerprint)
+			if len(ds) != 1 {
+				t.Fatalf("expect 1 decision, got %d", len(ds))
+			}
+			d := ds[0]
+			if !reflect.DeepEqual(d.Receivers, tt.receivers) || !reflect.DeepEqual(d.Notifiers, tt.notifiers) || d.Dropped != tt.dropped {
+				t.Fatalf("unexpected decision %+v", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
+	"github.com/prometheus/alertmanager/template"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWarmUpTimeout = time.Second * 10
+	// The interval of retrying the failed warm-up, it is doubled after every failure until the maximum.
+	MinWarmUpRetryInterval = time.Second * 5
+	MaxWarmUpRetryInterval = time.Minute * 5
+)
+
+var (
+	// The warmer of the receivers, a degraded receiver recovers once a notification is delivered to it,
+	// without waiting for the warm-up retry.
+	warmer      *Warmer
+	warmerMutex sync.RWMutex
+)
+
+// Warmer warms up the notifiers of the receivers when the receivers are added or changed,
+// so that the first notification need not wait for the access token.
+// The receivers whose warm-up failed are degraded and reported as config errors, and the warm-up will be retried
+// in the background until succeeded.
+type Warmer struct {
+	logger      log.Logger
+	notifierCfg *config.Config
+	// The receivers being warmed up or degraded, in form of map[type/namespace/name]*warmUpState.
+	states map[string]*warmUpState
+	mutex  sync.Mutex
+}
+
+type warmUpState struct {
+	receiver config.Receiver
+	timer    *time.Timer
+	interval time.Duration
+	err      error
+	// The time of the first failed warm-up, the receiver is degraded since then.
+	degradedSince time.Time
+}
+
+// recoveringNotifier is the notifier of a degraded receiver, the receiver recovers once a notification is delivered.
+type recoveringNotifier struct {
+	notifier.Notifier
+	key string
+}
+
+func (n *recoveringNotifier) Notify(ctx context.Context, data template.Data) []error {
+
+	errs := n.Notifier.Notify(ctx, data)
+	for _, err := range errs {
+		if err != nil {
+			return errs
+		}
+	}
+
+	warmerMutex.RLock()
+	w := warmer
+	warmerMutex.RUnlock()
+	if w != nil {
+		w.delivered(n.key)
+	}
+
+	return errs
+}
+
+func NewWarmer(logger log.Logger, notifierCfg *config.Config) *Warmer {
+
+	w := &Warmer{
+		logger:      logger,
+		notifierCfg: notifierCfg,
+		states:      make(map[string]*warmUpState),
+	}
+
+	warmerMutex.Lock()
+	warmer = w
+	warmerMutex.Unlock()
+
+	return w
+}
+
+// Whether the receiver of the key is degraded by the warmer.
+func isDegraded(key string) bool {
+
+	warmerMutex.RLock()
+	w := warmer
+	warmerMutex.RUnlock()
+	if w == nil {
+		return false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	s, ok := w.states[key]
+	return ok && s.err != nil
+}
+
+// Update warms up the receiver asynchronously, the pending retry of the old receiver is canceled.
+// Nothing to do if the receiver is deleted.
+func (w *Warmer) Update(key string, r config.Receiver) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if s, ok := w.states[key]; ok {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		delete(w.states, key)
+	}
+
+	if r == nil || !w.enabled() {
+		return
+	}
+
+	s := &warmUpState{receiver: r}
+	w.states[key] = s
+	go w.warmUp(key, s)
+}
+
+// Degraded returns the error of the last warm-up of the degraded receivers.
+func (w *Warmer) Degraded() map[string]string {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	m := make(map[string]string)
+	for k, s := range w.states {
+		if s.err != nil {
+			m[k] = s.err.Error()
+		}
+	}
+
+	return m
+}
+
+func (w *Warmer) warmUp(key string, s *warmUpState) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultWarmUpTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, f := range factories {
+		if f == nil {
+			continue
+		}
+
+		if u, ok := f(w.logger, []config.Receiver{s.receiver}, w.notifierCfg).(notifier.WarmUpper); ok {
+			errs = append(errs, u.WarmUp(ctx)...)
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// The receiver is changed or deleted during the warm-up.
+	if w.states[key] != s {
+		return
+	}
+
+	if len(errs) == 0 {
+		w.recover(key, s)
+		return
+	}
+
+	if !w.enabled() {
+		delete(w.states, key)
+		return
+	}
+
+	// Notify the administrators when the receiver is degraded, not on every retry.
+	if s.err == nil {
+		s.degradedSince = time.Now()
+		notifier.ReportConfigError(w.logger, w.notifierCfg, config.ReceiverType(s.receiver), s.receiver,
+			fmt.Errorf("warm up error, %s", errs[0].Error()))
+	}
+
+	s.err = errs[0]
+	s.interval *= 2
+	if s.interval < MinWarmUpRetryInterval {
+		s.interval = MinWarmUpRetryInterval
+	}
+	if s.interval > MaxWarmUpRetryInterval {
+		s.interval = MaxWarmUpRetryInterval
+	}
+
+	stats.GetCounters().Add("warmup_failures", 1)
+	_ = level.Warn(w.logger).Log("msg", "Warmer: warm up error, receiver degraded", "receiver", key, "retry", s.interval.String(), "error", s.err.Error())
+
+	s.timer = time.AfterFunc(s.interval, func() {
+		w.warmUp(key, s)
+	})
+}
+
+// A notification is delivered to the receiver, it recovers if it is degraded, and the pending retry is canceled.
+func (w *Warmer) delivered(key string) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if s, ok := w.states[key]; ok && s.err != nil {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		w.recover(key, s)
+	}
+}
+
+// Clear the state of the receiver, the recovery is notified if it was degraded. It must be called holding the lock.
+func (w *Warmer) recover(key string, s *warmUpState) {
+
+	if s.err != nil {
+		degraded := time.Since(s.degradedSince)
+		_ = level.Info(w.logger).Log("msg", "Warmer: receiver recovered", "receiver", key, "degraded", degraded.String())
+		// The notifiers are created to send the notification, it must not be done while holding the lock.
+		go notifyRecovery(w.logger, w.notifierCfg, key, degraded)
+	}
+	delete(w.states, key)
+}
+
+func (w *Warmer) enabled() bool {
+
+	opts := w.notifierCfg.ReceiverOpts
+	return opts != nil && opts.Global != nil && opts.Global.WarmUp
+}
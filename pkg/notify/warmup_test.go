@@ -0,0 +1,273 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/apis/v1alpha1"
+	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/prometheus/alertmanager/template"
+)
+
+// fakeWarmUpper counts the warm-ups, and fails them if `fail` is set.
+type fakeWarmUpper struct {
+	calls *int32
+	fail  *int32
+}
+
+func (n *fakeWarmUpper) Notify(_ context.Context, _ template.Data) []error {
+	return nil
+}
+
+func (n *fakeWarmUpper) WarmUp(_ context.Context) []error {
+
+	atomic.AddInt32(n.calls, 1)
+	if atomic.LoadInt32(n.fail) != 0 {
+		return []error{fmt.Errorf("get access token error")}
+	}
+
+	return nil
+}
+
+// Register a notifier warming up the receivers for the test.
+func registerWarmUpper(t *testing.T) (calls, fail *int32) {
+
+	calls, fail = new(int32), new(int32)
+	Register("WarmUpTest", func(_ log.Logger, receivers []config.Receiver, _ *config.Config) notifier.Notifier {
+		return &fakeWarmUpper{calls: calls, fail: fail}
+	})
+	t.Cleanup(func() { delete(factories, "WarmUpTest") })
+
+	return calls, fail
+}
+
+// Wait until the condition is met or the timeout, the warm-ups run in the background.
+func waitFor(cond func() bool, timeout time.Duration) bool {
+
+	for deadline := time.Now().Add(timeout); !cond(); {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return true
+}
+
+func TestWarmerUpdate(t *testing.T) {
+
+	calls, fail := registerWarmUpper(t)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		fail    bool
+		// The number of the warm-ups expected.
+		wantCalls    int32
+		wantDegraded bool
+	}{
+		{name: "disabled", wantCalls: 0},
+		{name: "warmed up", enabled: true, wantCalls: 1},
+		{name: "failure degrades the receiver", enabled: true, fail: true, wantCalls: 1, wantDegraded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(calls, 0)
+			atomic.StoreInt32(fail, 0)
+			if tt.fail {
+				atomic.StoreInt32(fail, 1)
+			}
+
+			cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{WarmUp: tt.enabled}}}
+			w := NewWarmer(log.NewNopLogger(), cfg)
+			key := "webhook/default/warmup"
+			w.Update(key, newTestWebhook("default", "http://127.0.0.1"))
+			defer w.Update(key, nil)
+
+			if !waitFor(func() bool { return atomic.LoadInt32(calls) >= tt.wantCalls }, time.Second) {
+				t.Fatalf("expect %d warm-ups, got %d", tt.wantCalls, atomic.LoadInt32(calls))
+			}
+
+			settled := waitFor(func() bool {
+				w.mutex.Lock()
+				defer w.mutex.Unlock()
+				s, ok := w.states[key]
+				return !ok || s.err != nil
+			}, time.Second)
+			if !settled {
+				t.Fatal("expect the warm-up finished")
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Fatalf("expect %d warm-ups, got %d", tt.wantCalls, got)
+			}
+
+			_, degraded := w.Degraded()[key]
+			if degraded != tt.wantDegraded {
+				t.Fatalf("degraded = %v, want %v", degraded, tt.wantDegraded)
+			}
+			if degraded {
+				w.mutex.Lock()
+				interval := w.states[key].interval
+				w.mutex.Unlock()
+				if interval != MinWarmUpRetryInterval {
+					t.Fatalf("retry interval = %s, want %s", interval, MinWarmUpRetryInterval)
+				}
+			}
+		})
+	}
+}
+
+func TestWarmerUpdateCancelsRetry(t *testing.T) {
+
+	calls, fail := registerWarmUpper(t)
+	atomic.StoreInt32(fail, 1)
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{WarmUp: true}}}
+	w := NewWarmer(log.NewNopLogger(), cfg)
+	key := "webhook/default/warmup"
+	w.Update(key, newTestWebhook("default", "http://127.0.0.1"))
+
+	if !waitFor(func() bool { return len(w.Degraded()) == 1 }, time.Second) {
+		t.Fatal("expect the receiver degraded")
+	}
+
+	// The deleted receiver is neither degraded nor retried.
+	w.Update(key, nil)
+	if len(w.Degraded()) != 0 {
+		t.Fatalf("expect no degraded receiver after deleted, got %v", w.Degraded())
+	}
+	w.mutex.Lock()
+	_, ok := w.states[key]
+	w.mutex.Unlock()
+	if ok || atomic.LoadInt32(calls) != 1 {
+		t.Fatalf("expect the retry canceled, got %d warm-ups", atomic.LoadInt32(calls))
+	}
+}
+
+func TestWarmerReportsConfigError(t *testing.T) {
+
+	_, fail := registerWarmUpper(t)
+	atomic.StoreInt32(fail, 1)
+	sent := captureMetaNotifications(t)
+
+	cfg := &config.Config{ReceiverOpts: configErrorOptions()}
+	cfg.ReceiverOpts.Global.WarmUp = true
+	w := NewWarmer(log.NewNopLogger(), cfg)
+
+	// The receiver is degraded again after it is updated, the same error is notified once in the interval.
+	key := "webhook/warmup-config-error/warmup"
+	for i := 0; i < 2; i++ {
+		w.Update(key, newTestWebhook("warmup-config-error", "http://127.0.0.1"))
+		if !waitFor(func() bool { return len(w.Degraded()) == 1 }, time.Second) {
+			t.Fatal("expect the receiver degraded")
+		}
+	}
+	defer w.Update(key, nil)
+
+	notifications := sent()
+	if len(notifications) != 1 {
+		t.Fatalf("expect 1 notification, got %d", len(notifications))
+	}
+
+	labels := notifications[0].CommonLabels
+	if labels["namespace"] != "warmup-config-error" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+	if msg := notifications[0].CommonAnnotations["message"]; !strings.Contains(msg, "warm up error, get access token error") {
+		t.Fatalf("expect the warm-up error in the message, got %s", msg)
+	}
+
+	// Another receiver is notified separately.
+	other := "webhook/warmup-config-error-other/warmup"
+	w.Update(other, newTestWebhook("warmup-config-error-other", "http://127.0.0.1"))
+	defer w.Update(other, nil)
+	if !waitFor(func() bool { return len(sent()) == 2 }, time.Second) {
+		t.Fatalf("expect 2 notifications, got %d", len(sent()))
+	}
+}
+
+func TestDeliveryRecoversDegradedReceiver(t *testing.T) {
+
+	sent := captureMetaNotifications(t)
+
+	// The webhook fails until it is fixed.
+	var fixed int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fixed) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer s.Close()
+
+	cfg := &config.Config{ReceiverOpts: &v1alpha1.Options{Global: &v1alpha1.GlobalOptions{
+		WarmUp:               true,
+		RecoveryNotification: &v1alpha1.RecoveryNotification{Receiver: "admin", Interval: time.Hour},
+	}}}
+	w := NewWarmer(log.NewNopLogger(), cfg)
+	defer func() {
+		warmerMutex.Lock()
+		warmer = nil
+		warmerMutex.Unlock()
+	}()
+
+	hook := newTestWebhook("recovering", s.URL)
+	hook.SetName("recovering")
+	key := receiverKey(hook)
+	w.states[key] = &warmUpState{
+		receiver:      hook,
+		err:           fmt.Errorf("get access token error"),
+		degradedSince: time.Now().Add(-time.Minute),
+	}
+
+	notify := func() []error {
+		n := &Notification{
+			Notifiers: newNotifiers(log.NewNopLogger(), []config.Receiver{hook}, cfg),
+			Data:      testGroup("recovering", testAlert("a", "firing")),
+		}
+		return n.Notify(context.Background())
+	}
+
+	if errs := notify(); len(errs) == 0 {
+		t.Fatal("expect the notification to fail")
+	}
+	if !isDegraded(key) {
+		t.Fatal("expect the receiver still degraded after the failed delivery")
+	}
+
+	atomic.StoreInt32(&fixed, 1)
+	for i := 0; i < 2; i++ {
+		if errs := notify(); len(errs) != 0 {
+			t.Fatalf("expect the notification delivered, got %v", errs)
+		}
+	}
+	if isDegraded(key) {
+		t.Fatal("expect the receiver recovered after the delivery")
+	}
+
+	// The recovery is notified in the background, wait for it, and a while longer for any duplicate.
+	for deadline := time.Now().Add(time.Second); len(sent()) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	notifications := sent()
+	if len(notifications) != 1 {
+		t.Fatalf("expect 1 recovery notification, got %d", len(notifications))
+	}
+
+	data := notifications[0]
+	if data.CommonLabels["receiver"] != "recovering" || data.CommonLabels["notifier"] != "webhook" {
+		t.Fatalf("unexpected labels %v", data.CommonLabels)
+	}
+	if got := data.CommonAnnotations["degradedDuration"]; got != time.Minute.String() {
+		t.Fatalf("degraded duration = %s, want %s", got, time.Minute)
+	}
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"sync"
+)
+
+var counters *Counters
+
+// Counters counts the events by name since started, such as the alerts dropped for some reason.
+type Counters struct {
+	values map[string]int64
+	mutex  sync.Mutex
+}
+
+func init() {
+	counters = NewCounters()
+}
+
+func GetCounters() *Counters {
+	return counters
+}
+
+func NewCounters() *Counters {
+	return &Counters{
+		values: make(map[string]int64),
+	}
+}
+
+func (c *Counters) Add(name string, n int) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values[name] += int64(n)
+}
+
+// Values returns the value of each counter.
+func (c *Counters) Values() map[string]int64 {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	m := make(map[string]int64)
+	for k, v := range c.values {
+		m[k] = v
+	}
+
+	return m
+}
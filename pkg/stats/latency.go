@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWindowSize = 1000
+)
+
+var recorder *LatencyRecorder
+
+// LatencyRecorder keeps the durations of the latest sends of each notifier type in a sliding window,
+// it is used to provide a quick view of the latency without prometheus.
+type LatencyRecorder struct {
+	windowSize int
+	windows    map[string]*window
+	mutex      sync.Mutex
+}
+
+// A ring buffer of samples, the memory is bounded by the window size.
+type window struct {
+	samples []sample
+	next    int
+}
+
+type sample struct {
+	duration time.Duration
+	failed   bool
+}
+
+type Summary struct {
+	Count     int     `json:"count"`
+	P50       string  `json:"p50"`
+	P90       string  `json:"p90"`
+	P99       string  `json:"p99"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+func init() {
+	recorder = NewLatencyRecorder(DefaultWindowSize)
+}
+
+func GetLatencyRecorder() *LatencyRecorder {
+	return recorder
+}
+
+func NewLatencyRecorder(windowSize int) *LatencyRecorder {
+
+	if windowSize <= 0 {
+		windowSize = DefaultWindowSize
+	}
+
+	return &LatencyRecorder{
+		windowSize: windowSize,
+		windows:    make(map[string]*window),
+	}
+}
+
+// SetWindowSize resets the window size, the samples recorded will be dropped.
+func (r *LatencyRecorder) SetWindowSize(windowSize int) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if windowSize <= 0 || windowSize == r.windowSize {
+		return
+	}
+
+	r.windowSize = windowSize
+	r.windows = make(map[string]*window)
+}
+
+// Record the duration of a send, a send with error is treated as failed.
+func (r *LatencyRecorder) Record(notifierType string, duration time.Duration, err error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	w, ok := r.windows[notifierType]
+	if !ok {
+		w = &window{}
+		r.windows[notifierType] = w
+	}
+
+	s := sample{duration: duration, failed: err != nil}
+	if len(w.samples) < r.windowSize {
+		w.samples = append(w.samples, s)
+		return
+	}
+
+	w.samples[w.next] = s
+	w.next = (w.next + 1) % r.windowSize
+}
+
+// Summaries returns the latency summary of each notifier type.
+func (r *LatencyRecorder) Summaries() map[string]Summary {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	m := make(map[string]Summary)
+	for k, w := range r.windows {
+		m[k] = w.summary()
+	}
+
+	return m
+}
+
+func (w *window) summary() Summary {
+
+	s := Summary{Count: len(w.samples)}
+	if s.Count == 0 {
+		return s
+	}
+
+	failed := 0
+	durations := make([]time.Duration, 0, len(w.samples))
+	for _, sample := range w.samples {
+		durations = append(durations, sample.duration)
+		if sample.failed {
+			failed++
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	s.P50 = percentile(durations, 0.5).String()
+	s.P90 = percentile(durations, 0.9).String()
+	s.P99 = percentile(durations, 0.99).String()
+	s.ErrorRate = float64(failed) / float64(s.Count)
+
+	return s
+}
+
+// Calculate the percentile of the sorted durations with the nearest-rank method.
+func percentile(durations []time.Duration, p float64) time.Duration {
+
+	rank := int(math.Ceil(p * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return durations[rank-1]
+}
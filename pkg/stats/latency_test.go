@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLatencySummaries(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		windowSize int
+		// The durations in milliseconds, the negative ones are failed sends.
+		durations []int
+		want      Summary
+	}{
+		{
+			name:       "1 to 100ms",
+			windowSize: 100,
+			durations:  sequence(1, 100),
+			want:       Summary{Count: 100, P50: "50ms", P90: "90ms", P99: "99ms"},
+		},
+		{
+			name:       "unordered with failures",
+			windowSize: 10,
+			durations:  []int{-40, 10, 30, -20, 50},
+			want:       Summary{Count: 5, P50: "30ms", P90: "50ms", P99: "50ms", ErrorRate: 0.4},
+		},
+		{
+			// The window keeps the latest 10 samples, 91 to 100ms.
+			name:       "the samples beyond the window are dropped",
+			windowSize: 10,
+			durations:  append(sequence(1000, 1090), sequence(91, 100)...),
+			want:       Summary{Count: 10, P50: "95ms", P90: "99ms", P99: "100ms"},
+		},
+		{
+			name:       "single sample",
+			windowSize: 10,
+			durations:  []int{-7},
+			want:       Summary{Count: 1, P50: "7ms", P90: "7ms", P99: "7ms", ErrorRate: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewLatencyRecorder(tt.windowSize)
+			for _, d := range tt.durations {
+				var err error
+				if d < 0 {
+					d, err = -d, fmt.Errorf("send failed")
+				}
+				r.Record("email", time.Duration(d)*time.Millisecond, err)
+			}
+
+			got := r.Summaries()["email"]
+			if got != tt.want {
+				t.Fatalf("summary = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatencySummariesByType(t *testing.T) {
+
+	r := NewLatencyRecorder(10)
+	r.Record("email", time.Second, nil)
+	r.Record("slack", time.Millisecond, fmt.Errorf("send failed"))
+
+	summaries := r.Summaries()
+	if len(summaries) != 2 {
+		t.Fatalf("expect 2 notifier types, got %v", summaries)
+	}
+	if summaries["email"].P50 != "1s" || summaries["email"].ErrorRate != 0 {
+		t.Fatalf("unexpected email summary %+v", summaries["email"])
+	}
+	if summaries["slack"].P50 != "1ms" || summaries["slack"].ErrorRate != 1 {
+		t.Fatalf("unexpected slack summary %+v", summaries["slack"])
+	}
+
+	// Resizing the window drops the samples.
+	r.SetWindowSize(20)
+	if len(r.Summaries()) != 0 {
+		t.Fatal("expect the samples dropped after the window resized")
+	}
+}
+
+func sequence(from, to int) []int {
+
+	var s []int
+	for i := from; i <= to; i++ {
+		s = append(s, i)
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+var quotaCounter *QuotaCounter
+
+// QuotaCounter counts the sends of each key in a day, it is used to keep the sends within the daily quota of the providers.
+type QuotaCounter struct {
+	counters map[string]*counter
+	mutex    sync.Mutex
+	// The clock, it can be replaced.
+	now func() time.Time
+}
+
+type counter struct {
+	limit int
+	used  int
+	// The number of the sends blocked because of the quota exhausted.
+	exhausted int
+	// The start of the day the counter belongs to.
+	day time.Time
+}
+
+type QuotaSummary struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Exhausted int       `json:"quotaExhausted"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+func init() {
+	quotaCounter = NewQuotaCounter(time.Now)
+}
+
+func GetQuotaCounter() *QuotaCounter {
+	return quotaCounter
+}
+
+func NewQuotaCounter(now func() time.Time) *QuotaCounter {
+	return &QuotaCounter{
+		counters: make(map[string]*counter),
+		now:      now,
+	}
+}
+
+// Take consumes one send of the `key`, it returns false if the quota of today is exhausted.
+// The quota is reset at the midnight of `location`.
+func (q *QuotaCounter) Take(key string, limit int, location *time.Location) bool {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if location == nil {
+		location = time.Local
+	}
+
+	now := q.now().In(location)
+	y, m, d := now.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, location)
+
+	c, ok := q.counters[key]
+	if !ok || !c.day.Equal(day) {
+		c = &counter{day: day}
+		q.counters[key] = c
+	}
+	c.limit = limit
+
+	if c.used >= limit {
+		c.exhausted++
+		return false
+	}
+
+	c.used++
+	return true
+}
+
+// Summaries returns the quota usage of each key today.
+func (q *QuotaCounter) Summaries() map[string]QuotaSummary {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := q.now()
+	m := make(map[string]QuotaSummary)
+	for k, c := range q.counters {
+		s := QuotaSummary{
+			Limit:     c.limit,
+			Used:      c.used,
+			Exhausted: c.exhausted,
+			ResetAt:   nextDay(c.day),
+		}
+
+		// The day has passed, the quota has been reset.
+		if !now.Before(s.ResetAt) {
+			s.Used = 0
+			s.Exhausted = 0
+			s.ResetAt = nextDay(now.In(c.day.Location()))
+		}
+
+		s.Remaining = s.Limit - s.Used
+		if s.Remaining < 0 {
+			s.Remaining = 0
+		}
+		m[k] = s
+	}
+
+	return m
+}
+
+func nextDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
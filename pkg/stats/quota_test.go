@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuotaCounter(t *testing.T) {
+
+	utc8 := time.FixedZone("UTC+8", 8*60*60)
+	// 2020-09-13 20:00 in UTC+8.
+	start := time.Date(2020, 9, 13, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		limit int
+		// The time since the start of each send.
+		sends []time.Duration
+		want  []bool
+		// The summary after the sends.
+		wantSummary QuotaSummary
+	}{
+		{
+			name:        "under quota",
+			limit:       3,
+			sends:       []time.Duration{0, time.Minute},
+			want:        []bool{true, true},
+			wantSummary: QuotaSummary{Limit: 3, Used: 2, Remaining: 1},
+		},
+		{
+			name:        "exhausted",
+			limit:       2,
+			sends:       []time.Duration{0, time.Minute, 2 * time.Minute, 3 * time.Minute},
+			want:        []bool{true, true, false, false},
+			wantSummary: QuotaSummary{Limit: 2, Used: 2, Remaining: 0, Exhausted: 2},
+		},
+		{
+			// The quota is reset at the midnight of UTC+8, 4 hours after the start.
+			name:        "post reset",
+			limit:       1,
+			sends:       []time.Duration{0, time.Hour, 5 * time.Hour},
+			want:        []bool{true, false, true},
+			wantSummary: QuotaSummary{Limit: 1, Used: 1, Remaining: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start
+			q := NewQuotaCounter(func() time.Time { return now })
+			for i, d := range tt.sends {
+				now = start.Add(d)
+				if got := q.Take("smtp.example.com:25/alerts@example.com", tt.limit, utc8); got != tt.want[i] {
+					t.Fatalf("send %d: Take() = %v, want %v", i, got, tt.want[i])
+				}
+			}
+
+			got := q.Summaries()["smtp.example.com:25/alerts@example.com"]
+			got.ResetAt = time.Time{}
+			if got != tt.wantSummary {
+				t.Fatalf("summary = %+v, want %+v", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestQuotaSummaryAfterReset(t *testing.T) {
+
+	now := time.Date(2020, 9, 13, 23, 0, 0, 0, time.UTC)
+	q := NewQuotaCounter(func() time.Time { return now })
+	q.Take("a", 1, time.UTC)
+	q.Take("a", 1, time.UTC)
+
+	if got := q.Summaries()["a"]; got.Remaining != 0 || got.Exhausted != 1 || !got.ResetAt.Equal(time.Date(2020, 9, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected summary before reset %+v", got)
+	}
+
+	// The summary is reset without any send after the midnight.
+	now = now.Add(2 * time.Hour)
+	if got := q.Summaries()["a"]; got.Used != 0 || got.Remaining != 1 || got.Exhausted != 0 || !got.ResetAt.Equal(time.Date(2020, 9, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected summary after reset %+v", got)
+	}
+}
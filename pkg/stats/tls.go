@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"sync"
+)
+
+var tlsRecorder *TLSRecorder
+
+// TLSRecorder counts the TLS handshakes of each notifier type, it is used to verify whether the TLS sessions are resumed.
+type TLSRecorder struct {
+	counters map[string]*TLSSummary
+	mutex    sync.Mutex
+}
+
+type TLSSummary struct {
+	FullHandshakes  int64 `json:"fullHandshakes"`
+	ResumedSessions int64 `json:"resumedSessions"`
+}
+
+func init() {
+	tlsRecorder = NewTLSRecorder()
+}
+
+func GetTLSRecorder() *TLSRecorder {
+	return tlsRecorder
+}
+
+func NewTLSRecorder() *TLSRecorder {
+	return &TLSRecorder{
+		counters: make(map[string]*TLSSummary),
+	}
+}
+
+// Record a completed handshake, `resumed` means the session is resumed instead of a full handshake.
+func (r *TLSRecorder) Record(notifierType string, resumed bool) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s, ok := r.counters[notifierType]
+	if !ok {
+		s = &TLSSummary{}
+		r.counters[notifierType] = s
+	}
+
+	if resumed {
+		s.ResumedSessions++
+	} else {
+		s.FullHandshakes++
+	}
+}
+
+// Summaries returns the handshake counters of each notifier type since started.
+func (r *TLSRecorder) Summaries() map[string]TLSSummary {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	m := make(map[string]TLSSummary)
+	for k, s := range r.counters {
+		m[k] = *s
+	}
+
+	return m
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/notify"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	"github.com/prometheus/alertmanager/template"
 	"io"
 	"net/http"
@@ -20,6 +22,10 @@ type HttpHandler struct {
 	webhookTimeout time.Duration
 	wkrTimeout     time.Duration
 	notifierCfg    *config.Config
+	staleTracker   *notify.StaleTracker
+	coalescer      *notify.Coalescer
+	batcher        *notify.Batcher
+	warmer         *notify.Warmer
 }
 
 type response struct {
@@ -27,6 +33,14 @@ type response struct {
 	Message string
 }
 
+// The group to acknowledge, identified by the alertmanager receiver, the namespace and the group labels.
+type ackRequest struct {
+	Receiver    string            `json:"receiver"`
+	Namespace   string            `json:"namespace,omitempty"`
+	GroupLabels map[string]string `json:"groupLabels"`
+	By          string            `json:"by"`
+}
+
 func New(logger log.Logger, semCh chan struct{}, webhookTimeout time.Duration, wkrTimeout time.Duration, cfg *config.Config) *HttpHandler {
 	h := &HttpHandler{
 		logger:         logger,
@@ -34,7 +48,14 @@ func New(logger log.Logger, semCh chan struct{}, webhookTimeout time.Duration, w
 		webhookTimeout: webhookTimeout,
 		wkrTimeout:     wkrTimeout,
 		notifierCfg:    cfg,
+		staleTracker:   notify.NewStaleTracker(logger, cfg),
+		coalescer:      notify.NewCoalescer(logger, cfg),
+		batcher:        notify.NewBatcher(logger, cfg),
+		warmer:         notify.NewWarmer(logger, cfg),
 	}
+	cfg.OnReceiverChange(h.warmer.Update)
+	cfg.OnReceiverChange(notify.ReleaseTargets)
+	cfg.OnOptionsChange(notify.ApplyOptions)
 	return h
 }
 
@@ -48,6 +69,8 @@ func (h *HttpHandler) CreateNotificationfromAlerts(w http.ResponseWriter, r *htt
 		h.handle(w, &response{http.StatusBadRequest, err.Error()})
 		return
 	}
+	// The labels are normalized before the namespace is read, so the routing sees the canonical labels too.
+	data = notify.NormalizeLabels(h.notifierCfg.ReceiverOpts, data)
 
 	//	if alerts, err := json.MarshalIndent(data, "", "  "); err != nil {
 	//		_ = level.Error(h.logger).Log("msg", "Failed to encode alerts:", "err", err)
@@ -114,10 +137,23 @@ func (h *HttpHandler) CreateNotificationfromAlerts(w http.ResponseWriter, r *htt
 			for k, d := range dm {
 				var ns *string = nil
 				if len(k) > 0 {
-					ns = &k
+					// The namespace is kept by the stale tracker and the coalescer, so it can not point to the loop variable.
+					namespace := k
+					ns = &namespace
+				}
+				h.staleTracker.Track(ns, d)
+				if h.coalescer.Add(ns, d) {
+					continue
+				}
+				all := h.notifierCfg.RcvsFromNs(ns)
+				// The group is preprocessed once, then the receivers delaying the first notification of a new group
+				// get it from the batcher.
+				d = notify.Preprocess(h.logger, h.notifierCfg, d)
+				receivers := h.batcher.Add(all, d)
+				if len(receivers) == 0 && len(all) > 0 {
+					continue
 				}
-				receivers := h.notifierCfg.RcvsFromNs(ns)
-				n := notify.NewNotification(h.logger, receivers, h.notifierCfg, d)
+				n := notify.NewPreprocessedNotification(h.logger, receivers, h.notifierCfg, d)
 				group.Add(func(stopCh chan interface{}) {
 					stopCh <- n.Notify(ctx)
 				})
@@ -171,11 +207,97 @@ func (h *HttpHandler) CreateNotificationfromAlerts(w http.ResponseWriter, r *htt
 	h.handle(w, &response{http.StatusOK, "Notification request accepted"})
 }
 
+// AcknowledgeAlerts acknowledges an alert group, the repeat notifications of the group are marked as acknowledged
+// until it is resolved.
+func (h *HttpHandler) AcknowledgeAlerts(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	req := ackRequest{}
+	if err := jsoniter.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	if len(req.By) == 0 {
+		h.handle(w, &response{http.StatusBadRequest, "who acknowledges the alerts is unknown"})
+		return
+	}
+
+	notify.Acknowledge(req.Receiver, req.Namespace, req.GroupLabels, req.By)
+	h.handle(w, &response{http.StatusOK, "acknowledged"})
+}
+
+// RunStaleTracker checks the stale groups until the context is done.
+func (h *HttpHandler) RunStaleTracker(ctx context.Context) {
+	h.staleTracker.Run(ctx)
+}
+
+// FlushCoalescer sends the groups buffered by the coalescer, it is called when shutting down.
+func (h *HttpHandler) FlushCoalescer(ctx context.Context) {
+	h.coalescer.Flush(ctx)
+}
+
+// FlushBatcher sends the groups waiting for the first notification, it is called when shutting down.
+func (h *HttpHandler) FlushBatcher(ctx context.Context) {
+	h.batcher.Flush(ctx)
+}
+
 func (h *HttpHandler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
 	h.handle(w, &response{http.StatusOK, "metrics"})
 }
 
+// ServeLatency returns the latency summaries of the recent sends of each notifier type.
+func (h *HttpHandler) ServeLatency(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(stats.GetLatencyRecorder().Summaries(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeTLS returns the number of the full TLS handshakes and the resumed sessions of each notifier type.
+func (h *HttpHandler) ServeTLS(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(stats.GetTLSRecorder().Summaries(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeQuota returns the usage of the daily quotas.
+func (h *HttpHandler) ServeQuota(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(stats.GetQuotaCounter().Summaries(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeCounters returns the counters of the events, such as the alerts dropped.
+func (h *HttpHandler) ServeCounters(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(stats.GetCounters().Values(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeWarmUp returns the receivers degraded because of the failed warm-up, and the errors.
+func (h *HttpHandler) ServeWarmUp(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(h.warmer.Degraded(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeRoutingTrace returns the recent routing decisions of the alert with the `fingerprint`, newest first,
+// or of all the alerts if it is not given. It is empty if the routing trace is not enabled.
+func (h *HttpHandler) ServeRoutingTrace(w http.ResponseWriter, r *http.Request) {
+
+	_ = r.ParseForm()
+	bs, _ := jsoniter.MarshalIndent(notify.RoutingDecisions(r.FormValue("fingerprint")), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ServeReload reloads the template files, the notifiers will use the new template at the next send.
 func (h *HttpHandler) ServeReload(w http.ResponseWriter, r *http.Request) {
+
+	if err := notifier.ReloadTemplate(); err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, "reload template error, " + err.Error()})
+		return
+	}
+
 	h.handle(w, &response{http.StatusOK, "reload"})
 }
 
@@ -211,3 +333,17 @@ func (h *HttpHandler) GetReceivers(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(bs)
 	return
 }
+
+// GetReceiversDiff returns the changes of the receivers which are not applied yet, the secrets are masked.
+func (h *HttpHandler) GetReceiversDiff(w http.ResponseWriter, r *http.Request) {
+
+	bs, _ := jsoniter.MarshalIndent(h.notifierCfg.PendingDiff(), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ApplyReceivers makes the pending changes of the receivers take effect.
+func (h *HttpHandler) ApplyReceivers(w http.ResponseWriter, r *http.Request) {
+
+	h.notifierCfg.Apply()
+	h.handle(w, &response{http.StatusOK, "apply"})
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/kubesphere/notification-manager/pkg/notify/config"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier/email"
+	"github.com/kubesphere/notification-manager/pkg/stats"
 	whv1 "github.com/kubesphere/notification-manager/pkg/webhook/v1"
 	"net/http"
 	"time"
@@ -17,13 +20,24 @@ type Options struct {
 	WebhookTimeout string
 	WorkerTimeout  string
 	WorkerQueue    int
+	// The number of the recent sends used to calculate the latency of each notifier type.
+	LatencyWindowSize int
+	// The size of the TLS session cache shared by the notifiers.
+	TLSSessionCacheSize int
+	// The interval to retry the emails failed transiently, 0 means they are not retried.
+	EmailRetryInterval time.Duration
+	// The file persisting the emails to retry, they are in memory if it is empty.
+	EmailRetryQueuePath string
+	EmailRetryQueueSize int
+	EmailRetryMaxAge    time.Duration
 }
 
 type Webhook struct {
-	router  chi.Router
-	options *Options
-	logger  log.Logger
-	handler *whv1.HttpHandler
+	router      chi.Router
+	options     *Options
+	logger      log.Logger
+	handler     *whv1.HttpHandler
+	notifierCfg *config.Config
 }
 
 func New(logger log.Logger, notifierCfg *config.Config, o *Options) *Webhook {
@@ -31,8 +45,24 @@ func New(logger log.Logger, notifierCfg *config.Config, o *Options) *Webhook {
 	wkrTimeout, _ := time.ParseDuration(o.WorkerTimeout)
 
 	h := &Webhook{
-		options: o,
-		logger:  logger,
+		options:     o,
+		logger:      logger,
+		notifierCfg: notifierCfg,
+	}
+
+	stats.GetLatencyRecorder().SetWindowSize(h.options.LatencyWindowSize)
+	notifier.SetTLSSessionCacheSize(h.options.TLSSessionCacheSize)
+
+	if h.options.EmailRetryInterval > 0 {
+		var store email.RetryStore
+		if len(h.options.EmailRetryQueuePath) > 0 {
+			store = email.NewFileRetryStore(h.options.EmailRetryQueuePath)
+		}
+		if q, err := email.NewRetryQueue(store, h.options.EmailRetryQueueSize, h.options.EmailRetryMaxAge); err != nil {
+			_ = level.Error(logger).Log("msg", "create the email retry queue error, the failed emails are not retried", "error", err.Error())
+		} else {
+			email.SetRetryQueue(q)
+		}
 	}
 
 	semCh := make(chan struct{}, h.options.WorkerQueue)
@@ -44,12 +74,21 @@ func New(logger log.Logger, notifierCfg *config.Config, o *Options) *Webhook {
 	h.router.Use(middleware.Recoverer)
 	h.router.Use(middleware.Timeout(2 * webhookTimeout))
 	h.router.Get("/receivers", h.handler.GetReceivers)
+	h.router.Get("/receivers/diff", h.handler.GetReceiversDiff)
+	h.router.Post("/receivers/apply", h.handler.ApplyReceivers)
 	h.router.Post("/api/v2/alerts", h.handler.CreateNotificationfromAlerts)
+	h.router.Post("/alerts/ack", h.handler.AcknowledgeAlerts)
 	h.router.Get("/metrics", h.handler.ServeMetrics)
 	h.router.Get("/-/reload", h.handler.ServeReload)
 	h.router.Get("/-/ready", h.handler.ServeHealthCheck)
 	h.router.Get("/-/live", h.handler.ServeReadinessCheck)
 	h.router.Get("/status", h.handler.ServeStatus)
+	h.router.Get("/stats/latency", h.handler.ServeLatency)
+	h.router.Get("/stats/tls", h.handler.ServeTLS)
+	h.router.Get("/stats/quota", h.handler.ServeQuota)
+	h.router.Get("/stats/counters", h.handler.ServeCounters)
+	h.router.Get("/stats/warmup", h.handler.ServeWarmUp)
+	h.router.Get("/debug/routing", h.handler.ServeRoutingTrace)
 
 	return h
 }
@@ -61,6 +100,9 @@ func (h *Webhook) Run(ctx context.Context) error {
 		Handler: h.router,
 	}
 
+	go h.handler.RunStaleTracker(ctx)
+	go email.RunRetryQueue(ctx, h.logger, h.notifierCfg, h.options.EmailRetryInterval)
+
 	srvClosed := make(chan struct{})
 	go func() {
 		select {
@@ -83,5 +125,12 @@ func (h *Webhook) Run(ctx context.Context) error {
 	_ = level.Error(h.logger).Log("msg", "HTTP server exit", "err", err)
 	<-srvClosed
 
+	// Send the coalesced groups and the first notifications which are still in the window.
+	wkrTimeout, _ := time.ParseDuration(h.options.WorkerTimeout)
+	flushCtx, cancel := context.WithTimeout(context.Background(), wkrTimeout)
+	defer cancel()
+	h.handler.FlushCoalescer(flushCtx)
+	h.handler.FlushBatcher(flushCtx)
+
 	return err
 }